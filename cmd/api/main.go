@@ -4,12 +4,15 @@ import (
 	"context"
 	"log"
 	"net/http"
+	"os"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/joshu-sajeev/goqueue/common"
 	"github.com/joshu-sajeev/goqueue/internal/job"
 	"github.com/joshu-sajeev/goqueue/internal/storage/postgres"
+	"github.com/joshu-sajeev/goqueue/internal/templates"
+	"github.com/joshu-sajeev/goqueue/internal/workerapi"
 	"github.com/joshu-sajeev/goqueue/middleware"
 	"gorm.io/gorm"
 )
@@ -32,7 +35,39 @@ func main() {
 
 	jobRepo := postgres.NewJobRepository(db)
 	jobService := job.NewJobService(jobRepo)
+	if dir := os.Getenv("EMAIL_TEMPLATES_DIR"); dir != "" {
+		store, err := templates.LoadDir(dir)
+		if err != nil {
+			log.Fatal("Failed to load email templates:", err)
+		}
+		jobService.SetEmailTemplates(store)
+	}
+	// QUEUE_TTL expires jobs created without expires_at that have not run
+	// in time, e.g. "email=5m,webhooks=1h".
+	ttls, err := job.ParseQueueTTLs(os.Getenv("QUEUE_TTL"))
+	if err != nil {
+		log.Fatal("Failed to parse QUEUE_TTL:", err)
+	}
+	jobService.SetQueueTTLs(ttls)
+
+	// JOB_RETENTION is enforced by workers; the API reports on it.
+	retention, err := job.ParseRetention(os.Getenv("JOB_RETENTION"))
+	if err != nil {
+		log.Fatal("Failed to parse JOB_RETENTION:", err)
+	}
+	jobService.SetRetention(retention)
+
+	// JOB_ARCHIVE moves finished jobs to jobs_archive after a per-queue
+	// age, in the same format as JOB_RETENTION.
+	archive, err := job.ParseRetention(os.Getenv("JOB_ARCHIVE"))
+	if err != nil {
+		log.Fatal("Failed to parse JOB_ARCHIVE:", err)
+	}
+	jobService.SetArchive(archive)
 	jobHandler := job.NewJobHandler(jobService)
+	workerRepo := postgres.NewWorkerRepository(db)
+	workerService := workerapi.NewWorkerService(workerRepo, jobRepo)
+	workerHandler := workerapi.NewWorkerHandler(workerService)
 	r := gin.Default()
 
 	r.Use(middleware.TimeoutMiddleware(5*time.Second), middleware.ErrorHandler())
@@ -47,14 +82,39 @@ func main() {
 		c.JSON(http.StatusOK, gin.H{"status": "ok"})
 	})
 
+	r.POST("/workflows", jobHandler.CreateWorkflow)
+	r.POST("/batches", jobHandler.CreateBatch)
+	r.GET("/batches/:id", jobHandler.GetBatch)
+	r.POST("/sagas", jobHandler.CreateSaga)
+	r.GET("/sagas/:id", jobHandler.GetSaga)
+
+	r.GET("/queues/stats", jobHandler.QueueStats)
+	r.GET("/queues/retention/report", jobHandler.RetentionReport)
+
 	jobs := r.Group("/jobs")
 	{
 		jobs.POST("/create", jobHandler.Create)
+		jobs.POST("/batch", jobHandler.CreateJobs)
+		jobs.POST("/bulk/:action", jobHandler.CreateBulkOperation)
+		jobs.GET("/bulk/:id", jobHandler.GetBulkOperation)
 		jobs.GET("/:id", jobHandler.Get)
 		jobs.PUT("/:id/status", jobHandler.Update)
 		jobs.POST("/:id/increment", jobHandler.Increment)
 		jobs.POST("/:id/save", jobHandler.Save)
 		jobs.GET("/", jobHandler.List)
+
+		// Remote worker protocol
+		jobs.POST("/:id/ack", workerHandler.Ack)
+		jobs.POST("/:id/nack", workerHandler.Nack)
+		jobs.POST("/:id/extend", workerHandler.Extend)
+	}
+
+	workers := r.Group("/workers")
+	{
+		workers.GET("", workerHandler.List)
+		workers.POST("/register", workerHandler.Register)
+		workers.POST("/lease", workerHandler.Lease)
+		workers.POST("/:id/heartbeat", workerHandler.Heartbeat)
 	}
 	log.Println("Starting server on :8080...")
 	if err := r.Run(":8080"); err != nil {
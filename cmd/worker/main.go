@@ -5,14 +5,24 @@ import (
 	"log"
 	"os"
 	"os/signal"
+	"slices"
 	"strconv"
+	"strings"
 	"syscall"
 	"time"
 
+	"github.com/joshu-sajeev/goqueue/internal/config"
+	"github.com/joshu-sajeev/goqueue/internal/job"
 	"github.com/joshu-sajeev/goqueue/internal/pool"
 	"github.com/joshu-sajeev/goqueue/internal/storage/postgres"
+	"github.com/joshu-sajeev/goqueue/internal/templates"
+	"github.com/joshu-sajeev/goqueue/internal/worker"
 )
 
+// version is reported in the workers table. Override it at build time with
+// -ldflags "-X main.version=...".
+var version = "dev"
+
 func main() {
 	log.Println("Starting Worker...")
 
@@ -38,15 +48,167 @@ func main() {
 		maxWorkers = v
 	}
 
-	workerPool := pool.NewWorkerPool(maxWorkers, repo, queues, 1*time.Minute)
+	workerRepo := postgres.NewWorkerRepository(db)
+	workerPool := pool.NewWorkerPool(maxWorkers, repo, workerRepo, queues, 1*time.Minute)
+	workerPool.SetVersion(version)
+	workerPool.Use(worker.Logging(nil))
+
+	if d, err := time.ParseDuration(os.Getenv("SHUTDOWN_TIMEOUT")); err == nil && d > 0 {
+		workerPool.SetShutdownTimeout(d)
+	}
+
+	// WEBHOOK_SECRETS holds per-host signing secrets for webhook jobs, e.g.
+	// "hooks.example.com=secret1,api.partner.io=secret2".
+	webhooks := &worker.WebhookSender{Secrets: parsePairs(os.Getenv("WEBHOOK_SECRETS"))}
+	worker.Register(workerPool.Registry(), "webhooks", webhooks.Send)
+
+	// PAYMENT_PROVIDER selects the gateway payment jobs are charged with.
+	// Payments are recorded in the ledger so retried jobs never charge
+	// twice. No real gateway is wired up yet, so only the in-memory fake
+	// can be chosen, for development and tests; without it payment jobs
+	// fail permanently.
+	switch provider := os.Getenv("PAYMENT_PROVIDER"); provider {
+	case "":
+		log.Println("PAYMENT_PROVIDER not set, payment jobs will fail")
+	case "fake":
+		log.Println("Charging payment jobs with the fake payment provider")
+		payments := &worker.PaymentProcessor{
+			Provider: &worker.FakePaymentProvider{},
+			Ledger:   postgres.NewPaymentLedgerRepository(db),
+		}
+		worker.Register(workerPool.Registry(), "payment", payments.Process)
+	default:
+		log.Fatalf("Unknown PAYMENT_PROVIDER %q", provider)
+	}
+
+	var emailTemplates *templates.Store
+	if dir := os.Getenv("EMAIL_TEMPLATES_DIR"); dir != "" {
+		if emailTemplates, err = templates.LoadDir(dir); err != nil {
+			log.Fatal("Failed to load email templates:", err)
+		}
+		workerPool.SetEmailTemplates(emailTemplates)
+	}
+
+	ttls, err := job.ParseQueueTTLs(os.Getenv("QUEUE_TTL"))
+	if err != nil {
+		log.Fatal("Failed to parse QUEUE_TTL:", err)
+	}
+	workerPool.SetQueueTTLs(ttls)
+
+	// JOB_RETENTION deletes finished jobs after a per-queue period, e.g.
+	// "email:completed=7d,email:failed=30d".
+	retention, err := job.ParseRetention(os.Getenv("JOB_RETENTION"))
+	if err != nil {
+		log.Fatal("Failed to parse JOB_RETENTION:", err)
+	}
+	workerPool.SetRetention(retention)
+
+	// JOB_ARCHIVE moves finished jobs to jobs_archive after a per-queue
+	// age, in the same format as JOB_RETENTION.
+	archive, err := job.ParseRetention(os.Getenv("JOB_ARCHIVE"))
+	if err != nil {
+		log.Fatal("Failed to parse JOB_ARCHIVE:", err)
+	}
+	workerPool.SetArchive(archive)
+
+	if mailer := smtpMailerFromEnv(); mailer != nil {
+		mailer.Templates = emailTemplates
+		log.Printf("Sending email through %s:%d", mailer.Host, mailer.Port)
+		worker.Register(workerPool.Registry(), "email", mailer.Send)
+		worker.Register(workerPool.Registry(), "default", mailer.Send)
+	} else {
+		log.Println("SMTP_HOST not set, email jobs will only be logged")
+		mailer := &worker.LogMailer{Templates: emailTemplates}
+		worker.Register(workerPool.Registry(), "email", mailer.Send)
+		worker.Register(workerPool.Registry(), "default", mailer.Send)
+	}
+
+	// PUSH_ENDPOINTS switches queues to push delivery, e.g.
+	// "webhooks=https://svc.internal/jobs,payment=https://billing/jobs".
+	for queue, url := range parsePairs(os.Getenv("PUSH_ENDPOINTS")) {
+		mustBeAllowedQueue("PUSH_ENDPOINTS", queue)
+		log.Printf("Delivering %s jobs to %s", queue, url)
+		workerPool.Registry().Handle(queue, worker.NewPushHandler(worker.PushConfig{
+			URL:    url,
+			Secret: os.Getenv("PUSH_SIGNING_SECRET"),
+		}))
+	}
 
-	workerPool.Start()
+	// EXEC_HANDLERS runs a queue's jobs as external commands, e.g.
+	// "default=/usr/local/bin/build-report --fast". EXEC_TIMEOUT bounds
+	// each run.
+	execTimeout, _ := time.ParseDuration(os.Getenv("EXEC_TIMEOUT"))
+	for queue, command := range parsePairs(os.Getenv("EXEC_HANDLERS")) {
+		mustBeAllowedQueue("EXEC_HANDLERS", queue)
+		fields := strings.Fields(command)
+		log.Printf("Running %s jobs with %s", queue, fields[0])
+		workerPool.Registry().Handle(queue, worker.NewExecHandler(worker.ExecConfig{
+			Command: fields[0],
+			Args:    fields[1:],
+			Timeout: execTimeout,
+		}))
+	}
+
+	if err := workerPool.Start(); err != nil {
+		log.Fatal("Failed to start worker pool:", err)
+	}
 	log.Println("Worker pool active. Press Ctrl+C to stop.")
 
 	stop := make(chan os.Signal, 1)
 	signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
 	<-stop
 
+	log.Println("Draining running jobs...")
 	workerPool.Stop()
 	log.Println("Shutdown complete.")
 }
+
+// parsePairs parses comma separated key=value pairs, skipping malformed
+// entries.
+func parsePairs(v string) map[string]string {
+	pairs := map[string]string{}
+	for _, entry := range strings.Split(v, ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(entry), "=")
+		if !ok || key == "" || value == "" {
+			continue
+		}
+		pairs[key] = value
+	}
+	return pairs
+}
+
+// mustBeAllowedQueue stops the worker if queue, configured in env, is
+// not one the API accepts jobs for and the pool polls; a handler for any
+// other queue would never run.
+func mustBeAllowedQueue(env, queue string) {
+	if !slices.Contains(config.AllowedQueues, queue) {
+		log.Fatalf("%s: unknown queue %q, must be one of %v", env, queue, config.AllowedQueues)
+	}
+}
+
+// smtpMailerFromEnv builds the SMTP mailer from the SMTP_* variables, or
+// returns nil if SMTP_HOST is not set.
+func smtpMailerFromEnv() *worker.SMTPMailer {
+	host := os.Getenv("SMTP_HOST")
+	if host == "" {
+		return nil
+	}
+
+	port := 587
+	if v, err := strconv.Atoi(os.Getenv("SMTP_PORT")); err == nil && v > 0 {
+		port = v
+	}
+	startTLS := true
+	if v, err := strconv.ParseBool(os.Getenv("SMTP_STARTTLS")); err == nil {
+		startTLS = v
+	}
+
+	return &worker.SMTPMailer{
+		Host:     host,
+		Port:     port,
+		Username: os.Getenv("SMTP_USERNAME"),
+		Password: os.Getenv("SMTP_PASSWORD"),
+		From:     os.Getenv("SMTP_FROM"),
+		StartTLS: startTLS,
+	}
+}
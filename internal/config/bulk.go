@@ -0,0 +1,17 @@
+package config
+
+import "time"
+
+const (
+	// BulkPollInterval is how often workers look for bulk operations to
+	// run.
+	BulkPollInterval = 2 * time.Second
+
+	// BulkBatchSize is the number of jobs a bulk operation changes per
+	// transaction. Progress is saved after each batch.
+	BulkBatchSize = 500
+
+	// BulkOperationStaleAfter is how long a running bulk operation may go
+	// without progress before another worker takes it over.
+	BulkOperationStaleAfter = time.Minute
+)
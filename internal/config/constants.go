@@ -8,4 +8,43 @@ var (
 	JobStatusRunning   JobStatus = "running"
 	JobStatusFailed    JobStatus = "failed"
 	JobStatusCompleted JobStatus = "completed"
+	// JobStatusWaiting jobs have dependencies that have not completed yet.
+	JobStatusWaiting JobStatus = "waiting"
+	// JobStatusSkipped jobs were not run because a dependency did not
+	// complete.
+	JobStatusSkipped JobStatus = "skipped"
+	// JobStatusExpired jobs were not run because their expires_at passed
+	// while they were queued.
+	JobStatusExpired JobStatus = "expired"
+	// JobStatusCancelled jobs were cancelled before they finished.
+	JobStatusCancelled JobStatus = "cancelled"
+)
+
+// JobStatuses lists every job status.
+var JobStatuses = []JobStatus{
+	JobStatusQueued,
+	JobStatusRunning,
+	JobStatusFailed,
+	JobStatusCompleted,
+	JobStatusWaiting,
+	JobStatusSkipped,
+	JobStatusExpired,
+	JobStatusCancelled,
+}
+
+// What happens to a waiting job when one of its dependencies fails or is
+// skipped.
+const (
+	DependencyFailureFail = "fail"
+	DependencyFailureSkip = "skip"
+)
+
+// How an enqueue with a debounce key treats earlier jobs with the key.
+const (
+	// DebounceModeDebounce replaces the payload of the pending job with the
+	// key and pushes its start out by the window.
+	DebounceModeDebounce = "debounce"
+	// DebounceModeThrottle drops the enqueue if a job with the key was
+	// created within the window.
+	DebounceModeThrottle = "throttle"
 )
@@ -0,0 +1,23 @@
+package config
+
+import "time"
+
+const (
+	// PruneInterval is how often workers delete jobs that are past their
+	// queue's retention.
+	PruneInterval = 5 * time.Minute
+
+	// PruneBatchSize is the number of jobs deleted per statement, which
+	// keeps each delete's locks short.
+	PruneBatchSize = 1000
+)
+
+// RetainedStatuses are the statuses a retention period can be set for:
+// those jobs never leave on their own.
+var RetainedStatuses = []JobStatus{
+	JobStatusCompleted,
+	JobStatusFailed,
+	JobStatusSkipped,
+	JobStatusExpired,
+	JobStatusCancelled,
+}
@@ -0,0 +1,18 @@
+package config
+
+import "time"
+
+const (
+	retryBaseDelay = 10 * time.Second
+	retryMaxDelay  = 10 * time.Minute
+)
+
+// RetryBackoff returns the default delay before retrying a job that has
+// already failed attempts times: 10s doubled per attempt, capped at 10m.
+func RetryBackoff(attempts int) time.Duration {
+	d := retryBaseDelay
+	for i := 0; i < attempts && d < retryMaxDelay; i++ {
+		d *= 2
+	}
+	return min(d, retryMaxDelay)
+}
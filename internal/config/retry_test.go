@@ -0,0 +1,15 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetryBackoff(t *testing.T) {
+	assert.Equal(t, 10*time.Second, RetryBackoff(0))
+	assert.Equal(t, 20*time.Second, RetryBackoff(1))
+	assert.Equal(t, 80*time.Second, RetryBackoff(3))
+	assert.Equal(t, 10*time.Minute, RetryBackoff(20))
+}
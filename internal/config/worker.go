@@ -0,0 +1,18 @@
+package config
+
+import "time"
+
+const (
+	// WorkerHeartbeatInterval is how often a worker process refreshes its
+	// row in the workers table.
+	WorkerHeartbeatInterval = 10 * time.Second
+
+	// WorkerHeartbeatTimeout is how long a worker may go without a
+	// heartbeat before it is considered dead and its jobs are reclaimed.
+	WorkerHeartbeatTimeout = 3 * WorkerHeartbeatInterval
+
+	// DeadWorkerRemoveAfter is how long a worker that stopped sending
+	// heartbeats without deregistering stays in the workers table before
+	// the janitor removes it.
+	DeadWorkerRemoveAfter = 10 * time.Minute
+)
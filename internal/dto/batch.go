@@ -0,0 +1,45 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Batch statuses reported by BatchResponseDTO.
+const (
+	BatchStatusRunning  = "running"
+	BatchStatusFinished = "finished"
+)
+
+// BatchCreateDTO is a group of jobs created together and tracked as one
+// batch.
+type BatchCreateDTO struct {
+	Jobs []JobCreateDTO `json:"jobs" validate:"required,min=1,max=10000,dive"`
+
+	// OnComplete is queued once every job in the batch has completed or
+	// failed. OnFailure is queued as soon as the first job fails.
+	OnComplete *BatchCallbackDTO `json:"on_complete,omitempty"`
+	OnFailure  *BatchCallbackDTO `json:"on_failure,omitempty"`
+}
+
+// BatchCallbackDTO is a job queued when a batch reaches a milestone. If its
+// payload is a JSON object, the batch's counts are added under "batch".
+type BatchCallbackDTO struct {
+	Queue      string          `json:"queue" validate:"required"`
+	Payload    json.RawMessage `json:"payload" validate:"required"`
+	MaxRetries int             `json:"max_retries" validate:"gte=0,lte=20"`
+}
+
+type BatchResponseDTO struct {
+	ID              uint       `json:"id"`
+	Status          string     `json:"status"`
+	Total           int        `json:"total"`
+	Pending         int        `json:"pending"`
+	Succeeded       int        `json:"succeeded"`
+	Failed          int        `json:"failed"`
+	OnCompleteJobID *uint      `json:"on_complete_job_id,omitempty"`
+	OnFailureJobID  *uint      `json:"on_failure_job_id,omitempty"`
+	JobIDs          []uint     `json:"job_ids,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+	FinishedAt      *time.Time `json:"finished_at,omitempty"`
+}
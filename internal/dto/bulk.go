@@ -0,0 +1,67 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/joshu-sajeev/goqueue/internal/config"
+)
+
+// BulkCreateDTO is a list of jobs to create at once.
+type BulkCreateDTO struct {
+	// Jobs are validated one by one, so the elements are not validated
+	// when the request is bound.
+	Jobs []JobCreateDTO `json:"jobs" validate:"required,min=1,max=10000"`
+	// AllOrNothing creates no jobs if any of them is invalid. Otherwise the
+	// valid jobs are created and the invalid ones reported.
+	AllOrNothing bool `json:"all_or_nothing,omitempty"`
+}
+
+// BulkCreateResponseDTO reports the outcome of a bulk creation. JobIDs has
+// an entry per requested job, null for jobs that were not created.
+type BulkCreateResponseDTO struct {
+	Created int                `json:"created"`
+	Failed  int                `json:"failed"`
+	JobIDs  []*uint            `json:"job_ids"`
+	Errors  []BulkItemErrorDTO `json:"errors,omitempty"`
+}
+
+// BulkItemErrorDTO is why the job at Index of a bulk request is invalid.
+type BulkItemErrorDTO struct {
+	Index  int            `json:"index"`
+	Error  string         `json:"error"`
+	Fields map[string]any `json:"fields,omitempty"`
+}
+
+// BulkOperationCreateDTO starts a bulk retry, cancel, delete or move of the
+// jobs matching Filter.
+type BulkOperationCreateDTO struct {
+	Filter BulkFilterDTO `json:"filter"`
+	// TargetQueue is the queue jobs are moved to. Only used by move.
+	TargetQueue string `json:"target_queue,omitempty"`
+}
+
+// BulkFilterDTO selects the jobs of a bulk operation. Every set field must
+// match; at least one must be set.
+type BulkFilterDTO struct {
+	Queue         string           `json:"queue,omitempty"`
+	Status        config.JobStatus `json:"status,omitempty"`
+	CreatedAfter  *time.Time       `json:"created_after,omitempty"`
+	CreatedBefore *time.Time       `json:"created_before,omitempty"`
+	// ErrorContains matches jobs whose error contains it, ignoring case.
+	ErrorContains string `json:"error_contains,omitempty" validate:"omitempty,max=1000"`
+}
+
+// BulkOperationResponseDTO reports the progress of a bulk operation.
+type BulkOperationResponseDTO struct {
+	ID          uint          `json:"id"`
+	Action      string        `json:"action"`
+	Status      string        `json:"status"`
+	Filter      BulkFilterDTO `json:"filter"`
+	TargetQueue string        `json:"target_queue,omitempty"`
+	Total       int           `json:"total"`
+	Processed   int           `json:"processed"`
+	Error       string        `json:"error,omitempty"`
+	CreatedAt   time.Time     `json:"created_at"`
+	StartedAt   *time.Time    `json:"started_at,omitempty"`
+	FinishedAt  *time.Time    `json:"finished_at,omitempty"`
+}
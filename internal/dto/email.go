@@ -1,7 +1,34 @@
 package dto
 
 type SendEmailPayload struct {
-	To      string `json:"to" validate:"required,email"`
-	Subject string `json:"subject" validate:"required"`
-	Body    string `json:"body" validate:"required"`
+	To          string            `json:"to" validate:"required,email"`
+	CC          []string          `json:"cc,omitempty" validate:"omitempty,dive,email"`
+	BCC         []string          `json:"bcc,omitempty" validate:"omitempty,dive,email"`
+	ReplyTo     string            `json:"reply_to,omitempty" validate:"omitempty,email"`
+	Subject     string            `json:"subject,omitempty" validate:"required_without=Template,excluded_with=Template"`
+	Body        string            `json:"body,omitempty" validate:"required_without_all=HTML Template,excluded_with=Template"`
+	HTML        string            `json:"html,omitempty" validate:"required_without_all=Body Template,excluded_with=Template"`
+	Attachments []EmailAttachment `json:"attachments,omitempty" validate:"omitempty,dive"`
+
+	// Template names a stored email template that is rendered with Data to
+	// produce the subject and body. It replaces Subject, Body and HTML.
+	Template string         `json:"template,omitempty"`
+	Data     map[string]any `json:"data,omitempty"`
+}
+
+// EmailAttachment is a file attached to an email. Content is base64 encoded.
+type EmailAttachment struct {
+	Filename    string `json:"filename" validate:"required"`
+	ContentType string `json:"content_type,omitempty"`
+	Content     string `json:"content" validate:"required,base64"`
+}
+
+type SendEmailResult struct {
+	To        string `json:"to"`
+	Subject   string `json:"subject"`
+	SentAt    string `json:"sent_at"`
+	MessageID string `json:"message_id"`
+	// ServerID is the queue ID the SMTP server reported for the message,
+	// when it reports one.
+	ServerID string `json:"server_id,omitempty"`
 }
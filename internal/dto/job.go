@@ -13,27 +13,66 @@ type JobCreateDTO struct {
 	Payload     json.RawMessage `json:"payload" validate:"required"`
 	MaxRetries  int             `json:"max_retries" validate:"gte=0,lte=20"`
 	AvailableAt *time.Time      `json:"available_at,omitempty"`
+	// ExpiresAt is when the job stops being worth running. A job that has
+	// not started by then is expired instead. Defaults to the queue's TTL
+	// after the job becomes available, if the queue has one.
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+
+	// DependsOn lists existing jobs that must complete before this one
+	// runs. Until then the job is in the waiting status.
+	DependsOn []uint `json:"depends_on,omitempty" validate:"omitempty,max=100,dive,gt=0"`
+	// PassResults adds the dependencies' results to the payload under
+	// "dependency_results", keyed by job ID.
+	PassResults bool `json:"pass_results,omitempty"`
+	// OnDependencyFailure is "fail" (the default) or "skip".
+	OnDependencyFailure string `json:"on_dependency_failure,omitempty" validate:"omitempty,oneof=fail skip"`
+
+	// GroupKey runs the jobs of a queue that share it one at a time, in the
+	// order they were created.
+	GroupKey string `json:"group_key,omitempty" validate:"omitempty,max=255"`
+	// ConcurrencyKey caps the number of running jobs that share it at
+	// ConcurrencyLimit, which defaults to 1. Other jobs with the key stay
+	// queued until a slot frees up.
+	ConcurrencyKey   string `json:"concurrency_key,omitempty" validate:"omitempty,max=255"`
+	ConcurrencyLimit int    `json:"concurrency_limit,omitempty" validate:"omitempty,gte=1,lte=1000"`
+
+	// DebounceKey collapses enqueues of jobs in the queue that share it.
+	// In "debounce" mode (the default) the pending job with the key takes
+	// the new payload and starts DebounceWindow seconds after the latest
+	// enqueue. In "throttle" mode enqueues within DebounceWindow seconds of
+	// the last accepted one are dropped.
+	DebounceKey    string `json:"debounce_key,omitempty" validate:"omitempty,max=255"`
+	DebounceWindow int    `json:"debounce_window,omitempty" validate:"required_with=DebounceKey,omitempty,gte=1,lte=86400"`
+	DebounceMode   string `json:"debounce_mode,omitempty" validate:"omitempty,oneof=debounce throttle"`
 }
 
 type JobResponseDTO struct {
-	ID         uint             `json:"id"`
-	Queue      string           `json:"queue"`
-	Payload    json.RawMessage  `json:"payload"`
-	Status     config.JobStatus `json:"status"`
-	Attempts   int              `json:"attempts"`
-	MaxRetries int              `json:"max_retries"`
-	Result     json.RawMessage  `json:"result,omitempty"`
-	Error      string           `json:"error,omitempty"`
-	CreatedAt  time.Time        `json:"created_at"`
-	UpdatedAt  time.Time        `json:"updated_at"`
+	ID               uint             `json:"id"`
+	Queue            string           `json:"queue"`
+	Payload          json.RawMessage  `json:"payload"`
+	Status           config.JobStatus `json:"status"`
+	Attempts         int              `json:"attempts"`
+	MaxRetries       int              `json:"max_retries"`
+	Result           json.RawMessage  `json:"result,omitempty"`
+	Error            string           `json:"error,omitempty"`
+	ParentID         *uint            `json:"parent_id,omitempty"`
+	BatchID          *uint            `json:"batch_id,omitempty"`
+	GroupKey         *string          `json:"group_key,omitempty"`
+	ConcurrencyKey   *string          `json:"concurrency_key,omitempty"`
+	ConcurrencyLimit int              `json:"concurrency_limit,omitempty"`
+	DebounceKey      *string          `json:"debounce_key,omitempty"`
+	ExpiresAt        *time.Time       `json:"expires_at,omitempty"`
+	ArchivedAt       *time.Time       `json:"archived_at,omitempty"`
+	CreatedAt        time.Time        `json:"created_at"`
+	UpdatedAt        time.Time        `json:"updated_at"`
 }
 
 type JobDTO struct {
-	ID      uint           `json:"id"`
-	Queue   string         `json:"queue"`
-	Payload datatypes.JSON `json:"payload"`
-	// Attempts   int            `json:"attempts"`
-	// MaxRetries int            `json:"max_retries"`
+	ID         uint           `json:"id"`
+	Queue      string         `json:"queue"`
+	Payload    datatypes.JSON `json:"payload"`
+	Attempts   int            `json:"attempts"`
+	MaxRetries int            `json:"max_retries"`
 	// Result     datatypes.JSON `json:"result,omitempty"`
 	// Error      string         `json:"error,omitempty"`
 	// CreatedAt  time.Time      `json:"created_at"`
@@ -3,7 +3,19 @@ package dto
 type ProcessPaymentPayload struct {
 	PaymentID string  `json:"payment_id" validate:"required"`
 	UserID    string  `json:"user_id" validate:"required"`
-	Amount    float64 `json:"amount" validate:"gt=0"`
+	Amount    float64 `json:"amount" validate:"gt=0,lt=100000000000000"`
 	Currency  string  `json:"currency" validate:"required,len=3"`
 	Method    string  `json:"method" validate:"required,oneof=card upi netbanking wallet"`
 }
+
+type ProcessPaymentResult struct {
+	PaymentID     string  `json:"payment_id"`
+	Status        string  `json:"status"`
+	Amount        float64 `json:"amount"`
+	Currency      string  `json:"currency"`
+	TransactionID string  `json:"transaction_id"`
+	ProcessedAt   string  `json:"processed_at"`
+	// Replayed is set when the payment had already been charged by an
+	// earlier attempt and the recorded transaction was returned.
+	Replayed bool `json:"replayed,omitempty"`
+}
@@ -0,0 +1,33 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/joshu-sajeev/goqueue/internal/config"
+)
+
+// QueueStatsDTO holds the number of jobs in a queue by status. Every
+// status is present, with zero for statuses no job of the queue is in.
+type QueueStatsDTO struct {
+	Queue  string                     `json:"queue"`
+	Total  int64                      `json:"total"`
+	Counts map[config.JobStatus]int64 `json:"counts"`
+}
+
+// What the pruner does with jobs past a cutoff.
+const (
+	RetentionActionArchive = "archive"
+	RetentionActionDelete  = "delete"
+)
+
+// RetentionReportDTO is what the pruner would archive or delete for one
+// queue and status if it ran now.
+type RetentionReportDTO struct {
+	Queue  string           `json:"queue"`
+	Status config.JobStatus `json:"status"`
+	Action string           `json:"action"`
+	// RetainFor is the retention period, e.g. "168h0m0s".
+	RetainFor string    `json:"retain_for"`
+	Cutoff    time.Time `json:"cutoff"`
+	Jobs      int64     `json:"jobs"`
+}
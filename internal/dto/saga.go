@@ -0,0 +1,54 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/joshu-sajeev/goqueue/internal/config"
+)
+
+// SagaCreateDTO is a sequence of steps run one after the other. When a
+// step fails permanently, the compensations of the steps before it run in
+// reverse order.
+type SagaCreateDTO struct {
+	Steps []SagaStepDTO `json:"steps" validate:"required,min=1,max=50,dive"`
+}
+
+type SagaStepDTO struct {
+	Queue      string          `json:"queue" validate:"required"`
+	Payload    json.RawMessage `json:"payload" validate:"required"`
+	MaxRetries int             `json:"max_retries" validate:"gte=0,lte=20"`
+	// PassResults adds the previous step's result to the payload under
+	// "dependency_results".
+	PassResults bool `json:"pass_results,omitempty"`
+
+	// Compensation undoes the step. It runs only if a later step fails,
+	// with the step's result added under "step_result" when its payload is
+	// a JSON object.
+	Compensation *SagaCompensationDTO `json:"compensation,omitempty"`
+}
+
+type SagaCompensationDTO struct {
+	Queue      string          `json:"queue" validate:"required"`
+	Payload    json.RawMessage `json:"payload" validate:"required"`
+	MaxRetries int             `json:"max_retries" validate:"gte=0,lte=20"`
+}
+
+type SagaResponseDTO struct {
+	ID          uint                  `json:"id"`
+	Status      string                `json:"status"`
+	CurrentStep int                   `json:"current_step"`
+	FailedStep  *int                  `json:"failed_step,omitempty"`
+	Error       string                `json:"error,omitempty"`
+	Steps       []SagaStepResponseDTO `json:"steps"`
+	CreatedAt   time.Time             `json:"created_at"`
+	FinishedAt  *time.Time            `json:"finished_at,omitempty"`
+}
+
+type SagaStepResponseDTO struct {
+	Position           int              `json:"position"`
+	JobID              uint             `json:"job_id"`
+	Status             config.JobStatus `json:"status"`
+	CompensationJobID  *uint            `json:"compensation_job_id,omitempty"`
+	CompensationStatus config.JobStatus `json:"compensation_status,omitempty"`
+}
@@ -7,5 +7,14 @@ type SendWebhookPayload struct {
 	Method  string            `json:"method" validate:"required,oneof=POST PUT PATCH"`
 	Headers map[string]string `json:"headers,omitempty"`
 	Body    json.RawMessage   `json:"body" validate:"required"`
-	Timeout int               `json:"timeout" validate:"gte=1,lte=30"`
+	Timeout int               `json:"timeout" validate:"gte=1,lte=30"` // seconds
+}
+
+type SendWebhookResult struct {
+	URL         string              `json:"url"`
+	Method      string              `json:"method"`
+	StatusCode  int                 `json:"status_code"`
+	Headers     map[string][]string `json:"headers,omitempty"`
+	Response    string              `json:"response"`
+	DeliveredAt string              `json:"delivered_at"`
 }
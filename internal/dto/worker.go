@@ -0,0 +1,53 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type WorkerResponseDTO struct {
+	ID              uint      `json:"id"`
+	Hostname        string    `json:"hostname"`
+	PID             int       `json:"pid"`
+	Queues          []string  `json:"queues"`
+	Version         string    `json:"version"`
+	Alive           bool      `json:"alive"`
+	StartedAt       time.Time `json:"started_at"`
+	LastHeartbeatAt time.Time `json:"last_heartbeat_at"`
+}
+
+type WorkerRegisterDTO struct {
+	Hostname string   `json:"hostname" validate:"required"`
+	PID      int      `json:"pid" validate:"gte=0"`
+	Queues   []string `json:"queues" validate:"required,min=1"`
+	Version  string   `json:"version"`
+}
+
+type LeaseRequestDTO struct {
+	WorkerID     uint     `json:"worker_id" validate:"required"`
+	Queues       []string `json:"queues" validate:"required,min=1"`
+	BatchSize    int      `json:"batch_size" validate:"gte=0,lte=100"`
+	LeaseSeconds int      `json:"lease_seconds" validate:"gte=0,lte=3600"`
+}
+
+type LeaseResponseDTO struct {
+	Jobs           []JobDTO  `json:"jobs"`
+	LeaseExpiresAt time.Time `json:"lease_expires_at"`
+}
+
+type AckRequestDTO struct {
+	WorkerID uint            `json:"worker_id" validate:"required"`
+	Result   json.RawMessage `json:"result,omitempty"`
+}
+
+type NackRequestDTO struct {
+	WorkerID       uint   `json:"worker_id" validate:"required"`
+	Error          string `json:"error" validate:"required"`
+	RetryInSeconds *int   `json:"retry_in_seconds,omitempty" validate:"omitempty,gte=0,lte=86400"`
+	Permanent      bool   `json:"permanent"`
+}
+
+type ExtendRequestDTO struct {
+	WorkerID     uint `json:"worker_id" validate:"required"`
+	LeaseSeconds int  `json:"lease_seconds" validate:"required,gte=1,lte=3600"`
+}
@@ -0,0 +1,31 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// WorkflowCreateDTO is a graph of jobs submitted together. Jobs refer to
+// each other by Key.
+type WorkflowCreateDTO struct {
+	Jobs []WorkflowJobDTO `json:"jobs" validate:"required,min=1,max=500,dive"`
+}
+
+type WorkflowJobDTO struct {
+	Key         string          `json:"key" validate:"required"`
+	Queue       string          `json:"queue" validate:"required"`
+	Payload     json.RawMessage `json:"payload" validate:"required"`
+	MaxRetries  int             `json:"max_retries" validate:"gte=0,lte=20"`
+	AvailableAt *time.Time      `json:"available_at,omitempty"`
+
+	// DependsOn lists the keys of jobs in the same workflow that must
+	// complete before this one runs.
+	DependsOn           []string `json:"depends_on,omitempty"`
+	PassResults         bool     `json:"pass_results,omitempty"`
+	OnDependencyFailure string   `json:"on_dependency_failure,omitempty" validate:"omitempty,oneof=fail skip"`
+}
+
+// WorkflowResponseDTO maps each job key to the ID of the created job.
+type WorkflowResponseDTO struct {
+	Jobs map[string]uint `json:"jobs"`
+}
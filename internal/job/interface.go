@@ -2,6 +2,8 @@ package job
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -11,38 +13,122 @@ import (
 	"gorm.io/datatypes"
 )
 
+// ErrDependencyNotFound is matched by the DependencyNotFoundError that
+// JobRepoInterface.Create and CreateWorkflow return when a job depends on
+// a job that does not exist.
+var ErrDependencyNotFound = errors.New("dependency not found")
+
+// DependencyNotFoundError lists the IDs of the dependencies of a job that
+// do not exist. It matches ErrDependencyNotFound with errors.Is.
+type DependencyNotFoundError struct {
+	IDs []uint
+}
+
+func (e *DependencyNotFoundError) Error() string {
+	return fmt.Sprintf("%s: %v", ErrDependencyNotFound, e.IDs)
+}
+
+func (e *DependencyNotFoundError) Is(target error) bool {
+	return target == ErrDependencyNotFound
+}
+
+// ErrBatchNotFound is returned by JobRepoInterface.GetBatch when the batch
+// does not exist.
+var ErrBatchNotFound = errors.New("batch not found")
+
+// ErrSagaNotFound is returned by JobRepoInterface.GetSaga when the saga
+// does not exist.
+var ErrSagaNotFound = errors.New("saga not found")
+
+// ErrBulkOperationNotFound is returned by
+// JobRepoInterface.GetBulkOperation when the operation does not exist.
+var ErrBulkOperationNotFound = errors.New("bulk operation not found")
+
+// ErrLeaseLost is returned by JobRepoInterface.MarkCompleted, MarkFailed,
+// RetryLater and Snooze when the job is not running under the given
+// worker, because it finished already or its lock was released.
+var ErrLeaseLost = errors.New("job is not running under this worker")
+
+// ThrottledError is returned by JobRepoInterface.Create when a throttled
+// job is dropped because JobID, a job with the same debounce key, was
+// created within the window that ends at Until.
+type ThrottledError struct {
+	JobID uint
+	Until time.Time
+}
+
+func (e *ThrottledError) Error() string {
+	return fmt.Sprintf("throttled by job %d until %s", e.JobID, e.Until.Format(time.RFC3339))
+}
+
 // JobRepoInterface defines the contract for job repository operations.
 type JobRepoInterface interface {
 	Create(ctx context.Context, job *models.Job) error
+	CreateJobs(ctx context.Context, jobs []*models.Job) error
+	CreateWorkflow(ctx context.Context, jobs []*models.Job, deps [][]int) error
+	CreateBatch(ctx context.Context, batch *models.Batch, jobs []*models.Job) error
+	GetBatch(ctx context.Context, id uint) (*models.Batch, error)
+	CreateSaga(ctx context.Context, saga *models.Saga, steps []*models.SagaStep) error
+	GetSaga(ctx context.Context, id uint) (*models.Saga, []models.SagaStep, error)
 	Get(ctx context.Context, id uint) (*models.Job, error)
 	UpdateStatus(ctx context.Context, id uint, status config.JobStatus) error
 	IncrementAttempts(ctx context.Context, id uint) error
 	SaveResult(ctx context.Context, id uint, result datatypes.JSON, err string) error
 	List(ctx context.Context, queue string) ([]models.Job, error)
-
+	QueueStats(ctx context.Context) (map[string]map[config.JobStatus]int64, error)
+	PruneJobs(ctx context.Context, queue string, status config.JobStatus, cutoff time.Time, limit int) (int64, error)
+	CountPrunable(ctx context.Context, queue string, status config.JobStatus, cutoff time.Time) (int64, error)
+	ArchiveJobs(ctx context.Context, queue string, status config.JobStatus, cutoff time.Time, limit int) (int64, error)
+	GetArchived(ctx context.Context, id uint) (*models.Job, error)
+	CreateBulkOperation(ctx context.Context, op *models.BulkOperation) error
+	GetBulkOperation(ctx context.Context, id uint) (*models.BulkOperation, error)
 	AcquireNext(ctx context.Context, queue string, workerID uint, lockDuration time.Duration) (*dto.JobDTO, error)
+	ExtendLock(ctx context.Context, id uint, workerID uint, until time.Time) (bool, error)
 	Release(ctx context.Context, id uint) error
-	RetryLater(ctx context.Context, id uint, availableAt time.Time) error
+	RetryLater(ctx context.Context, id uint, workerID uint, availableAt time.Time, errMsg string) error
+	Snooze(ctx context.Context, id uint, workerID uint, availableAt time.Time) error
+	MarkFailed(ctx context.Context, id uint, workerID uint, errMsg string) error
 	ListStuckJobs(ctx context.Context, staleDuration time.Duration) ([]models.Job, error)
-	MarkCompleted(ctx context.Context, id uint, result datatypes.JSON) error
+	ListOrphanedJobs(ctx context.Context, heartbeatTimeout time.Duration) ([]models.Job, error)
+	MarkCompleted(ctx context.Context, id uint, workerID uint, result datatypes.JSON) error
 }
 
 // JobServiceInterface defines the contract for job business logic operations.
 type JobServiceInterface interface {
 	CreateJob(ctx context.Context, dto *dto.JobCreateDTO) error
+	CreateJobs(ctx context.Context, req *dto.BulkCreateDTO) (*dto.BulkCreateResponseDTO, error)
+	CreateWorkflow(ctx context.Context, wf *dto.WorkflowCreateDTO) (*dto.WorkflowResponseDTO, error)
+	CreateBatch(ctx context.Context, b *dto.BatchCreateDTO) (*dto.BatchResponseDTO, error)
+	GetBatch(ctx context.Context, id uint) (*dto.BatchResponseDTO, error)
+	CreateSaga(ctx context.Context, saga *dto.SagaCreateDTO) (*dto.SagaResponseDTO, error)
+	GetSaga(ctx context.Context, id uint) (*dto.SagaResponseDTO, error)
 	GetJobByID(ctx context.Context, id uint) (*dto.JobResponseDTO, error)
 	UpdateStatus(ctx context.Context, id uint, status config.JobStatus) error
 	IncrementAttempts(ctx context.Context, id uint) error
 	SaveResult(ctx context.Context, id uint, result datatypes.JSON, err string) error
 	ListJobs(ctx context.Context, queue string) ([]dto.JobResponseDTO, error)
+	QueueStats(ctx context.Context) ([]dto.QueueStatsDTO, error)
+	RetentionReport(ctx context.Context) ([]dto.RetentionReportDTO, error)
+	CreateBulkOperation(ctx context.Context, action string, req *dto.BulkOperationCreateDTO) (*dto.BulkOperationResponseDTO, error)
+	GetBulkOperation(ctx context.Context, id uint) (*dto.BulkOperationResponseDTO, error)
 }
 
 // JobHandlerInterface defines the contract for HTTP request handlers.
 type JobHandlerInterface interface {
 	Create(c *gin.Context)
+	CreateJobs(c *gin.Context)
+	CreateBulkOperation(c *gin.Context)
+	GetBulkOperation(c *gin.Context)
+	CreateWorkflow(c *gin.Context)
+	CreateBatch(c *gin.Context)
+	GetBatch(c *gin.Context)
+	CreateSaga(c *gin.Context)
+	GetSaga(c *gin.Context)
 	Get(c *gin.Context)
 	Update(c *gin.Context)
 	Increment(c *gin.Context)
 	Save(c *gin.Context)
 	List(c *gin.Context)
+	QueueStats(c *gin.Context)
+	RetentionReport(c *gin.Context)
 }
@@ -44,6 +44,164 @@ func (h *JobHandler) Create(c *gin.Context) {
 	c.JSON(http.StatusCreated, req)
 }
 
+// CreateJobs handles HTTP requests for creating many jobs at once. Each
+// job is validated separately and HTTP 201 is returned with the ID of each
+// created job and the errors of the rejected ones.
+func (h *JobHandler) CreateJobs(c *gin.Context) {
+	var req dto.BulkCreateDTO
+
+	if !middleware.Bind(c, &req) {
+		c.Abort()
+		return
+	}
+
+	resp, err := h.service.CreateJobs(c.Request.Context(), &req)
+	if err != nil {
+		c.Error(err)
+		c.Abort()
+		return
+	}
+
+	c.JSON(http.StatusCreated, resp)
+}
+
+// CreateBulkOperation handles HTTP requests for retrying, cancelling,
+// deleting or moving the jobs matching a filter. The action is taken from
+// the path and runs in the background; HTTP 202 is returned with the
+// operation, whose progress is polled with GetBulkOperation.
+func (h *JobHandler) CreateBulkOperation(c *gin.Context) {
+	var req dto.BulkOperationCreateDTO
+
+	if !middleware.Bind(c, &req) {
+		c.Abort()
+		return
+	}
+
+	resp, err := h.service.CreateBulkOperation(c.Request.Context(), c.Param("action"), &req)
+	if err != nil {
+		c.Error(err)
+		c.Abort()
+		return
+	}
+
+	c.JSON(http.StatusAccepted, resp)
+}
+
+// GetBulkOperation handles HTTP requests to fetch the progress of a bulk
+// operation.
+func (h *JobHandler) GetBulkOperation(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 0)
+	if err != nil || id < 1 {
+		c.Error(common.Errf(http.StatusBadRequest, "invalid ID"))
+		return
+	}
+
+	resp, err := h.service.GetBulkOperation(c.Request.Context(), uint(id))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// CreateWorkflow handles HTTP requests for submitting a graph of jobs.
+// All jobs are created atomically and HTTP 201 is returned with the ID
+// of each job by key.
+func (h *JobHandler) CreateWorkflow(c *gin.Context) {
+	var req dto.WorkflowCreateDTO
+
+	if !middleware.Bind(c, &req) {
+		c.Abort()
+		return
+	}
+
+	resp, err := h.service.CreateWorkflow(c.Request.Context(), &req)
+	if err != nil {
+		c.Error(err)
+		c.Abort()
+		return
+	}
+
+	c.JSON(http.StatusCreated, resp)
+}
+
+// CreateBatch handles HTTP requests for creating a batch of jobs. The jobs
+// are created atomically and HTTP 201 is returned with the batch and the
+// IDs of its jobs in request order.
+func (h *JobHandler) CreateBatch(c *gin.Context) {
+	var req dto.BatchCreateDTO
+
+	if !middleware.Bind(c, &req) {
+		c.Abort()
+		return
+	}
+
+	resp, err := h.service.CreateBatch(c.Request.Context(), &req)
+	if err != nil {
+		c.Error(err)
+		c.Abort()
+		return
+	}
+
+	c.JSON(http.StatusCreated, resp)
+}
+
+// GetBatch handles HTTP requests to fetch the progress of a batch.
+func (h *JobHandler) GetBatch(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 0)
+	if err != nil || id < 1 {
+		c.Error(common.Errf(http.StatusBadRequest, "invalid ID"))
+		return
+	}
+
+	resp, err := h.service.GetBatch(c.Request.Context(), uint(id))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// CreateSaga handles HTTP requests for starting a saga. The saga and the
+// jobs of its steps are created atomically and HTTP 201 is returned with
+// the saga's state.
+func (h *JobHandler) CreateSaga(c *gin.Context) {
+	var req dto.SagaCreateDTO
+
+	if !middleware.Bind(c, &req) {
+		c.Abort()
+		return
+	}
+
+	resp, err := h.service.CreateSaga(c.Request.Context(), &req)
+	if err != nil {
+		c.Error(err)
+		c.Abort()
+		return
+	}
+
+	c.JSON(http.StatusCreated, resp)
+}
+
+// GetSaga handles HTTP requests to fetch the state of a saga and its steps.
+func (h *JobHandler) GetSaga(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 0)
+	if err != nil || id < 1 {
+		c.Error(common.Errf(http.StatusBadRequest, "invalid ID"))
+		return
+	}
+
+	resp, err := h.service.GetSaga(c.Request.Context(), uint(id))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
 // Get handles HTTP requests to fetch a job by its ID.
 // It validates the job ID, calls the JobService, and returns
 // HTTP 200 with the job data on success or an appropriate error code.
@@ -150,3 +308,27 @@ func (h *JobHandler) List(c *gin.Context) {
 
 	c.JSON(http.StatusOK, jobs)
 }
+
+// QueueStats handles HTTP requests for the number of jobs in each queue by
+// status.
+func (h *JobHandler) QueueStats(c *gin.Context) {
+	stats, err := h.service.QueueStats(c.Request.Context())
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, stats)
+}
+
+// RetentionReport handles HTTP requests for a dry run of the retention
+// pruner: how many jobs of each queue and status it would delete now.
+func (h *JobHandler) RetentionReport(c *gin.Context) {
+	report, err := h.service.RetentionReport(c.Request.Context())
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
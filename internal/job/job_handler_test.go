@@ -110,6 +110,466 @@ func TestJobHandler_Create(t *testing.T) {
 	}
 }
 
+func TestJobHandler_CreateWorkflow(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		body           string
+		setupMock      func(*mocks.JobServiceMock)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "successful workflow creation",
+			body: `{"jobs":[{"key":"a","queue":"default","payload":{}},{"key":"b","queue":"default","payload":{},"depends_on":["a"]}]}`,
+			setupMock: func(m *mocks.JobServiceMock) {
+				m.On("CreateWorkflow", mock.Anything, mock.MatchedBy(func(wf *dto.WorkflowCreateDTO) bool {
+					return len(wf.Jobs) == 2 && wf.Jobs[1].DependsOn[0] == "a"
+				})).Return(&dto.WorkflowResponseDTO{Jobs: map[string]uint{"a": 1, "b": 2}}, nil)
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody:   `{"jobs":{"a":1,"b":2}}`,
+		},
+		{
+			name:           "empty workflow",
+			body:           `{"jobs":[]}`,
+			setupMock:      func(m *mocks.JobServiceMock) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "invalid dependency failure mode",
+			body:           `{"jobs":[{"key":"a","queue":"default","payload":{},"on_dependency_failure":"ignore"}]}`,
+			setupMock:      func(m *mocks.JobServiceMock) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "cycle",
+			body: `{"jobs":[{"key":"a","queue":"default","payload":{},"depends_on":["a"]}]}`,
+			setupMock: func(m *mocks.JobServiceMock) {
+				m.On("CreateWorkflow", mock.Anything, mock.Anything).
+					Return(nil, common.NewAPIError(http.StatusBadRequest, "workflow has a dependency cycle", map[string]any{"keys": []string{"a"}}))
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(mocks.JobServiceMock)
+			tt.setupMock(mockService)
+
+			req := httptest.NewRequest(http.MethodPost, "/workflows", bytes.NewReader([]byte(tt.body)))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			r := gin.New()
+			r.Use(middleware.TimeoutMiddleware(5*time.Second), middleware.ErrorHandler())
+			r.POST("/workflows", NewJobHandler(mockService).CreateWorkflow)
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedBody != "" {
+				assert.JSONEq(t, tt.expectedBody, w.Body.String())
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestJobHandler_CreateBatch(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		body           string
+		setupMock      func(*mocks.JobServiceMock)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "successful batch creation",
+			body: `{"jobs":[{"queue":"default","payload":{}},{"queue":"default","payload":{}}],"on_complete":{"queue":"default","payload":{}}}`,
+			setupMock: func(m *mocks.JobServiceMock) {
+				m.On("CreateBatch", mock.Anything, mock.MatchedBy(func(b *dto.BatchCreateDTO) bool {
+					return len(b.Jobs) == 2 && b.OnComplete != nil && b.OnFailure == nil
+				})).Return(&dto.BatchResponseDTO{ID: 7, Status: dto.BatchStatusRunning, Total: 2, Pending: 2, JobIDs: []uint{1, 2}}, nil)
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody:   `{"id":7,"status":"running","total":2,"pending":2,"succeeded":0,"failed":0,"job_ids":[1,2],"created_at":"0001-01-01T00:00:00Z"}`,
+		},
+		{
+			name:           "empty batch",
+			body:           `{"jobs":[]}`,
+			setupMock:      func(m *mocks.JobServiceMock) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "callback without queue",
+			body:           `{"jobs":[{"queue":"default","payload":{}}],"on_failure":{"payload":{}}}`,
+			setupMock:      func(m *mocks.JobServiceMock) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(mocks.JobServiceMock)
+			tt.setupMock(mockService)
+
+			req := httptest.NewRequest(http.MethodPost, "/batches", bytes.NewReader([]byte(tt.body)))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			r := gin.New()
+			r.Use(middleware.TimeoutMiddleware(5*time.Second), middleware.ErrorHandler())
+			r.POST("/batches", NewJobHandler(mockService).CreateBatch)
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedBody != "" {
+				assert.JSONEq(t, tt.expectedBody, w.Body.String())
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestJobHandler_CreateJobs(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	id := uint(5)
+	tests := []struct {
+		name           string
+		body           string
+		setupMock      func(*mocks.JobServiceMock)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "invalid jobs are passed to the service",
+			body: `{"jobs":[{"queue":"default","payload":{}},{"payload":{}}],"all_or_nothing":false}`,
+			setupMock: func(m *mocks.JobServiceMock) {
+				m.On("CreateJobs", mock.Anything, mock.MatchedBy(func(req *dto.BulkCreateDTO) bool {
+					return len(req.Jobs) == 2 && req.Jobs[1].Queue == ""
+				})).Return(&dto.BulkCreateResponseDTO{
+					Created: 1,
+					Failed:  1,
+					JobIDs:  []*uint{&id, nil},
+					Errors:  []dto.BulkItemErrorDTO{{Index: 1, Error: "validation failed", Fields: map[string]any{"Queue": "failed required"}}},
+				}, nil)
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody:   `{"created":1,"failed":1,"job_ids":[5,null],"errors":[{"index":1,"error":"validation failed","fields":{"Queue":"failed required"}}]}`,
+		},
+		{
+			name:           "no jobs",
+			body:           `{"jobs":[]}`,
+			setupMock:      func(m *mocks.JobServiceMock) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "all or nothing with invalid jobs",
+			body: `{"jobs":[{"payload":{}}],"all_or_nothing":true}`,
+			setupMock: func(m *mocks.JobServiceMock) {
+				m.On("CreateJobs", mock.Anything, mock.Anything).Return(nil, common.NewAPIError(
+					http.StatusBadRequest,
+					"validation failed",
+					map[string]any{"errors": []dto.BulkItemErrorDTO{{Index: 0, Error: "validation failed"}}},
+				))
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"validation failed","fields":{"errors":[{"index":0,"error":"validation failed"}]}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(mocks.JobServiceMock)
+			tt.setupMock(mockService)
+
+			req := httptest.NewRequest(http.MethodPost, "/jobs/batch", bytes.NewReader([]byte(tt.body)))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			r := gin.New()
+			r.Use(middleware.TimeoutMiddleware(5*time.Second), middleware.ErrorHandler())
+			r.POST("/jobs/batch", NewJobHandler(mockService).CreateJobs)
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedBody != "" {
+				assert.JSONEq(t, tt.expectedBody, w.Body.String())
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestJobHandler_CreateBulkOperation(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		path           string
+		body           string
+		setupMock      func(*mocks.JobServiceMock)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "operation is accepted",
+			path: "/jobs/bulk/retry",
+			body: `{"filter":{"queue":"email","status":"failed","error_contains":"timeout"}}`,
+			setupMock: func(m *mocks.JobServiceMock) {
+				m.On("CreateBulkOperation", mock.Anything, "retry", mock.MatchedBy(func(req *dto.BulkOperationCreateDTO) bool {
+					return req.Filter.Queue == "email" && req.Filter.Status == config.JobStatusFailed && req.Filter.ErrorContains == "timeout"
+				})).Return(&dto.BulkOperationResponseDTO{ID: 4, Action: "retry", Status: "pending", Total: 120}, nil)
+			},
+			expectedStatus: http.StatusAccepted,
+		},
+		{
+			name: "action is passed from the path",
+			path: "/jobs/bulk/move",
+			body: `{"filter":{"queue":"email"},"target_queue":"default"}`,
+			setupMock: func(m *mocks.JobServiceMock) {
+				m.On("CreateBulkOperation", mock.Anything, "move", mock.MatchedBy(func(req *dto.BulkOperationCreateDTO) bool {
+					return req.TargetQueue == "default"
+				})).Return(&dto.BulkOperationResponseDTO{ID: 5, Action: "move", Status: "pending"}, nil)
+			},
+			expectedStatus: http.StatusAccepted,
+		},
+		{
+			name:           "invalid request body JSON",
+			path:           "/jobs/bulk/delete",
+			body:           "{invalid json}",
+			setupMock:      func(m *mocks.JobServiceMock) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "unknown action",
+			path: "/jobs/bulk/pause",
+			body: `{"filter":{"queue":"email"}}`,
+			setupMock: func(m *mocks.JobServiceMock) {
+				m.On("CreateBulkOperation", mock.Anything, "pause", mock.Anything).
+					Return(nil, common.Errf(http.StatusBadRequest, "invalid bulk action"))
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"invalid bulk action"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(mocks.JobServiceMock)
+			tt.setupMock(mockService)
+
+			req := httptest.NewRequest(http.MethodPost, tt.path, bytes.NewReader([]byte(tt.body)))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			r := gin.New()
+			r.Use(middleware.TimeoutMiddleware(5*time.Second), middleware.ErrorHandler())
+			r.POST("/jobs/bulk/:action", NewJobHandler(mockService).CreateBulkOperation)
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedBody != "" {
+				assert.JSONEq(t, tt.expectedBody, w.Body.String())
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestJobHandler_GetBulkOperation(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		id             string
+		setupMock      func(*mocks.JobServiceMock)
+		expectedStatus int
+	}{
+		{
+			name: "operation found",
+			id:   "4",
+			setupMock: func(m *mocks.JobServiceMock) {
+				m.On("GetBulkOperation", mock.Anything, uint(4)).
+					Return(&dto.BulkOperationResponseDTO{ID: 4, Status: "running", Total: 1000, Processed: 500}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "invalid ID",
+			id:             "abc",
+			setupMock:      func(m *mocks.JobServiceMock) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "not found",
+			id:   "9",
+			setupMock: func(m *mocks.JobServiceMock) {
+				m.On("GetBulkOperation", mock.Anything, uint(9)).
+					Return(nil, common.Errf(http.StatusNotFound, "bulk operation not found"))
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(mocks.JobServiceMock)
+			tt.setupMock(mockService)
+
+			req := httptest.NewRequest(http.MethodGet, "/jobs/bulk/"+tt.id, nil)
+			w := httptest.NewRecorder()
+
+			r := gin.New()
+			r.Use(middleware.ErrorHandler())
+			r.GET("/jobs/bulk/:id", NewJobHandler(mockService).GetBulkOperation)
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestJobHandler_GetBatch(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		id             string
+		setupMock      func(*mocks.JobServiceMock)
+		expectedStatus int
+	}{
+		{
+			name: "batch found",
+			id:   "7",
+			setupMock: func(m *mocks.JobServiceMock) {
+				m.On("GetBatch", mock.Anything, uint(7)).
+					Return(&dto.BatchResponseDTO{ID: 7, Status: dto.BatchStatusFinished, Total: 2, Succeeded: 1, Failed: 1}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "batch not found",
+			id:   "8",
+			setupMock: func(m *mocks.JobServiceMock) {
+				m.On("GetBatch", mock.Anything, uint(8)).
+					Return(nil, common.Errf(http.StatusNotFound, "batch not found"))
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "invalid ID",
+			id:             "abc",
+			setupMock:      func(m *mocks.JobServiceMock) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(mocks.JobServiceMock)
+			tt.setupMock(mockService)
+
+			req := httptest.NewRequest(http.MethodGet, "/batches/"+tt.id, nil)
+			w := httptest.NewRecorder()
+
+			r := gin.New()
+			r.Use(middleware.TimeoutMiddleware(5*time.Second), middleware.ErrorHandler())
+			r.GET("/batches/:id", NewJobHandler(mockService).GetBatch)
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestJobHandler_CreateSaga(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		body           string
+		setupMock      func(*mocks.JobServiceMock)
+		expectedStatus int
+	}{
+		{
+			name: "successful saga creation",
+			body: `{"steps":[{"queue":"payment","payload":{},"compensation":{"queue":"payment","payload":{}}},{"queue":"email","payload":{}}]}`,
+			setupMock: func(m *mocks.JobServiceMock) {
+				m.On("CreateSaga", mock.Anything, mock.MatchedBy(func(sg *dto.SagaCreateDTO) bool {
+					return len(sg.Steps) == 2 && sg.Steps[0].Compensation != nil && sg.Steps[1].Compensation == nil
+				})).Return(&dto.SagaResponseDTO{ID: 3, Status: "running", CurrentStep: 1}, nil)
+			},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:           "no steps",
+			body:           `{"steps":[]}`,
+			setupMock:      func(m *mocks.JobServiceMock) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "compensation without payload",
+			body:           `{"steps":[{"queue":"payment","payload":{},"compensation":{"queue":"payment"}}]}`,
+			setupMock:      func(m *mocks.JobServiceMock) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(mocks.JobServiceMock)
+			tt.setupMock(mockService)
+
+			req := httptest.NewRequest(http.MethodPost, "/sagas", bytes.NewReader([]byte(tt.body)))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			r := gin.New()
+			r.Use(middleware.TimeoutMiddleware(5*time.Second), middleware.ErrorHandler())
+			r.POST("/sagas", NewJobHandler(mockService).CreateSaga)
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestJobHandler_GetSaga(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockService := new(mocks.JobServiceMock)
+	mockService.On("GetSaga", mock.Anything, uint(3)).Return(&dto.SagaResponseDTO{
+		ID:          3,
+		Status:      "compensating",
+		CurrentStep: 2,
+		Steps: []dto.SagaStepResponseDTO{
+			{Position: 1, JobID: 10, Status: config.JobStatusCompleted},
+			{Position: 2, JobID: 11, Status: config.JobStatusFailed},
+		},
+	}, nil)
+	mockService.On("GetSaga", mock.Anything, uint(4)).Return(nil, common.Errf(http.StatusNotFound, "saga not found"))
+
+	r := gin.New()
+	r.Use(middleware.TimeoutMiddleware(5*time.Second), middleware.ErrorHandler())
+	r.GET("/sagas/:id", NewJobHandler(mockService).GetSaga)
+
+	for id, status := range map[string]int{"3": http.StatusOK, "4": http.StatusNotFound, "x": http.StatusBadRequest} {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/sagas/"+id, nil))
+		assert.Equal(t, status, w.Code, "saga %s", id)
+	}
+	mockService.AssertExpectations(t)
+}
+
 func TestJobHandler_Get(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
@@ -427,3 +887,82 @@ func TestJobHandler_List(t *testing.T) {
 		})
 	}
 }
+
+func TestJobHandler_QueueStats(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		setupMock      func(*mocks.JobServiceMock)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "success",
+			setupMock: func(m *mocks.JobServiceMock) {
+				m.On("QueueStats", mock.Anything).Return([]dto.QueueStatsDTO{{
+					Queue:  "email",
+					Total:  3,
+					Counts: map[config.JobStatus]int64{config.JobStatusQueued: 1, config.JobStatusExpired: 2},
+				}}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `[{"queue":"email","total":3,"counts":{"queued":1,"expired":2}}]`,
+		},
+		{
+			name: "service error",
+			setupMock: func(m *mocks.JobServiceMock) {
+				m.On("QueueStats", mock.Anything).
+					Return(nil, common.Errf(http.StatusInternalServerError, "failed to get queue stats"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   `{"error":"failed to get queue stats"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(mocks.JobServiceMock)
+			tt.setupMock(mockService)
+
+			req := httptest.NewRequest(http.MethodGet, "/queues/stats", nil)
+			w := httptest.NewRecorder()
+
+			r := gin.New()
+			r.Use(middleware.TimeoutMiddleware(5*time.Second), middleware.ErrorHandler())
+			r.GET("/queues/stats", NewJobHandler(mockService).QueueStats)
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.JSONEq(t, tt.expectedBody, w.Body.String())
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestJobHandler_RetentionReport(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	cutoff := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	mockService := new(mocks.JobServiceMock)
+	mockService.On("RetentionReport", mock.Anything).Return([]dto.RetentionReportDTO{{
+		Queue:     "email",
+		Status:    config.JobStatusCompleted,
+		Action:    dto.RetentionActionDelete,
+		RetainFor: "168h0m0s",
+		Cutoff:    cutoff,
+		Jobs:      40,
+	}}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/queues/retention/report", nil)
+	w := httptest.NewRecorder()
+
+	r := gin.New()
+	r.Use(middleware.TimeoutMiddleware(5*time.Second), middleware.ErrorHandler())
+	r.GET("/queues/retention/report", NewJobHandler(mockService).RetentionReport)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `[{"queue":"email","status":"completed","action":"delete","retain_for":"168h0m0s","cutoff":"2026-03-01T00:00:00Z","jobs":40}]`, w.Body.String())
+	mockService.AssertExpectations(t)
+}
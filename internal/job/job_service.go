@@ -4,20 +4,28 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"slices"
 	"strings"
+	"time"
 
 	"github.com/joshu-sajeev/goqueue/common"
 	"github.com/joshu-sajeev/goqueue/internal/config"
 	"github.com/joshu-sajeev/goqueue/internal/dto"
 	"github.com/joshu-sajeev/goqueue/internal/models"
+	"github.com/joshu-sajeev/goqueue/internal/templates"
+	"github.com/joshu-sajeev/goqueue/middleware"
 	"gorm.io/datatypes"
 	"gorm.io/gorm"
 )
 
 type JobService struct {
-	repo JobRepoInterface
+	repo      JobRepoInterface
+	templates *templates.Store
+	queueTTLs map[string]time.Duration
+	retention Retention
+	archive   Retention
 }
 
 func NewJobService(repo JobRepoInterface) *JobService {
@@ -26,6 +34,52 @@ func NewJobService(repo JobRepoInterface) *JobService {
 
 var _ JobServiceInterface = (*JobService)(nil)
 
+// SetEmailTemplates sets the templates email payloads with a template name
+// are checked against. Without templates such payloads are rejected.
+func (s *JobService) SetEmailTemplates(t *templates.Store) {
+	s.templates = t
+}
+
+// SetQueueTTLs sets how long after becoming available the jobs of each
+// queue expire when they are created without an expires_at.
+func (s *JobService) SetQueueTTLs(ttls map[string]time.Duration) {
+	s.queueTTLs = ttls
+}
+
+// SetRetention sets the retention periods RetentionReport reports on.
+func (s *JobService) SetRetention(r Retention) {
+	s.retention = r
+}
+
+// SetArchive sets the ages after which jobs are archived, which
+// RetentionReport reports on.
+func (s *JobService) SetArchive(r Retention) {
+	s.archive = r
+}
+
+// ParseQueueTTLs parses comma separated queue=duration pairs such as
+// "email=5m,webhooks=1h". TTLs are stored in whole seconds, so each must be
+// at least one second.
+func ParseQueueTTLs(v string) (map[string]time.Duration, error) {
+	ttls := map[string]time.Duration{}
+	for _, entry := range strings.Split(v, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		queue, value, ok := strings.Cut(entry, "=")
+		if !ok || queue == "" {
+			return nil, fmt.Errorf("invalid queue TTL %q", entry)
+		}
+		ttl, err := time.ParseDuration(value)
+		if err != nil || ttl < time.Second {
+			return nil, fmt.Errorf("invalid TTL for queue %q: %q", queue, value)
+		}
+		ttls[queue] = ttl
+	}
+	return ttls, nil
+}
+
 // CreateJob validates job creation input, applies business rules,
 // constructs a Job model, and persists it using the repository.
 // It returns a typed API error for validation failures and an
@@ -35,12 +89,594 @@ func (s *JobService) CreateJob(ctx context.Context, dto *dto.JobCreateDTO) error
 		return common.Errf(http.StatusRequestTimeout, "request canceled or timed out")
 	}
 
+	job, err := s.NewJob(dto)
+	if err != nil {
+		return err
+	}
+
+	if err := s.repo.Create(ctx, job); err != nil {
+		return mapCreateError(err)
+	}
+
+	return nil
+}
+
+// CreateJobs validates each job of a bulk request and creates the valid
+// ones with multi-row inserts. Invalid jobs are reported by index. If
+// AllOrNothing is set or no job is valid, nothing is created and the
+// errors are returned in a 400.
+func (s *JobService) CreateJobs(ctx context.Context, req *dto.BulkCreateDTO) (*dto.BulkCreateResponseDTO, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, common.Errf(http.StatusRequestTimeout, "request canceled or timed out")
+	}
+
+	resp := &dto.BulkCreateResponseDTO{JobIDs: make([]*uint, len(req.Jobs))}
+	var jobs []*models.Job
+	var indexes []int
+	for i := range req.Jobs {
+		j, err := s.newBulkJob(&req.Jobs[i])
+		if err != nil {
+			resp.Errors = append(resp.Errors, bulkItemError(i, err))
+			continue
+		}
+		jobs = append(jobs, j)
+		indexes = append(indexes, i)
+	}
+
+	resp.Failed = len(resp.Errors)
+	if resp.Failed > 0 && (req.AllOrNothing || len(jobs) == 0) {
+		return nil, common.NewAPIError(
+			http.StatusBadRequest,
+			"validation failed",
+			map[string]any{"errors": resp.Errors},
+		)
+	}
+
+	if err := s.repo.CreateJobs(ctx, jobs); err != nil {
+		return nil, mapCreateError(err)
+	}
+
+	for k, j := range jobs {
+		id := j.ID
+		resp.JobIDs[indexes[k]] = &id
+	}
+	resp.Created = len(jobs)
+	return resp, nil
+}
+
+// newBulkJob validates one job of a bulk request, which is not validated
+// when the request is bound, and returns the model to insert.
+func (s *JobService) newBulkJob(d *dto.JobCreateDTO) (*models.Job, error) {
+	if err := validate.Struct(d); err != nil {
+		return nil, common.NewAPIError(
+			http.StatusBadRequest,
+			"validation failed",
+			middleware.FormatValidationErrors(err),
+		)
+	}
+	if len(d.DependsOn) > 0 {
+		return nil, common.Errf(http.StatusBadRequest, "depends_on is not supported in bulk creation")
+	}
+	if d.DebounceKey != "" {
+		return nil, common.Errf(http.StatusBadRequest, "debounce_key is not supported in bulk creation")
+	}
+	return s.NewJob(d)
+}
+
+func bulkItemError(index int, err error) dto.BulkItemErrorDTO {
+	var apiErr common.APIError
+	if errors.As(err, &apiErr) {
+		return dto.BulkItemErrorDTO{Index: index, Error: apiErr.Message, Fields: apiErr.Fields}
+	}
+	return dto.BulkItemErrorDTO{Index: index, Error: err.Error()}
+}
+
+// CreateBulkOperation validates a bulk action on the jobs matching a filter
+// and saves it for a worker to run in the background. The returned
+// operation reports how many jobs match.
+func (s *JobService) CreateBulkOperation(ctx context.Context, action string, req *dto.BulkOperationCreateDTO) (*dto.BulkOperationResponseDTO, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, common.Errf(http.StatusRequestTimeout, "request canceled or timed out")
+	}
+
+	statuses, ok := models.BulkActionStatuses[action]
+	if !ok {
+		return nil, common.NewAPIError(
+			http.StatusBadRequest,
+			"invalid bulk action",
+			map[string]any{
+				"provided": action,
+				"allowed":  []string{models.BulkActionRetry, models.BulkActionCancel, models.BulkActionDelete, models.BulkActionMove},
+			},
+		)
+	}
+	if err := validateBulkFilter(&req.Filter, statuses); err != nil {
+		return nil, err
+	}
+
+	switch {
+	case action != models.BulkActionMove && req.TargetQueue != "":
+		return nil, common.Errf(http.StatusBadRequest, "target_queue is only used by move")
+	case action == models.BulkActionMove && !slices.Contains(config.AllowedQueues, req.TargetQueue):
+		return nil, common.NewAPIError(
+			http.StatusBadRequest,
+			"invalid target_queue",
+			map[string]any{
+				"provided": req.TargetQueue,
+				"allowed":  config.AllowedQueues,
+			},
+		)
+	case action == models.BulkActionMove && req.TargetQueue == req.Filter.Queue:
+		return nil, common.Errf(http.StatusBadRequest, "target_queue must differ from the filter's queue")
+	}
+
+	op := &models.BulkOperation{
+		Action:        action,
+		TargetQueue:   req.TargetQueue,
+		FilterQueue:   req.Filter.Queue,
+		FilterStatus:  req.Filter.Status,
+		CreatedAfter:  req.Filter.CreatedAfter,
+		CreatedBefore: req.Filter.CreatedBefore,
+		ErrorContains: req.Filter.ErrorContains,
+	}
+	if err := s.repo.CreateBulkOperation(ctx, op); err != nil {
+		if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled) {
+			return nil, common.Errf(http.StatusRequestTimeout, "request timed out")
+		}
+		return nil, common.Errf(http.StatusInternalServerError, "failed to create bulk operation")
+	}
+
+	resp := toBulkOperationResponse(op)
+	return &resp, nil
+}
+
+// validateBulkFilter checks that a bulk filter selects something and only
+// selects jobs in statuses the action applies to.
+func validateBulkFilter(f *dto.BulkFilterDTO, statuses []config.JobStatus) error {
+	if err := validate.Struct(f); err != nil {
+		return common.NewAPIError(
+			http.StatusBadRequest,
+			"validation failed",
+			middleware.FormatValidationErrors(err),
+		)
+	}
+	if f.Queue == "" && f.Status == "" && f.CreatedAfter == nil && f.CreatedBefore == nil && f.ErrorContains == "" {
+		return common.Errf(http.StatusBadRequest, "filter must set at least one of queue, status, created_after, created_before or error_contains")
+	}
+	if f.Queue != "" && !slices.Contains(config.AllowedQueues, f.Queue) {
+		return common.NewAPIError(
+			http.StatusBadRequest,
+			"invalid queue",
+			map[string]any{
+				"provided": f.Queue,
+				"allowed":  config.AllowedQueues,
+			},
+		)
+	}
+	if f.Status != "" && !slices.Contains(statuses, f.Status) {
+		return common.NewAPIError(
+			http.StatusBadRequest,
+			"invalid status for this action",
+			map[string]any{
+				"provided": f.Status,
+				"allowed":  statuses,
+			},
+		)
+	}
+	if f.CreatedAfter != nil && f.CreatedBefore != nil && !f.CreatedBefore.After(*f.CreatedAfter) {
+		return common.Errf(http.StatusBadRequest, "created_before must be after created_after")
+	}
+	return nil
+}
+
+// GetBulkOperation returns the progress of a bulk operation.
+func (s *JobService) GetBulkOperation(ctx context.Context, id uint) (*dto.BulkOperationResponseDTO, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, common.Errf(http.StatusRequestTimeout, "request timed out")
+	}
+
+	op, err := s.repo.GetBulkOperation(ctx, id)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrBulkOperationNotFound):
+			return nil, common.Errf(http.StatusNotFound, "bulk operation not found")
+		case errors.Is(err, context.DeadlineExceeded), errors.Is(err, context.Canceled):
+			return nil, common.Errf(http.StatusRequestTimeout, "request timed out")
+		default:
+			return nil, common.Errf(http.StatusInternalServerError, "failed to get bulk operation")
+		}
+	}
+
+	resp := toBulkOperationResponse(op)
+	return &resp, nil
+}
+
+func toBulkOperationResponse(op *models.BulkOperation) dto.BulkOperationResponseDTO {
+	return dto.BulkOperationResponseDTO{
+		ID:     op.ID,
+		Action: op.Action,
+		Status: op.Status,
+		Filter: dto.BulkFilterDTO{
+			Queue:         op.FilterQueue,
+			Status:        op.FilterStatus,
+			CreatedAfter:  op.CreatedAfter,
+			CreatedBefore: op.CreatedBefore,
+			ErrorContains: op.ErrorContains,
+		},
+		TargetQueue: op.TargetQueue,
+		Total:       op.Total,
+		Processed:   op.Processed,
+		Error:       op.Error,
+		CreatedAt:   op.CreatedAt,
+		StartedAt:   op.StartedAt,
+		FinishedAt:  op.FinishedAt,
+	}
+}
+
+// CreateWorkflow validates a graph of jobs and creates all of them in one
+// transaction. Jobs without dependencies are queued immediately; the rest
+// wait until their dependencies complete. It returns the ID of each job by
+// key.
+func (s *JobService) CreateWorkflow(ctx context.Context, wf *dto.WorkflowCreateDTO) (*dto.WorkflowResponseDTO, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, common.Errf(http.StatusRequestTimeout, "request canceled or timed out")
+	}
+
+	order, err := sortWorkflow(wf.Jobs)
+	if err != nil {
+		return nil, err
+	}
+
+	index := make(map[string]int, len(order))
+	jobs := make([]*models.Job, len(order))
+	deps := make([][]int, len(order))
+	for i, node := range order {
+		j, err := s.NewJob(&dto.JobCreateDTO{
+			Queue:               node.Queue,
+			Payload:             node.Payload,
+			MaxRetries:          node.MaxRetries,
+			AvailableAt:         node.AvailableAt,
+			PassResults:         node.PassResults,
+			OnDependencyFailure: node.OnDependencyFailure,
+		})
+		if err != nil {
+			return nil, prefixAPIError(err, fmt.Sprintf("job %q", node.Key))
+		}
+
+		index[node.Key] = i
+		jobs[i] = j
+		for _, key := range node.DependsOn {
+			deps[i] = append(deps[i], index[key])
+		}
+	}
+
+	if err := s.repo.CreateWorkflow(ctx, jobs, deps); err != nil {
+		return nil, mapCreateError(err)
+	}
+
+	resp := &dto.WorkflowResponseDTO{Jobs: make(map[string]uint, len(order))}
+	for i, node := range order {
+		resp.Jobs[node.Key] = jobs[i].ID
+	}
+	return resp, nil
+}
+
+// CreateBatch validates a group of jobs and creates them in one
+// transaction together with the batch that tracks them. Callback jobs wait
+// until the batch finishes or its first job fails.
+func (s *JobService) CreateBatch(ctx context.Context, b *dto.BatchCreateDTO) (*dto.BatchResponseDTO, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, common.Errf(http.StatusRequestTimeout, "request canceled or timed out")
+	}
+
+	batch := &models.Batch{}
+	for _, cb := range []struct {
+		name string
+		dto  *dto.BatchCallbackDTO
+		job  **models.Job
+	}{
+		{"on_complete", b.OnComplete, &batch.OnComplete},
+		{"on_failure", b.OnFailure, &batch.OnFailure},
+	} {
+		if cb.dto == nil {
+			continue
+		}
+		j, err := s.NewJob(&dto.JobCreateDTO{
+			Queue:      cb.dto.Queue,
+			Payload:    cb.dto.Payload,
+			MaxRetries: cb.dto.MaxRetries,
+		})
+		if err != nil {
+			return nil, prefixAPIError(err, cb.name)
+		}
+		*cb.job = j
+	}
+
+	jobs := make([]*models.Job, len(b.Jobs))
+	for i := range b.Jobs {
+		if b.Jobs[i].DebounceKey != "" {
+			return nil, common.Errf(http.StatusBadRequest, "job %d: debounce_key is not supported in batches", i)
+		}
+		j, err := s.NewJob(&b.Jobs[i])
+		if err != nil {
+			return nil, prefixAPIError(err, fmt.Sprintf("job %d", i))
+		}
+		jobs[i] = j
+	}
+
+	if err := s.repo.CreateBatch(ctx, batch, jobs); err != nil {
+		return nil, mapCreateError(err)
+	}
+
+	resp := toBatchResponse(batch)
+	resp.JobIDs = make([]uint, len(jobs))
+	for i, j := range jobs {
+		resp.JobIDs[i] = j.ID
+	}
+	return &resp, nil
+}
+
+// GetBatch returns the progress of a batch.
+func (s *JobService) GetBatch(ctx context.Context, id uint) (*dto.BatchResponseDTO, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, common.Errf(http.StatusRequestTimeout, "request timed out")
+	}
+
+	batch, err := s.repo.GetBatch(ctx, id)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrBatchNotFound):
+			return nil, common.Errf(http.StatusNotFound, "batch not found")
+		case errors.Is(err, context.DeadlineExceeded), errors.Is(err, context.Canceled):
+			return nil, common.Errf(http.StatusRequestTimeout, "request timed out")
+		default:
+			return nil, common.Errf(http.StatusInternalServerError, "failed to get batch")
+		}
+	}
+
+	resp := toBatchResponse(batch)
+	return &resp, nil
+}
+
+func toBatchResponse(b *models.Batch) dto.BatchResponseDTO {
+	status := dto.BatchStatusRunning
+	if b.IsFinished() {
+		status = dto.BatchStatusFinished
+	}
+	return dto.BatchResponseDTO{
+		ID:              b.ID,
+		Status:          status,
+		Total:           b.Total,
+		Pending:         b.Pending,
+		Succeeded:       b.Succeeded,
+		Failed:          b.Failed,
+		OnCompleteJobID: b.OnCompleteJobID,
+		OnFailureJobID:  b.OnFailureJobID,
+		CreatedAt:       b.CreatedAt,
+		FinishedAt:      b.FinishedAt,
+	}
+}
+
+// CreateSaga validates the steps of a saga and creates the saga with the
+// job of every step in one transaction. Only the first step is queued
+// immediately; each later step waits for the one before it.
+func (s *JobService) CreateSaga(ctx context.Context, sg *dto.SagaCreateDTO) (*dto.SagaResponseDTO, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, common.Errf(http.StatusRequestTimeout, "request canceled or timed out")
+	}
+
+	steps := make([]*models.SagaStep, len(sg.Steps))
+	for i, st := range sg.Steps {
+		prefix := fmt.Sprintf("step %d", i+1)
+		if st.PassResults && i == 0 {
+			return nil, common.Errf(http.StatusBadRequest, "%s: pass_results requires a previous step", prefix)
+		}
+		j, err := s.NewJob(&dto.JobCreateDTO{
+			Queue:       st.Queue,
+			Payload:     st.Payload,
+			MaxRetries:  st.MaxRetries,
+			PassResults: st.PassResults,
+		})
+		if err != nil {
+			return nil, prefixAPIError(err, prefix)
+		}
+		step := &models.SagaStep{Job: j}
+
+		if st.Compensation != nil {
+			c, err := s.NewJob(&dto.JobCreateDTO{
+				Queue:      st.Compensation.Queue,
+				Payload:    st.Compensation.Payload,
+				MaxRetries: st.Compensation.MaxRetries,
+			})
+			if err != nil {
+				return nil, prefixAPIError(err, prefix+" compensation")
+			}
+			step.CompensationQueue = c.Queue
+			step.CompensationPayload = c.Payload
+			step.CompensationMaxRetries = c.MaxRetries
+		}
+		steps[i] = step
+	}
+
+	saga := &models.Saga{}
+	if err := s.repo.CreateSaga(ctx, saga, steps); err != nil {
+		return nil, mapCreateError(err)
+	}
+
+	stepValues := make([]models.SagaStep, len(steps))
+	for i, step := range steps {
+		stepValues[i] = *step
+		stepValues[i].JobStatus = step.Job.Status
+	}
+	resp := toSagaResponse(saga, stepValues)
+	return &resp, nil
+}
+
+// GetSaga returns a saga with the status of each of its steps.
+func (s *JobService) GetSaga(ctx context.Context, id uint) (*dto.SagaResponseDTO, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, common.Errf(http.StatusRequestTimeout, "request timed out")
+	}
+
+	saga, steps, err := s.repo.GetSaga(ctx, id)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrSagaNotFound):
+			return nil, common.Errf(http.StatusNotFound, "saga not found")
+		case errors.Is(err, context.DeadlineExceeded), errors.Is(err, context.Canceled):
+			return nil, common.Errf(http.StatusRequestTimeout, "request timed out")
+		default:
+			return nil, common.Errf(http.StatusInternalServerError, "failed to get saga")
+		}
+	}
+
+	resp := toSagaResponse(saga, steps)
+	return &resp, nil
+}
+
+func toSagaResponse(saga *models.Saga, steps []models.SagaStep) dto.SagaResponseDTO {
+	resp := dto.SagaResponseDTO{
+		ID:          saga.ID,
+		Status:      saga.Status,
+		CurrentStep: saga.CurrentStep,
+		FailedStep:  saga.FailedStep,
+		Error:       saga.Error,
+		Steps:       make([]dto.SagaStepResponseDTO, len(steps)),
+		CreatedAt:   saga.CreatedAt,
+		FinishedAt:  saga.FinishedAt,
+	}
+	for i, step := range steps {
+		resp.Steps[i] = dto.SagaStepResponseDTO{
+			Position:           step.Position,
+			JobID:              step.JobID,
+			Status:             step.JobStatus,
+			CompensationJobID:  step.CompensationJobID,
+			CompensationStatus: step.CompensationStatus,
+		}
+	}
+	return resp
+}
+
+// prefixAPIError prefixes the message of an API error with the part of the
+// request it is about.
+func prefixAPIError(err error, prefix string) error {
+	var apiErr common.APIError
+	if errors.As(err, &apiErr) {
+		apiErr.Message = fmt.Sprintf("%s: %s", prefix, apiErr.Message)
+		return apiErr
+	}
+	return err
+}
+
+// sortWorkflow checks that keys are unique and dependencies refer to jobs
+// in the workflow, and returns the jobs in dependency order, keeping the
+// submitted order where it is free to choose. Cycles are rejected.
+func sortWorkflow(nodes []dto.WorkflowJobDTO) ([]dto.WorkflowJobDTO, error) {
+	byKey := make(map[string]int, len(nodes))
+	for i, n := range nodes {
+		if _, dup := byKey[n.Key]; dup {
+			return nil, common.NewAPIError(http.StatusBadRequest, "duplicate job key", map[string]any{"key": n.Key})
+		}
+		byKey[n.Key] = i
+	}
+
+	pending := make([]int, len(nodes))
+	dependents := make([][]int, len(nodes))
+	for i, n := range nodes {
+		for _, dep := range n.DependsOn {
+			d, ok := byKey[dep]
+			if !ok {
+				return nil, common.NewAPIError(http.StatusBadRequest, "unknown dependency", map[string]any{
+					"key":        n.Key,
+					"depends_on": dep,
+				})
+			}
+			pending[i]++
+			dependents[d] = append(dependents[d], i)
+		}
+	}
+
+	order := make([]dto.WorkflowJobDTO, 0, len(nodes))
+	var ready []int
+	for i := range nodes {
+		if pending[i] == 0 {
+			ready = append(ready, i)
+		}
+	}
+	for len(ready) > 0 {
+		i := ready[0]
+		ready = ready[1:]
+		order = append(order, nodes[i])
+		for _, d := range dependents[i] {
+			if pending[d]--; pending[d] == 0 {
+				ready = append(ready, d)
+			}
+		}
+	}
+
+	if len(order) < len(nodes) {
+		var cycle []string
+		for i, n := range nodes {
+			if pending[i] > 0 {
+				cycle = append(cycle, n.Key)
+			}
+		}
+		return nil, common.NewAPIError(http.StatusBadRequest, "workflow has a dependency cycle", map[string]any{"keys": cycle})
+	}
+	return order, nil
+}
+
+// mapCreateError maps repository errors from creating jobs to API errors.
+func mapCreateError(err error) error {
+	var throttled *ThrottledError
+	var notFound *DependencyNotFoundError
+	switch {
+	case errors.As(err, &throttled):
+		return common.NewAPIError(http.StatusConflict, "job throttled", map[string]any{
+			"job_id":      throttled.JobID,
+			"retry_after": max(int(time.Until(throttled.Until).Seconds()), 1),
+		})
+	case errors.As(err, &notFound):
+		return common.Errf(http.StatusBadRequest, "%s", notFound)
+	case errors.Is(err, context.Canceled):
+		return common.Errf(http.StatusRequestTimeout, "request was canceled")
+	case errors.Is(err, context.DeadlineExceeded):
+		return common.Errf(http.StatusRequestTimeout, "request timeout")
+	default:
+		return common.Errf(http.StatusInternalServerError, "failed to add job to database")
+	}
+}
+
+func isJSONObject(raw json.RawMessage) bool {
+	var obj map[string]json.RawMessage
+	return json.Unmarshal(raw, &obj) == nil && obj != nil
+}
+
+// NewJob validates job creation input, applies business rules and
+// returns the Job model to persist. It is used by CreateJob and by workers
+// enqueueing child jobs, which persist the model themselves.
+func (s *JobService) NewJob(dto *dto.JobCreateDTO) (*models.Job, error) {
 	if !json.Valid(dto.Payload) {
-		return common.Errf(http.StatusBadRequest, "payload must be valid JSON")
+		return nil, common.Errf(http.StatusBadRequest, "payload must be valid JSON")
+	}
+
+	if dto.PassResults && !isJSONObject(dto.Payload) {
+		return nil, common.Errf(http.StatusBadRequest, "pass_results requires the payload to be a JSON object")
+	}
+
+	if dto.ConcurrencyLimit != 0 && dto.ConcurrencyKey == "" {
+		return nil, common.Errf(http.StatusBadRequest, "concurrency_limit requires a concurrency_key")
+	}
+
+	if dto.DebounceKey == "" && (dto.DebounceWindow != 0 || dto.DebounceMode != "") {
+		return nil, common.Errf(http.StatusBadRequest, "debounce_window and debounce_mode require a debounce_key")
+	}
+	if dto.DebounceKey != "" && len(dto.DependsOn) > 0 {
+		return nil, common.Errf(http.StatusBadRequest, "debounce_key cannot be combined with depends_on")
 	}
 
 	if !slices.Contains(config.AllowedQueues, dto.Queue) {
-		return common.NewAPIError(
+		return nil, common.NewAPIError(
 			http.StatusBadRequest,
 			"invalid queue",
 			map[string]any{
@@ -53,19 +689,19 @@ func (s *JobService) CreateJob(ctx context.Context, dto *dto.JobCreateDTO) error
 	switch dto.Queue {
 	case "default":
 		if err := s.validateSendEmailPayload(dto.Payload); err != nil {
-			return err
+			return nil, err
 		}
 	case "email":
 		if err := s.validateSendEmailPayload(dto.Payload); err != nil {
-			return err
+			return nil, err
 		}
 	case "payment":
 		if err := s.validateProcessPaymentPayload(dto.Payload); err != nil {
-			return err
+			return nil, err
 		}
-	case "webhook":
+	case "webhooks":
 		if err := s.validateSendWebhookPayload(dto.Payload); err != nil {
-			return err
+			return nil, err
 		}
 	}
 
@@ -74,10 +710,30 @@ func (s *JobService) CreateJob(ctx context.Context, dto *dto.JobCreateDTO) error
 		maxRetries = 3
 	}
 
-	job := models.Job{
-		Queue:      dto.Queue,
-		Payload:    datatypes.JSON(dto.Payload),
-		MaxRetries: maxRetries,
+	job := &models.Job{
+		Queue:               dto.Queue,
+		Payload:             datatypes.JSON(dto.Payload),
+		MaxRetries:          maxRetries,
+		DependsOn:           dto.DependsOn,
+		PassResults:         dto.PassResults,
+		OnDependencyFailure: dto.OnDependencyFailure,
+	}
+
+	if dto.GroupKey != "" {
+		job.GroupKey = &dto.GroupKey
+	}
+	if dto.ConcurrencyKey != "" {
+		job.ConcurrencyKey = &dto.ConcurrencyKey
+		job.ConcurrencyLimit = max(dto.ConcurrencyLimit, 1)
+	}
+
+	if dto.DebounceKey != "" {
+		job.DebounceKey = &dto.DebounceKey
+		job.DebounceWindow = time.Duration(dto.DebounceWindow) * time.Second
+		job.DebounceMode = dto.DebounceMode
+		if job.DebounceMode == "" {
+			job.DebounceMode = config.DebounceModeDebounce
+		}
 	}
 
 	// ONLY set AvailableAt if client explicitly provided it
@@ -86,21 +742,33 @@ func (s *JobService) CreateJob(ctx context.Context, dto *dto.JobCreateDTO) error
 		job.AvailableAt = *dto.AvailableAt
 	}
 
-	if err := s.repo.Create(ctx, &job); err != nil {
-		switch {
-		case errors.Is(err, context.Canceled):
-			return common.Errf(http.StatusRequestTimeout, "request was canceled")
-		case errors.Is(err, context.DeadlineExceeded):
-			return common.Errf(http.StatusRequestTimeout, "request timeout")
-		default:
-			return common.Errf(http.StatusInternalServerError, "failed to add job to database")
+	if dto.ExpiresAt != nil {
+		if dto.AvailableAt != nil && !dto.ExpiresAt.After(*dto.AvailableAt) {
+			return nil, common.Errf(http.StatusBadRequest, "expires_at must be after available_at")
+		}
+		job.ExpiresAt = dto.ExpiresAt
+	} else if ttl := s.queueTTLs[dto.Queue]; ttl > 0 {
+		// The TTL runs from when the job can first be acquired: its
+		// available_at, or the end of the debounce window. Jobs that start
+		// waiting get their expires_at from TTLSeconds when they are
+		// queued instead.
+		job.TTLSeconds = int(ttl.Round(time.Second) / time.Second)
+		start := time.Now()
+		if job.DebounceMode == config.DebounceModeDebounce {
+			start = start.Add(job.DebounceWindow)
 		}
+		if dto.AvailableAt != nil && dto.AvailableAt.After(start) {
+			start = *dto.AvailableAt
+		}
+		expiresAt := start.Add(ttl)
+		job.ExpiresAt = &expiresAt
 	}
 
-	return nil
+	return job, nil
 }
 
-// GetJobByID retrieves a job by its ID from the repository.
+// GetJobByID retrieves a job by its ID from the repository, falling back
+// to the archive for jobs that have been archived.
 // It maps repository errors to appropriate API errors
 // (e.g., not found, timeout, or internal failure).
 func (s *JobService) GetJobByID(ctx context.Context, id uint) (*dto.JobResponseDTO, error) {
@@ -112,6 +780,9 @@ func (s *JobService) GetJobByID(ctx context.Context, id uint) (*dto.JobResponseD
 	}
 
 	job, err := s.repo.Get(ctx, id)
+	if isNotFound(err) {
+		job, err = s.repo.GetArchived(ctx, id)
+	}
 	if err != nil {
 		if errors.Is(err, context.DeadlineExceeded) ||
 			errors.Is(err, context.Canceled) {
@@ -121,8 +792,7 @@ func (s *JobService) GetJobByID(ctx context.Context, id uint) (*dto.JobResponseD
 			)
 		}
 
-		if errors.Is(err, gorm.ErrRecordNotFound) ||
-			strings.Contains(err.Error(), "job not found") {
+		if isNotFound(err) {
 			return &dto.JobResponseDTO{}, common.Errf(
 				http.StatusNotFound,
 				"job not found",
@@ -135,18 +805,13 @@ func (s *JobService) GetJobByID(ctx context.Context, id uint) (*dto.JobResponseD
 		)
 	}
 
-	return &dto.JobResponseDTO{
-		ID:         job.ID,
-		Queue:      job.Queue,
-		Payload:    json.RawMessage(job.Payload),
-		Status:     job.Status,
-		Attempts:   job.Attempts,
-		MaxRetries: job.MaxRetries,
-		Result:     json.RawMessage(job.Result),
-		Error:      job.Error,
-		CreatedAt:  job.CreatedAt,
-		UpdatedAt:  job.UpdatedAt,
-	}, nil
+	res := toJobResponse(job)
+	return &res, nil
+}
+
+func isNotFound(err error) bool {
+	return err != nil && (errors.Is(err, gorm.ErrRecordNotFound) ||
+		strings.Contains(err.Error(), "job not found"))
 }
 
 // UpdateStatus updates the status of a job identified by its ID.
@@ -271,29 +936,169 @@ func (s *JobService) ListJobs(ctx context.Context, queue string) ([]dto.JobRespo
 
 	dtos := make([]dto.JobResponseDTO, len(jobs))
 	for i, job := range jobs {
-		dtos[i] = dto.JobResponseDTO{
-			ID:         job.ID,
-			Queue:      job.Queue,
-			Payload:    json.RawMessage(job.Payload),
-			Status:     job.Status,
-			Attempts:   job.Attempts,
-			MaxRetries: job.MaxRetries,
-			Result:     json.RawMessage(job.Result),
-			Error:      job.Error,
-			CreatedAt:  job.CreatedAt,
-			UpdatedAt:  job.UpdatedAt,
-		}
+		dtos[i] = toJobResponse(&job)
 	}
 
 	return dtos, nil
 }
 
+// QueueStats returns the number of jobs in each allowed queue by status.
+func (s *JobService) QueueStats(ctx context.Context) ([]dto.QueueStatsDTO, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, common.Errf(http.StatusRequestTimeout, "request timed out")
+	}
+
+	counts, err := s.repo.QueueStats(ctx)
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) ||
+			errors.Is(err, context.Canceled) {
+			return nil, common.Errf(http.StatusRequestTimeout, "request timed out")
+		}
+		return nil, common.Errf(http.StatusInternalServerError, "failed to get queue stats")
+	}
+
+	stats := make([]dto.QueueStatsDTO, len(config.AllowedQueues))
+	for i, queue := range config.AllowedQueues {
+		stats[i] = dto.QueueStatsDTO{
+			Queue:  queue,
+			Counts: make(map[config.JobStatus]int64, len(config.JobStatuses)),
+		}
+		for _, status := range config.JobStatuses {
+			n := counts[queue][status]
+			stats[i].Counts[status] = n
+			stats[i].Total += n
+		}
+	}
+	return stats, nil
+}
+
+// RetentionReport returns, for each queue and status with an archive age
+// or retention period, how many jobs the pruner would archive or delete if
+// it ran now. Nothing is moved or deleted. Archiving runs first, so jobs
+// counted for both are archived.
+func (s *JobService) RetentionReport(ctx context.Context) ([]dto.RetentionReportDTO, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, common.Errf(http.StatusRequestTimeout, "request timed out")
+	}
+
+	now := time.Now()
+	report := []dto.RetentionReportDTO{}
+	for _, policy := range []struct {
+		action string
+		rules  []RetentionRule
+	}{
+		{dto.RetentionActionArchive, s.archive.Rules()},
+		{dto.RetentionActionDelete, s.retention.Rules()},
+	} {
+		for _, rule := range policy.rules {
+			cutoff := now.Add(-rule.MaxAge)
+			n, err := s.repo.CountPrunable(ctx, rule.Queue, rule.Status, cutoff)
+			if err != nil {
+				if errors.Is(err, context.DeadlineExceeded) ||
+					errors.Is(err, context.Canceled) {
+					return nil, common.Errf(http.StatusRequestTimeout, "request timed out")
+				}
+				return nil, common.Errf(http.StatusInternalServerError, "failed to build retention report")
+			}
+			report = append(report, dto.RetentionReportDTO{
+				Queue:     rule.Queue,
+				Status:    rule.Status,
+				Action:    policy.action,
+				RetainFor: rule.MaxAge.String(),
+				Cutoff:    cutoff,
+				Jobs:      n,
+			})
+		}
+	}
+	return report, nil
+}
+
+func toJobResponse(job *models.Job) dto.JobResponseDTO {
+	return dto.JobResponseDTO{
+		ID:               job.ID,
+		Queue:            job.Queue,
+		Payload:          json.RawMessage(job.Payload),
+		Status:           job.Status,
+		Attempts:         job.Attempts,
+		MaxRetries:       job.MaxRetries,
+		Result:           json.RawMessage(job.Result),
+		Error:            job.Error,
+		ParentID:         job.ParentID,
+		BatchID:          job.BatchID,
+		GroupKey:         job.GroupKey,
+		ConcurrencyKey:   job.ConcurrencyKey,
+		ConcurrencyLimit: job.ConcurrencyLimit,
+		DebounceKey:      job.DebounceKey,
+		ExpiresAt:        job.ExpiresAt,
+		ArchivedAt:       job.ArchivedAt,
+		CreatedAt:        job.CreatedAt,
+		UpdatedAt:        job.UpdatedAt,
+	}
+}
+
 func (s *JobService) validateSendEmailPayload(raw json.RawMessage) error {
-	return validatePayload[dto.SendEmailPayload](raw)
+	if err := validatePayload[dto.SendEmailPayload](raw); err != nil {
+		return err
+	}
+
+	var payload dto.SendEmailPayload
+	json.Unmarshal(raw, &payload)
+	if payload.Template == "" {
+		return nil
+	}
+	return s.validateEmailTemplate(payload.Template, payload.Data)
+}
+
+// validateEmailTemplate renders the template with the job's data so that
+// unknown templates and missing variables are rejected at enqueue time
+// instead of failing in the worker.
+func (s *JobService) validateEmailTemplate(name string, data map[string]any) error {
+	if s.templates == nil {
+		return common.Errf(http.StatusBadRequest, "email templates are not configured")
+	}
+
+	_, err := s.templates.Render(name, data)
+	if err == nil {
+		return nil
+	}
+
+	var missing *templates.MissingVariableError
+	switch {
+	case errors.Is(err, templates.ErrNotFound):
+		return common.NewAPIError(http.StatusBadRequest, "unknown email template", map[string]any{
+			"template": name,
+		})
+	case errors.As(err, &missing):
+		return common.NewAPIError(http.StatusBadRequest, "missing template variable", map[string]any{
+			"template": name,
+			"variable": missing.Variable,
+		})
+	default:
+		return common.NewAPIError(http.StatusBadRequest, "email template rendering failed", map[string]any{
+			"template": name,
+			"error":    err.Error(),
+		})
+	}
 }
 
+// validateProcessPaymentPayload also rejects amounts the ledger cannot
+// store exactly, so a payment is never recorded at a different amount
+// than it was created with.
 func (s *JobService) validateProcessPaymentPayload(raw json.RawMessage) error {
-	return validatePayload[dto.ProcessPaymentPayload](raw)
+	if err := validatePayload[dto.ProcessPaymentPayload](raw); err != nil {
+		return err
+	}
+
+	var payment dto.ProcessPaymentPayload
+	if err := json.Unmarshal(raw, &payment); err != nil {
+		return err
+	}
+	if _, ok := models.AmountUnits(payment.Amount); !ok {
+		return common.NewAPIError(http.StatusBadRequest, "payload validation failed", map[string]any{
+			"Amount": fmt.Sprintf("at most %d decimal places", models.AmountScale),
+		})
+	}
+	return nil
 }
 
 func (s *JobService) validateSendWebhookPayload(raw json.RawMessage) error {
@@ -5,13 +5,17 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"net/http"
 	"testing"
+	"testing/fstest"
 	"time"
 
+	"github.com/joshu-sajeev/goqueue/common"
 	"github.com/joshu-sajeev/goqueue/internal/config"
 	"github.com/joshu-sajeev/goqueue/internal/dto"
 	"github.com/joshu-sajeev/goqueue/internal/mocks"
 	"github.com/joshu-sajeev/goqueue/internal/models"
+	"github.com/joshu-sajeev/goqueue/internal/templates"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"gorm.io/datatypes"
@@ -172,6 +176,19 @@ func TestJobService_CreateJob(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "payment amount finer than the ledger scale",
+			dto: &dto.JobCreateDTO{
+				Queue:   "payment",
+				Payload: []byte(`{"payment_id":"pay_123","user_id":"user_456","amount":100.00005,"currency":"USD","method":"card"}`),
+			},
+			setupMock: func(m *mocks.JobRepoMock) {},
+			setupCtx: func() context.Context {
+				return context.Background()
+			},
+			wantErr:     true,
+			errContains: "payload validation failed",
+		},
 		{
 			name: "empty JSON object payload",
 			dto: &dto.JobCreateDTO{
@@ -323,6 +340,862 @@ func TestJobService_CreateJob(t *testing.T) {
 	}
 }
 
+func TestJobService_CreateJob_EmailTemplate(t *testing.T) {
+	store, err := templates.Load(fstest.MapFS{
+		"welcome/subject.tmpl":  {Data: []byte("Welcome, {{.name}}")},
+		"welcome/body.txt.tmpl": {Data: []byte("Your plan is {{.plan}}.")},
+	})
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name        string
+		payload     string
+		templates   *templates.Store
+		wantErr     bool
+		errContains string
+		wantFields  map[string]any
+	}{
+		{
+			name:      "template with all variables",
+			payload:   `{"to":"a@example.com","template":"welcome","data":{"name":"Ann","plan":"pro"}}`,
+			templates: store,
+		},
+		{
+			name:        "missing variable",
+			payload:     `{"to":"a@example.com","template":"welcome","data":{"name":"Ann"}}`,
+			templates:   store,
+			wantErr:     true,
+			errContains: "missing template variable",
+			wantFields:  map[string]any{"template": "welcome", "variable": "plan"},
+		},
+		{
+			name:        "unknown template",
+			payload:     `{"to":"a@example.com","template":"nope","data":{}}`,
+			templates:   store,
+			wantErr:     true,
+			errContains: "unknown email template",
+			wantFields:  map[string]any{"template": "nope"},
+		},
+		{
+			name:        "templates not configured",
+			payload:     `{"to":"a@example.com","template":"welcome","data":{}}`,
+			wantErr:     true,
+			errContains: "email templates are not configured",
+		},
+		{
+			name:        "template together with subject",
+			payload:     `{"to":"a@example.com","template":"welcome","subject":"Hi","data":{"name":"Ann","plan":"pro"}}`,
+			templates:   store,
+			wantErr:     true,
+			errContains: "payload validation failed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(mocks.JobRepoMock)
+			if !tt.wantErr {
+				mockRepo.On("Create", mock.Anything, mock.AnythingOfType("*models.Job")).Return(nil)
+			}
+
+			s := NewJobService(mockRepo)
+			if tt.templates != nil {
+				s.SetEmailTemplates(tt.templates)
+			}
+			err := s.CreateJob(context.Background(), &dto.JobCreateDTO{
+				Queue:   "email",
+				Payload: json.RawMessage(tt.payload),
+			})
+
+			if tt.wantErr {
+				assert.ErrorContains(t, err, tt.errContains)
+				if tt.wantFields != nil {
+					var apiErr common.APIError
+					assert.True(t, errors.As(err, &apiErr))
+					assert.Equal(t, http.StatusBadRequest, apiErr.Status)
+					assert.Equal(t, tt.wantFields, apiErr.Fields)
+				}
+				mockRepo.AssertNumberOfCalls(t, "Create", 0)
+			} else {
+				assert.NoError(t, err)
+			}
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestJobService_CreateJob_Dependencies(t *testing.T) {
+	payload := json.RawMessage(`{"url":"https://example.com","method":"POST","body":{},"timeout":10}`)
+
+	t.Run("dependency settings are passed to the repository", func(t *testing.T) {
+		mockRepo := new(mocks.JobRepoMock)
+		mockRepo.On("Create", mock.Anything, mock.MatchedBy(func(j *models.Job) bool {
+			return assert.ObjectsAreEqual([]uint{1, 2}, j.DependsOn) &&
+				j.PassResults && j.OnDependencyFailure == "skip"
+		})).Return(nil)
+
+		err := NewJobService(mockRepo).CreateJob(context.Background(), &dto.JobCreateDTO{
+			Queue:               "webhooks",
+			Payload:             payload,
+			DependsOn:           []uint{1, 2},
+			PassResults:         true,
+			OnDependencyFailure: "skip",
+		})
+		assert.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("missing dependency", func(t *testing.T) {
+		mockRepo := new(mocks.JobRepoMock)
+		mockRepo.On("Create", mock.Anything, mock.Anything).
+			Return(fmt.Errorf("create job: %w", &DependencyNotFoundError{IDs: []uint{7}}))
+
+		err := NewJobService(mockRepo).CreateJob(context.Background(), &dto.JobCreateDTO{
+			Queue:     "webhooks",
+			Payload:   payload,
+			DependsOn: []uint{7},
+		})
+		var apiErr common.APIError
+		assert.True(t, errors.As(err, &apiErr))
+		assert.Equal(t, http.StatusBadRequest, apiErr.Status)
+		assert.Equal(t, "dependency not found: [7]", apiErr.Message)
+	})
+
+	t.Run("pass_results requires an object payload", func(t *testing.T) {
+		mockRepo := new(mocks.JobRepoMock)
+		err := NewJobService(mockRepo).CreateJob(context.Background(), &dto.JobCreateDTO{
+			Queue:       "default",
+			Payload:     json.RawMessage(`[1,2]`),
+			PassResults: true,
+		})
+		assert.ErrorContains(t, err, "pass_results requires the payload to be a JSON object")
+		mockRepo.AssertNumberOfCalls(t, "Create", 0)
+	})
+}
+
+func TestJobService_CreateJob_GroupKey(t *testing.T) {
+	payload := json.RawMessage(`{"url":"https://example.com","method":"POST","body":{},"timeout":10}`)
+
+	mockRepo := new(mocks.JobRepoMock)
+	mockRepo.On("Create", mock.Anything, mock.MatchedBy(func(j *models.Job) bool {
+		return j.GroupKey != nil && *j.GroupKey == "customer-42"
+	})).Return(nil).Once()
+	mockRepo.On("Create", mock.Anything, mock.MatchedBy(func(j *models.Job) bool {
+		return j.GroupKey == nil
+	})).Return(nil).Once()
+
+	svc := NewJobService(mockRepo)
+	assert.NoError(t, svc.CreateJob(context.Background(), &dto.JobCreateDTO{Queue: "webhooks", Payload: payload, GroupKey: "customer-42"}))
+	assert.NoError(t, svc.CreateJob(context.Background(), &dto.JobCreateDTO{Queue: "webhooks", Payload: payload}))
+	mockRepo.AssertExpectations(t)
+}
+
+func TestJobService_CreateJob_ConcurrencyKey(t *testing.T) {
+	payload := json.RawMessage(`{"url":"https://example.com","method":"POST","body":{},"timeout":10}`)
+
+	tests := []struct {
+		name      string
+		key       string
+		limit     int
+		wantLimit int
+		wantErr   string
+	}{
+		{name: "limit defaults to one", key: "account-1", wantLimit: 1},
+		{name: "explicit limit", key: "account-1", limit: 3, wantLimit: 3},
+		{name: "limit without key", limit: 2, wantErr: "concurrency_limit requires a concurrency_key"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(mocks.JobRepoMock)
+			if tt.wantErr == "" {
+				mockRepo.On("Create", mock.Anything, mock.MatchedBy(func(j *models.Job) bool {
+					return *j.ConcurrencyKey == tt.key && j.ConcurrencyLimit == tt.wantLimit
+				})).Return(nil)
+			}
+
+			err := NewJobService(mockRepo).CreateJob(context.Background(), &dto.JobCreateDTO{
+				Queue:            "webhooks",
+				Payload:          payload,
+				ConcurrencyKey:   tt.key,
+				ConcurrencyLimit: tt.limit,
+			})
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestJobService_CreateJob_Debounce(t *testing.T) {
+	payload := json.RawMessage(`{"url":"https://example.com","method":"POST","body":{},"timeout":10}`)
+
+	t.Run("debounce settings are passed to the repository", func(t *testing.T) {
+		mockRepo := new(mocks.JobRepoMock)
+		mockRepo.On("Create", mock.Anything, mock.MatchedBy(func(j *models.Job) bool {
+			return *j.DebounceKey == "doc-1" && j.DebounceWindow == 30*time.Second && j.DebounceMode == config.DebounceModeDebounce
+		})).Return(nil)
+
+		err := NewJobService(mockRepo).CreateJob(context.Background(), &dto.JobCreateDTO{
+			Queue:          "webhooks",
+			Payload:        payload,
+			DebounceKey:    "doc-1",
+			DebounceWindow: 30,
+		})
+		assert.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("throttled enqueue", func(t *testing.T) {
+		mockRepo := new(mocks.JobRepoMock)
+		mockRepo.On("Create", mock.Anything, mock.Anything).
+			Return(fmt.Errorf("create job: %w", &ThrottledError{JobID: 12, Until: time.Now().Add(20 * time.Second)}))
+
+		err := NewJobService(mockRepo).CreateJob(context.Background(), &dto.JobCreateDTO{
+			Queue:          "webhooks",
+			Payload:        payload,
+			DebounceKey:    "doc-1",
+			DebounceWindow: 30,
+			DebounceMode:   config.DebounceModeThrottle,
+		})
+		var apiErr common.APIError
+		assert.True(t, errors.As(err, &apiErr))
+		assert.Equal(t, http.StatusConflict, apiErr.Status)
+		assert.Equal(t, uint(12), apiErr.Fields["job_id"])
+		assert.InDelta(t, 20, apiErr.Fields["retry_after"], 1)
+	})
+
+	tests := []struct {
+		name    string
+		dto     dto.JobCreateDTO
+		wantErr string
+	}{
+		{
+			"window without key",
+			dto.JobCreateDTO{Queue: "webhooks", Payload: payload, DebounceWindow: 10},
+			"debounce_window and debounce_mode require a debounce_key",
+		},
+		{
+			"combined with dependencies",
+			dto.JobCreateDTO{Queue: "webhooks", Payload: payload, DebounceKey: "doc-1", DebounceWindow: 10, DependsOn: []uint{1}},
+			"debounce_key cannot be combined with depends_on",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(mocks.JobRepoMock)
+			err := NewJobService(mockRepo).CreateJob(context.Background(), &tt.dto)
+			assert.ErrorContains(t, err, tt.wantErr)
+			mockRepo.AssertNumberOfCalls(t, "Create", 0)
+		})
+	}
+}
+
+func TestJobService_CreateJob_ExpiresAt(t *testing.T) {
+	payload := json.RawMessage(`{"url":"https://example.com","method":"POST","body":{},"timeout":10}`)
+
+	t.Run("explicit expires_at", func(t *testing.T) {
+		expiresAt := time.Now().Add(time.Minute)
+		mockRepo := new(mocks.JobRepoMock)
+		mockRepo.On("Create", mock.Anything, mock.MatchedBy(func(j *models.Job) bool {
+			return j.ExpiresAt != nil && j.ExpiresAt.Equal(expiresAt) && j.TTLSeconds == 0
+		})).Return(nil)
+
+		svc := NewJobService(mockRepo)
+		svc.SetQueueTTLs(map[string]time.Duration{"webhooks": time.Hour})
+		err := svc.CreateJob(context.Background(), &dto.JobCreateDTO{
+			Queue:     "webhooks",
+			Payload:   payload,
+			ExpiresAt: &expiresAt,
+		})
+		assert.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("queue TTL runs from available_at", func(t *testing.T) {
+		availableAt := time.Now().Add(time.Hour)
+		mockRepo := new(mocks.JobRepoMock)
+		mockRepo.On("Create", mock.Anything, mock.MatchedBy(func(j *models.Job) bool {
+			return j.ExpiresAt != nil && j.ExpiresAt.Equal(availableAt.Add(5*time.Minute)) && j.TTLSeconds == 300
+		})).Return(nil)
+
+		svc := NewJobService(mockRepo)
+		svc.SetQueueTTLs(map[string]time.Duration{"webhooks": 5 * time.Minute})
+		err := svc.CreateJob(context.Background(), &dto.JobCreateDTO{
+			Queue:       "webhooks",
+			Payload:     payload,
+			AvailableAt: &availableAt,
+		})
+		assert.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("no TTL for queue", func(t *testing.T) {
+		mockRepo := new(mocks.JobRepoMock)
+		mockRepo.On("Create", mock.Anything, mock.MatchedBy(func(j *models.Job) bool {
+			return j.ExpiresAt == nil
+		})).Return(nil)
+
+		svc := NewJobService(mockRepo)
+		svc.SetQueueTTLs(map[string]time.Duration{"email": 5 * time.Minute})
+		err := svc.CreateJob(context.Background(), &dto.JobCreateDTO{Queue: "webhooks", Payload: payload})
+		assert.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("expires before available", func(t *testing.T) {
+		availableAt := time.Now().Add(time.Hour)
+		expiresAt := availableAt.Add(-time.Minute)
+		mockRepo := new(mocks.JobRepoMock)
+
+		err := NewJobService(mockRepo).CreateJob(context.Background(), &dto.JobCreateDTO{
+			Queue:       "webhooks",
+			Payload:     payload,
+			AvailableAt: &availableAt,
+			ExpiresAt:   &expiresAt,
+		})
+		assert.ErrorContains(t, err, "expires_at must be after available_at")
+		mockRepo.AssertNumberOfCalls(t, "Create", 0)
+	})
+}
+
+func TestParseQueueTTLs(t *testing.T) {
+	ttls, err := ParseQueueTTLs(" email=5m, webhooks=1h ")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]time.Duration{"email": 5 * time.Minute, "webhooks": time.Hour}, ttls)
+
+	ttls, err = ParseQueueTTLs("")
+	assert.NoError(t, err)
+	assert.Empty(t, ttls)
+
+	for _, v := range []string{"email", "=5m", "email=soon", "email=-5m", "email=500ms"} {
+		_, err := ParseQueueTTLs(v)
+		assert.Error(t, err, v)
+	}
+}
+
+func TestJobService_QueueStats(t *testing.T) {
+	t.Run("every queue and status is reported", func(t *testing.T) {
+		mockRepo := new(mocks.JobRepoMock)
+		mockRepo.On("QueueStats", mock.Anything).Return(map[string]map[config.JobStatus]int64{
+			"email": {config.JobStatusQueued: 3, config.JobStatusExpired: 2},
+		}, nil)
+
+		stats, err := NewJobService(mockRepo).QueueStats(context.Background())
+		assert.NoError(t, err)
+		assert.Len(t, stats, len(config.AllowedQueues))
+		for _, st := range stats {
+			assert.Len(t, st.Counts, len(config.JobStatuses))
+			if st.Queue == "email" {
+				assert.Equal(t, int64(5), st.Total)
+				assert.Equal(t, int64(2), st.Counts[config.JobStatusExpired])
+				assert.Equal(t, int64(0), st.Counts[config.JobStatusRunning])
+			} else {
+				assert.Equal(t, int64(0), st.Total)
+			}
+		}
+	})
+
+	t.Run("repository error", func(t *testing.T) {
+		mockRepo := new(mocks.JobRepoMock)
+		mockRepo.On("QueueStats", mock.Anything).Return(nil, errors.New("db down"))
+
+		_, err := NewJobService(mockRepo).QueueStats(context.Background())
+		var apiErr common.APIError
+		assert.True(t, errors.As(err, &apiErr))
+		assert.Equal(t, http.StatusInternalServerError, apiErr.Status)
+	})
+}
+
+func TestJobService_RetentionReport(t *testing.T) {
+	t.Run("counts jobs past retention", func(t *testing.T) {
+		mockRepo := new(mocks.JobRepoMock)
+		mockRepo.On("CountPrunable", mock.Anything, "email", config.JobStatusCompleted, mock.MatchedBy(func(cutoff time.Time) bool {
+			return time.Since(cutoff) >= 7*24*time.Hour && time.Since(cutoff) < 7*24*time.Hour+time.Minute
+		})).Return(int64(40), nil)
+		mockRepo.On("CountPrunable", mock.Anything, "email", config.JobStatusFailed, mock.Anything).Return(int64(2), nil)
+
+		svc := NewJobService(mockRepo)
+		svc.SetRetention(Retention{"email": {
+			config.JobStatusCompleted: 7 * 24 * time.Hour,
+			config.JobStatusFailed:    30 * 24 * time.Hour,
+		}})
+		report, err := svc.RetentionReport(context.Background())
+		assert.NoError(t, err)
+		assert.Len(t, report, 2)
+		assert.Equal(t, config.JobStatusCompleted, report[0].Status)
+		assert.Equal(t, "168h0m0s", report[0].RetainFor)
+		assert.Equal(t, int64(40), report[0].Jobs)
+		assert.Equal(t, int64(2), report[1].Jobs)
+		mockRepo.AssertExpectations(t)
+		mockRepo.AssertNumberOfCalls(t, "PruneJobs", 0)
+	})
+
+	t.Run("archive rules come first", func(t *testing.T) {
+		mockRepo := new(mocks.JobRepoMock)
+		mockRepo.On("CountPrunable", mock.Anything, "email", config.JobStatusCompleted, mock.Anything).Return(int64(5), nil)
+
+		svc := NewJobService(mockRepo)
+		svc.SetArchive(Retention{"email": {config.JobStatusCompleted: 24 * time.Hour}})
+		svc.SetRetention(Retention{"email": {config.JobStatusCompleted: 7 * 24 * time.Hour}})
+		report, err := svc.RetentionReport(context.Background())
+		assert.NoError(t, err)
+		assert.Len(t, report, 2)
+		assert.Equal(t, dto.RetentionActionArchive, report[0].Action)
+		assert.Equal(t, "24h0m0s", report[0].RetainFor)
+		assert.Equal(t, dto.RetentionActionDelete, report[1].Action)
+		mockRepo.AssertNumberOfCalls(t, "ArchiveJobs", 0)
+	})
+
+	t.Run("no retention", func(t *testing.T) {
+		report, err := NewJobService(new(mocks.JobRepoMock)).RetentionReport(context.Background())
+		assert.NoError(t, err)
+		assert.Empty(t, report)
+	})
+
+	t.Run("repository error", func(t *testing.T) {
+		mockRepo := new(mocks.JobRepoMock)
+		mockRepo.On("CountPrunable", mock.Anything, "email", config.JobStatusCompleted, mock.Anything).
+			Return(int64(0), errors.New("db down"))
+
+		svc := NewJobService(mockRepo)
+		svc.SetRetention(Retention{"email": {config.JobStatusCompleted: time.Hour}})
+		_, err := svc.RetentionReport(context.Background())
+		var apiErr common.APIError
+		assert.True(t, errors.As(err, &apiErr))
+		assert.Equal(t, http.StatusInternalServerError, apiErr.Status)
+	})
+}
+
+func TestJobService_CreateJobs(t *testing.T) {
+	payload := json.RawMessage(`{"url":"https://example.com","method":"POST","body":{},"timeout":10}`)
+	valid := dto.JobCreateDTO{Queue: "webhooks", Payload: payload}
+
+	t.Run("valid jobs are created and invalid ones reported", func(t *testing.T) {
+		mockRepo := new(mocks.JobRepoMock)
+		mockRepo.On("CreateJobs", mock.Anything, mock.MatchedBy(func(jobs []*models.Job) bool {
+			return len(jobs) == 2
+		})).Run(func(args mock.Arguments) {
+			for i, j := range args.Get(1).([]*models.Job) {
+				j.ID = uint(100 + i)
+			}
+		}).Return(nil)
+
+		resp, err := NewJobService(mockRepo).CreateJobs(context.Background(), &dto.BulkCreateDTO{Jobs: []dto.JobCreateDTO{
+			valid,
+			{Payload: payload},
+			{Queue: "nope", Payload: payload},
+			{Queue: "webhooks", Payload: payload, DependsOn: []uint{1}},
+			valid,
+		}})
+		assert.NoError(t, err)
+		assert.Equal(t, 2, resp.Created)
+		assert.Equal(t, 3, resp.Failed)
+		assert.Len(t, resp.JobIDs, 5)
+		assert.Equal(t, uint(100), *resp.JobIDs[0])
+		assert.Nil(t, resp.JobIDs[1])
+		assert.Equal(t, uint(101), *resp.JobIDs[4])
+
+		assert.Len(t, resp.Errors, 3)
+		assert.Equal(t, 1, resp.Errors[0].Index)
+		assert.Equal(t, "validation failed", resp.Errors[0].Error)
+		assert.Equal(t, "failed required", resp.Errors[0].Fields["Queue"])
+		assert.Equal(t, 2, resp.Errors[1].Index)
+		assert.Equal(t, "invalid queue", resp.Errors[1].Error)
+		assert.Equal(t, 3, resp.Errors[2].Index)
+		assert.Equal(t, "depends_on is not supported in bulk creation", resp.Errors[2].Error)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("all or nothing", func(t *testing.T) {
+		mockRepo := new(mocks.JobRepoMock)
+
+		_, err := NewJobService(mockRepo).CreateJobs(context.Background(), &dto.BulkCreateDTO{
+			Jobs:         []dto.JobCreateDTO{valid, {Payload: payload}},
+			AllOrNothing: true,
+		})
+		var apiErr common.APIError
+		assert.True(t, errors.As(err, &apiErr))
+		assert.Equal(t, http.StatusBadRequest, apiErr.Status)
+		errs := apiErr.Fields["errors"].([]dto.BulkItemErrorDTO)
+		assert.Len(t, errs, 1)
+		assert.Equal(t, 1, errs[0].Index)
+		mockRepo.AssertNumberOfCalls(t, "CreateJobs", 0)
+	})
+
+	t.Run("no valid jobs", func(t *testing.T) {
+		mockRepo := new(mocks.JobRepoMock)
+
+		_, err := NewJobService(mockRepo).CreateJobs(context.Background(), &dto.BulkCreateDTO{
+			Jobs: []dto.JobCreateDTO{{Payload: payload}},
+		})
+		var apiErr common.APIError
+		assert.True(t, errors.As(err, &apiErr))
+		assert.Equal(t, http.StatusBadRequest, apiErr.Status)
+		mockRepo.AssertNumberOfCalls(t, "CreateJobs", 0)
+	})
+
+	t.Run("repository error", func(t *testing.T) {
+		mockRepo := new(mocks.JobRepoMock)
+		mockRepo.On("CreateJobs", mock.Anything, mock.Anything).Return(errors.New("db down"))
+
+		_, err := NewJobService(mockRepo).CreateJobs(context.Background(), &dto.BulkCreateDTO{
+			Jobs: []dto.JobCreateDTO{valid},
+		})
+		var apiErr common.APIError
+		assert.True(t, errors.As(err, &apiErr))
+		assert.Equal(t, http.StatusInternalServerError, apiErr.Status)
+	})
+}
+
+func TestJobService_CreateBulkOperation(t *testing.T) {
+	after := time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)
+	before := after.Add(time.Hour)
+
+	t.Run("operation is saved with the filter", func(t *testing.T) {
+		mockRepo := new(mocks.JobRepoMock)
+		mockRepo.On("CreateBulkOperation", mock.Anything, mock.MatchedBy(func(op *models.BulkOperation) bool {
+			return op.Action == models.BulkActionRetry && op.FilterQueue == "email" &&
+				op.FilterStatus == config.JobStatusFailed && op.ErrorContains == "timeout" &&
+				op.CreatedAfter.Equal(after) && op.TargetQueue == ""
+		})).
+			Run(func(args mock.Arguments) {
+				op := args.Get(1).(*models.BulkOperation)
+				op.ID, op.Status, op.Total = 4, models.BulkOperationStatusPending, 120
+			}).
+			Return(nil)
+
+		resp, err := NewJobService(mockRepo).CreateBulkOperation(context.Background(), models.BulkActionRetry, &dto.BulkOperationCreateDTO{
+			Filter: dto.BulkFilterDTO{Queue: "email", Status: config.JobStatusFailed, CreatedAfter: &after, ErrorContains: "timeout"},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, uint(4), resp.ID)
+		assert.Equal(t, models.BulkOperationStatusPending, resp.Status)
+		assert.Equal(t, 120, resp.Total)
+		assert.Equal(t, "email", resp.Filter.Queue)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("move sets the target queue", func(t *testing.T) {
+		mockRepo := new(mocks.JobRepoMock)
+		mockRepo.On("CreateBulkOperation", mock.Anything, mock.MatchedBy(func(op *models.BulkOperation) bool {
+			return op.Action == models.BulkActionMove && op.TargetQueue == "default"
+		})).Return(nil)
+
+		_, err := NewJobService(mockRepo).CreateBulkOperation(context.Background(), models.BulkActionMove, &dto.BulkOperationCreateDTO{
+			Filter:      dto.BulkFilterDTO{Queue: "email"},
+			TargetQueue: "default",
+		})
+		assert.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+
+	tests := []struct {
+		name   string
+		action string
+		req    dto.BulkOperationCreateDTO
+		errMsg string
+	}{
+		{"unknown action", "pause", dto.BulkOperationCreateDTO{Filter: dto.BulkFilterDTO{Queue: "email"}}, "invalid bulk action"},
+		{"empty filter", models.BulkActionDelete, dto.BulkOperationCreateDTO{}, "filter must set at least one"},
+		{"invalid queue", models.BulkActionDelete, dto.BulkOperationCreateDTO{Filter: dto.BulkFilterDTO{Queue: "sms"}}, "invalid queue"},
+		{"status not eligible", models.BulkActionRetry, dto.BulkOperationCreateDTO{Filter: dto.BulkFilterDTO{Status: config.JobStatusQueued}}, "invalid status for this action"},
+		{"running jobs cannot be deleted", models.BulkActionDelete, dto.BulkOperationCreateDTO{Filter: dto.BulkFilterDTO{Status: config.JobStatusRunning}}, "invalid status for this action"},
+		{"reversed range", models.BulkActionCancel, dto.BulkOperationCreateDTO{Filter: dto.BulkFilterDTO{CreatedAfter: &before, CreatedBefore: &after}}, "created_before must be after created_after"},
+		{"move without target", models.BulkActionMove, dto.BulkOperationCreateDTO{Filter: dto.BulkFilterDTO{Queue: "email"}}, "invalid target_queue"},
+		{"move to the same queue", models.BulkActionMove, dto.BulkOperationCreateDTO{Filter: dto.BulkFilterDTO{Queue: "email"}, TargetQueue: "email"}, "target_queue must differ"},
+		{"target for another action", models.BulkActionCancel, dto.BulkOperationCreateDTO{Filter: dto.BulkFilterDTO{Queue: "email"}, TargetQueue: "default"}, "target_queue is only used by move"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(mocks.JobRepoMock)
+
+			_, err := NewJobService(mockRepo).CreateBulkOperation(context.Background(), tt.action, &tt.req)
+			var apiErr common.APIError
+			assert.True(t, errors.As(err, &apiErr))
+			assert.Equal(t, http.StatusBadRequest, apiErr.Status)
+			assert.Contains(t, apiErr.Message, tt.errMsg)
+			mockRepo.AssertNotCalled(t, "CreateBulkOperation", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestJobService_GetBulkOperation(t *testing.T) {
+	t.Run("running operation", func(t *testing.T) {
+		mockRepo := new(mocks.JobRepoMock)
+		mockRepo.On("GetBulkOperation", mock.Anything, uint(4)).
+			Return(&models.BulkOperation{
+				ID: 4, Action: models.BulkActionCancel, Status: models.BulkOperationStatusRunning,
+				FilterQueue: "webhooks", Total: 1000, Processed: 500,
+			}, nil)
+
+		resp, err := NewJobService(mockRepo).GetBulkOperation(context.Background(), 4)
+		assert.NoError(t, err)
+		assert.Equal(t, models.BulkOperationStatusRunning, resp.Status)
+		assert.Equal(t, 500, resp.Processed)
+		assert.Equal(t, "webhooks", resp.Filter.Queue)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		mockRepo := new(mocks.JobRepoMock)
+		mockRepo.On("GetBulkOperation", mock.Anything, uint(5)).
+			Return(nil, fmt.Errorf("%w: %w", ErrBulkOperationNotFound, gorm.ErrRecordNotFound))
+
+		_, err := NewJobService(mockRepo).GetBulkOperation(context.Background(), 5)
+		var apiErr common.APIError
+		assert.True(t, errors.As(err, &apiErr))
+		assert.Equal(t, http.StatusNotFound, apiErr.Status)
+	})
+}
+
+func TestJobService_CreateWorkflow(t *testing.T) {
+	payload := json.RawMessage(`{"url":"https://example.com","method":"POST","body":{},"timeout":10}`)
+	node := func(key string, deps ...string) dto.WorkflowJobDTO {
+		return dto.WorkflowJobDTO{Key: key, Queue: "webhooks", Payload: payload, DependsOn: deps}
+	}
+
+	t.Run("jobs are created in dependency order", func(t *testing.T) {
+		mockRepo := new(mocks.JobRepoMock)
+		mockRepo.On("CreateWorkflow", mock.Anything, mock.Anything, [][]int{nil, nil, {0, 1}, {2}}).
+			Run(func(args mock.Arguments) {
+				for i, j := range args.Get(1).([]*models.Job) {
+					j.ID = uint(i + 10)
+				}
+			}).
+			Return(nil)
+
+		resp, err := NewJobService(mockRepo).CreateWorkflow(context.Background(), &dto.WorkflowCreateDTO{
+			Jobs: []dto.WorkflowJobDTO{node("report", "extract", "load"), node("notify", "report"), node("extract"), node("load")},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]uint{"extract": 10, "load": 11, "report": 12, "notify": 13}, resp.Jobs)
+		mockRepo.AssertExpectations(t)
+	})
+
+	tests := []struct {
+		name        string
+		jobs        []dto.WorkflowJobDTO
+		errContains string
+	}{
+		{"duplicate key", []dto.WorkflowJobDTO{node("a"), node("a")}, "duplicate job key"},
+		{"unknown dependency", []dto.WorkflowJobDTO{node("a", "b")}, "unknown dependency"},
+		{"cycle", []dto.WorkflowJobDTO{node("a", "c"), node("b", "a"), node("c", "b"), node("d")}, "workflow has a dependency cycle"},
+		{"invalid job", []dto.WorkflowJobDTO{node("a"), {Key: "b", Queue: "nope", Payload: payload}}, `job "b": invalid queue`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(mocks.JobRepoMock)
+			_, err := NewJobService(mockRepo).CreateWorkflow(context.Background(), &dto.WorkflowCreateDTO{Jobs: tt.jobs})
+			assert.ErrorContains(t, err, tt.errContains)
+			var apiErr common.APIError
+			assert.True(t, errors.As(err, &apiErr))
+			assert.Equal(t, http.StatusBadRequest, apiErr.Status)
+			mockRepo.AssertNumberOfCalls(t, "CreateWorkflow", 0)
+		})
+	}
+}
+
+func TestJobService_CreateBatch(t *testing.T) {
+	payload := json.RawMessage(`{"url":"https://example.com","method":"POST","body":{},"timeout":10}`)
+	job := dto.JobCreateDTO{Queue: "webhooks", Payload: payload}
+
+	t.Run("jobs and callbacks are created", func(t *testing.T) {
+		mockRepo := new(mocks.JobRepoMock)
+		mockRepo.On("CreateBatch", mock.Anything, mock.MatchedBy(func(b *models.Batch) bool {
+			return b.OnComplete != nil && b.OnComplete.Queue == "webhooks" && b.OnFailure == nil
+		}), mock.Anything).
+			Run(func(args mock.Arguments) {
+				b := args.Get(1).(*models.Batch)
+				jobs := args.Get(2).([]*models.Job)
+				b.ID, b.Total, b.Pending = 5, len(jobs), len(jobs)
+				for i, j := range jobs {
+					j.ID = uint(i + 10)
+				}
+			}).
+			Return(nil)
+
+		resp, err := NewJobService(mockRepo).CreateBatch(context.Background(), &dto.BatchCreateDTO{
+			Jobs:       []dto.JobCreateDTO{job, job},
+			OnComplete: &dto.BatchCallbackDTO{Queue: "webhooks", Payload: payload},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, uint(5), resp.ID)
+		assert.Equal(t, dto.BatchStatusRunning, resp.Status)
+		assert.Equal(t, 2, resp.Pending)
+		assert.Equal(t, []uint{10, 11}, resp.JobIDs)
+		mockRepo.AssertExpectations(t)
+	})
+
+	tests := []struct {
+		name        string
+		batch       dto.BatchCreateDTO
+		errContains string
+	}{
+		{
+			"invalid job",
+			dto.BatchCreateDTO{Jobs: []dto.JobCreateDTO{job, {Queue: "nope", Payload: payload}}},
+			"job 1: invalid queue",
+		},
+		{
+			"invalid callback",
+			dto.BatchCreateDTO{
+				Jobs:      []dto.JobCreateDTO{job},
+				OnFailure: &dto.BatchCallbackDTO{Queue: "webhooks", Payload: json.RawMessage(`{}`)},
+			},
+			"on_failure: payload validation failed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(mocks.JobRepoMock)
+			_, err := NewJobService(mockRepo).CreateBatch(context.Background(), &tt.batch)
+			assert.ErrorContains(t, err, tt.errContains)
+			var apiErr common.APIError
+			assert.True(t, errors.As(err, &apiErr))
+			assert.Equal(t, http.StatusBadRequest, apiErr.Status)
+			mockRepo.AssertNumberOfCalls(t, "CreateBatch", 0)
+		})
+	}
+}
+
+func TestJobService_GetBatch(t *testing.T) {
+	now := time.Now()
+
+	t.Run("finished batch", func(t *testing.T) {
+		mockRepo := new(mocks.JobRepoMock)
+		mockRepo.On("GetBatch", mock.Anything, uint(3)).
+			Return(&models.Batch{ID: 3, Total: 2, Succeeded: 1, Failed: 1, FinishedAt: &now}, nil)
+
+		resp, err := NewJobService(mockRepo).GetBatch(context.Background(), 3)
+		assert.NoError(t, err)
+		assert.Equal(t, dto.BatchStatusFinished, resp.Status)
+		assert.Equal(t, 1, resp.Failed)
+		assert.Equal(t, &now, resp.FinishedAt)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		mockRepo := new(mocks.JobRepoMock)
+		mockRepo.On("GetBatch", mock.Anything, uint(4)).
+			Return(nil, fmt.Errorf("%w: %w", ErrBatchNotFound, gorm.ErrRecordNotFound))
+
+		_, err := NewJobService(mockRepo).GetBatch(context.Background(), 4)
+		var apiErr common.APIError
+		assert.True(t, errors.As(err, &apiErr))
+		assert.Equal(t, http.StatusNotFound, apiErr.Status)
+	})
+}
+
+func TestJobService_CreateSaga(t *testing.T) {
+	charge := json.RawMessage(`{"payment_id":"p1","user_id":"u1","amount":10,"currency":"USD","method":"card"}`)
+	notify := json.RawMessage(`{"url":"https://example.com","method":"POST","body":{},"timeout":10}`)
+
+	t.Run("steps and compensations are stored", func(t *testing.T) {
+		mockRepo := new(mocks.JobRepoMock)
+		mockRepo.On("CreateSaga", mock.Anything, mock.Anything, mock.MatchedBy(func(steps []*models.SagaStep) bool {
+			return len(steps) == 2 &&
+				steps[0].Job.Queue == "payment" && steps[0].CompensationQueue == "webhooks" &&
+				string(steps[0].CompensationPayload) == string(notify) && steps[0].CompensationMaxRetries == 3 &&
+				steps[1].Job.Queue == "webhooks" && !steps[1].HasCompensation()
+		})).
+			Run(func(args mock.Arguments) {
+				saga := args.Get(1).(*models.Saga)
+				saga.ID, saga.Status, saga.Steps, saga.CurrentStep = 9, models.SagaStatusRunning, 2, 1
+				for i, step := range args.Get(2).([]*models.SagaStep) {
+					step.Position = i + 1
+					step.JobID = uint(20 + i)
+					step.Job.Status = config.JobStatusQueued
+				}
+			}).
+			Return(nil)
+
+		resp, err := NewJobService(mockRepo).CreateSaga(context.Background(), &dto.SagaCreateDTO{
+			Steps: []dto.SagaStepDTO{
+				{Queue: "payment", Payload: charge, Compensation: &dto.SagaCompensationDTO{Queue: "webhooks", Payload: notify}},
+				{Queue: "webhooks", Payload: notify},
+			},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, uint(9), resp.ID)
+		assert.Equal(t, models.SagaStatusRunning, resp.Status)
+		assert.Len(t, resp.Steps, 2)
+		assert.Equal(t, uint(21), resp.Steps[1].JobID)
+		mockRepo.AssertExpectations(t)
+	})
+
+	tests := []struct {
+		name        string
+		steps       []dto.SagaStepDTO
+		errContains string
+	}{
+		{
+			"invalid step",
+			[]dto.SagaStepDTO{{Queue: "payment", Payload: charge}, {Queue: "nope", Payload: notify}},
+			"step 2: invalid queue",
+		},
+		{
+			"invalid compensation",
+			[]dto.SagaStepDTO{{Queue: "payment", Payload: charge, Compensation: &dto.SagaCompensationDTO{Queue: "payment", Payload: json.RawMessage(`{}`)}}},
+			"step 1 compensation: payload validation failed",
+		},
+		{
+			"pass_results on first step",
+			[]dto.SagaStepDTO{{Queue: "payment", Payload: charge, PassResults: true}},
+			"step 1: pass_results requires a previous step",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(mocks.JobRepoMock)
+			_, err := NewJobService(mockRepo).CreateSaga(context.Background(), &dto.SagaCreateDTO{Steps: tt.steps})
+			assert.ErrorContains(t, err, tt.errContains)
+			var apiErr common.APIError
+			assert.True(t, errors.As(err, &apiErr))
+			assert.Equal(t, http.StatusBadRequest, apiErr.Status)
+			mockRepo.AssertNumberOfCalls(t, "CreateSaga", 0)
+		})
+	}
+}
+
+func TestJobService_GetSaga(t *testing.T) {
+	failed := 2
+	compensationID := uint(30)
+
+	mockRepo := new(mocks.JobRepoMock)
+	mockRepo.On("GetSaga", mock.Anything, uint(9)).Return(
+		&models.Saga{ID: 9, Status: models.SagaStatusCompensating, Steps: 2, CurrentStep: 2, FailedStep: &failed, Error: "step 2 failed: declined"},
+		[]models.SagaStep{
+			{SagaID: 9, Position: 1, JobID: 20, JobStatus: config.JobStatusCompleted, CompensationJobID: &compensationID, CompensationStatus: config.JobStatusQueued},
+			{SagaID: 9, Position: 2, JobID: 21, JobStatus: config.JobStatusFailed},
+		},
+		nil,
+	)
+	mockRepo.On("GetSaga", mock.Anything, uint(10)).
+		Return(nil, nil, fmt.Errorf("%w: %w", ErrSagaNotFound, gorm.ErrRecordNotFound))
+
+	resp, err := NewJobService(mockRepo).GetSaga(context.Background(), 9)
+	assert.NoError(t, err)
+	assert.Equal(t, &failed, resp.FailedStep)
+	assert.Equal(t, dto.SagaStepResponseDTO{
+		Position:           1,
+		JobID:              20,
+		Status:             config.JobStatusCompleted,
+		CompensationJobID:  &compensationID,
+		CompensationStatus: config.JobStatusQueued,
+	}, resp.Steps[0])
+
+	_, err = NewJobService(mockRepo).GetSaga(context.Background(), 10)
+	var apiErr common.APIError
+	assert.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, http.StatusNotFound, apiErr.Status)
+}
+
 func TestJobService_GetJobByID(t *testing.T) {
 	validJob := &dto.JobResponseDTO{
 		ID:         1,
@@ -332,6 +1205,7 @@ func TestJobService_GetJobByID(t *testing.T) {
 		MaxRetries: 3,
 		Payload:    json.RawMessage(`{"to":"test@example.com","subject":"Test","body":"Hello"}`),
 	}
+	archivedAt := time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)
 
 	tests := []struct {
 		name         string
@@ -367,11 +1241,39 @@ func TestJobService_GetJobByID(t *testing.T) {
 			setupMock: func(m *mocks.JobRepoMock) {
 				m.On("Get", mock.Anything, uint(99)).
 					Return(nil, gorm.ErrRecordNotFound)
+				m.On("GetArchived", mock.Anything, uint(99)).
+					Return(nil, gorm.ErrRecordNotFound)
 			},
 			setupCtx:    func() context.Context { return context.Background() },
 			wantErr:     true,
 			errContains: "job not found",
 		},
+		{
+			name:  "archived job",
+			jobID: 1,
+			setupMock: func(m *mocks.JobRepoMock) {
+				m.On("Get", mock.Anything, uint(1)).
+					Return(nil, fmt.Errorf("job not found: %w", gorm.ErrRecordNotFound))
+				m.On("GetArchived", mock.Anything, uint(1)).
+					Return(&models.Job{
+						ID:         1,
+						Queue:      "email",
+						Status:     config.JobStatusCompleted,
+						MaxRetries: 3,
+						Payload:    []byte(`{}`),
+						ArchivedAt: &archivedAt,
+					}, nil)
+			},
+			setupCtx: func() context.Context { return context.Background() },
+			wantJob: &dto.JobResponseDTO{
+				ID:         1,
+				Queue:      "email",
+				Status:     config.JobStatusCompleted,
+				MaxRetries: 3,
+				Payload:    json.RawMessage(`{}`),
+				ArchivedAt: &archivedAt,
+			},
+		},
 		{
 			name:  "repository error - generic failure",
 			jobID: 1,
@@ -0,0 +1,80 @@
+package job
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/joshu-sajeev/goqueue/internal/config"
+)
+
+// Retention maps a queue to how long its finished jobs are kept, by
+// status. Jobs in queues or statuses without an entry are kept forever.
+type Retention map[string]map[config.JobStatus]time.Duration
+
+// RetentionRule is the retention period of one queue and status.
+type RetentionRule struct {
+	Queue  string
+	Status config.JobStatus
+	MaxAge time.Duration
+}
+
+// Rules returns the rules of r ordered by queue, then status.
+func (r Retention) Rules() []RetentionRule {
+	var rules []RetentionRule
+	for queue, statuses := range r {
+		for status, age := range statuses {
+			rules = append(rules, RetentionRule{Queue: queue, Status: status, MaxAge: age})
+		}
+	}
+	slices.SortFunc(rules, func(a, b RetentionRule) int {
+		if c := strings.Compare(a.Queue, b.Queue); c != 0 {
+			return c
+		}
+		return slices.Index(config.RetainedStatuses, a.Status) - slices.Index(config.RetainedStatuses, b.Status)
+	})
+	return rules
+}
+
+// ParseRetention parses comma separated queue:status=age entries such as
+// "email:completed=7d,email:failed=30d". Ages are Go durations or a whole
+// number of days with a "d" suffix.
+func ParseRetention(v string) (Retention, error) {
+	r := Retention{}
+	for _, entry := range strings.Split(v, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(entry, "=")
+		queue, status, ok2 := strings.Cut(key, ":")
+		if !ok || !ok2 || queue == "" {
+			return nil, fmt.Errorf("invalid retention %q", entry)
+		}
+		if !slices.Contains(config.RetainedStatuses, config.JobStatus(status)) {
+			return nil, fmt.Errorf("invalid retention status %q, allowed: %v", status, config.RetainedStatuses)
+		}
+		age, err := parseAge(value)
+		if err != nil || age <= 0 {
+			return nil, fmt.Errorf("invalid retention age for %s: %q", key, value)
+		}
+		if r[queue] == nil {
+			r[queue] = map[config.JobStatus]time.Duration{}
+		}
+		r[queue][config.JobStatus(status)] = age
+	}
+	return r, nil
+}
+
+func parseAge(v string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(v, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil {
+			return 0, err
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+	return time.ParseDuration(v)
+}
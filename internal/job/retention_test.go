@@ -0,0 +1,58 @@
+package job
+
+import (
+	"testing"
+	"time"
+
+	"github.com/joshu-sajeev/goqueue/internal/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseRetention(t *testing.T) {
+	r, err := ParseRetention("email:completed=7d, email:failed=30d,webhooks:cancelled=12h,webhooks:expired=1d,webhooks:skipped=1d")
+	assert.NoError(t, err)
+	assert.Equal(t, Retention{
+		"email": {
+			config.JobStatusCompleted: 7 * 24 * time.Hour,
+			config.JobStatusFailed:    30 * 24 * time.Hour,
+		},
+		"webhooks": {
+			config.JobStatusCancelled: 12 * time.Hour,
+			config.JobStatusExpired:   24 * time.Hour,
+			config.JobStatusSkipped:   24 * time.Hour,
+		},
+	}, r)
+
+	r, err = ParseRetention("")
+	assert.NoError(t, err)
+	assert.Empty(t, r)
+
+	for _, v := range []string{
+		"email=7d",
+		"email:completed",
+		":completed=7d",
+		"email:queued=7d",
+		"email:completed=soon",
+		"email:completed=0d",
+	} {
+		_, err := ParseRetention(v)
+		assert.Error(t, err, v)
+	}
+}
+
+func TestRetention_Rules(t *testing.T) {
+	r := Retention{
+		"webhooks": {config.JobStatusCompleted: time.Hour},
+		"email": {
+			config.JobStatusCancelled: 3 * time.Hour,
+			config.JobStatusCompleted: time.Hour,
+			config.JobStatusFailed:    2 * time.Hour,
+		},
+	}
+	assert.Equal(t, []RetentionRule{
+		{Queue: "email", Status: config.JobStatusCompleted, MaxAge: time.Hour},
+		{Queue: "email", Status: config.JobStatusFailed, MaxAge: 2 * time.Hour},
+		{Queue: "email", Status: config.JobStatusCancelled, MaxAge: 3 * time.Hour},
+		{Queue: "webhooks", Status: config.JobStatusCompleted, MaxAge: time.Hour},
+	}, r.Rules())
+}
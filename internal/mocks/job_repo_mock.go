@@ -20,6 +20,41 @@ func (m *JobRepoMock) Create(ctx context.Context, job *models.Job) error {
 	return args.Error(0)
 }
 
+func (m *JobRepoMock) CreateWorkflow(ctx context.Context, jobs []*models.Job, deps [][]int) error {
+	args := m.Called(ctx, jobs, deps)
+	return args.Error(0)
+}
+
+func (m *JobRepoMock) CreateBatch(ctx context.Context, batch *models.Batch, jobs []*models.Job) error {
+	args := m.Called(ctx, batch, jobs)
+	return args.Error(0)
+}
+
+func (m *JobRepoMock) GetBatch(ctx context.Context, id uint) (*models.Batch, error) {
+	args := m.Called(ctx, id)
+
+	batch, _ := args.Get(0).(*models.Batch)
+	return batch, args.Error(1)
+}
+
+func (m *JobRepoMock) CreateSaga(ctx context.Context, saga *models.Saga, steps []*models.SagaStep) error {
+	args := m.Called(ctx, saga, steps)
+	return args.Error(0)
+}
+
+func (m *JobRepoMock) GetSaga(ctx context.Context, id uint) (*models.Saga, []models.SagaStep, error) {
+	args := m.Called(ctx, id)
+
+	saga, _ := args.Get(0).(*models.Saga)
+	steps, _ := args.Get(1).([]models.SagaStep)
+	return saga, steps, args.Error(2)
+}
+
+func (m *JobRepoMock) CreateJobs(ctx context.Context, jobs []*models.Job) error {
+	args := m.Called(ctx, jobs)
+	return args.Error(0)
+}
+
 func (m *JobRepoMock) Get(ctx context.Context, id uint) (*models.Job, error) {
 	args := m.Called(ctx, id)
 
@@ -54,6 +89,47 @@ func (m *JobRepoMock) List(ctx context.Context, queue string) ([]models.Job, err
 	return jobs, args.Error(1)
 }
 
+func (m *JobRepoMock) QueueStats(ctx context.Context) (map[string]map[config.JobStatus]int64, error) {
+	args := m.Called(ctx)
+
+	stats, _ := args.Get(0).(map[string]map[config.JobStatus]int64)
+	return stats, args.Error(1)
+}
+
+func (m *JobRepoMock) PruneJobs(ctx context.Context, queue string, status config.JobStatus, cutoff time.Time, limit int) (int64, error) {
+	args := m.Called(ctx, queue, status, cutoff, limit)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *JobRepoMock) CountPrunable(ctx context.Context, queue string, status config.JobStatus, cutoff time.Time) (int64, error) {
+	args := m.Called(ctx, queue, status, cutoff)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *JobRepoMock) ArchiveJobs(ctx context.Context, queue string, status config.JobStatus, cutoff time.Time, limit int) (int64, error) {
+	args := m.Called(ctx, queue, status, cutoff, limit)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *JobRepoMock) GetArchived(ctx context.Context, id uint) (*models.Job, error) {
+	args := m.Called(ctx, id)
+
+	job, _ := args.Get(0).(*models.Job)
+	return job, args.Error(1)
+}
+
+func (m *JobRepoMock) CreateBulkOperation(ctx context.Context, op *models.BulkOperation) error {
+	args := m.Called(ctx, op)
+	return args.Error(0)
+}
+
+func (m *JobRepoMock) GetBulkOperation(ctx context.Context, id uint) (*models.BulkOperation, error) {
+	args := m.Called(ctx, id)
+
+	op, _ := args.Get(0).(*models.BulkOperation)
+	return op, args.Error(1)
+}
+
 func (m *JobRepoMock) AcquireNext(ctx context.Context, queue string, workerID uint, lockDuration time.Duration) (*dto.JobDTO, error) {
 	args := m.Called(ctx, queue, workerID, lockDuration)
 
@@ -61,13 +137,28 @@ func (m *JobRepoMock) AcquireNext(ctx context.Context, queue string, workerID ui
 	return job, args.Error(1)
 }
 
+func (m *JobRepoMock) ExtendLock(ctx context.Context, id uint, workerID uint, until time.Time) (bool, error) {
+	args := m.Called(ctx, id, workerID, until)
+	return args.Bool(0), args.Error(1)
+}
+
 func (m *JobRepoMock) Release(ctx context.Context, id uint) error {
 	args := m.Called(ctx, id)
 	return args.Error(0)
 }
 
-func (m *JobRepoMock) RetryLater(ctx context.Context, id uint, availableAt time.Time) error {
-	args := m.Called(ctx, id, availableAt)
+func (m *JobRepoMock) RetryLater(ctx context.Context, id uint, workerID uint, availableAt time.Time, errMsg string) error {
+	args := m.Called(ctx, id, workerID, availableAt, errMsg)
+	return args.Error(0)
+}
+
+func (m *JobRepoMock) Snooze(ctx context.Context, id uint, workerID uint, availableAt time.Time) error {
+	args := m.Called(ctx, id, workerID, availableAt)
+	return args.Error(0)
+}
+
+func (m *JobRepoMock) MarkFailed(ctx context.Context, id uint, workerID uint, errMsg string) error {
+	args := m.Called(ctx, id, workerID, errMsg)
 	return args.Error(0)
 }
 
@@ -78,7 +169,14 @@ func (m *JobRepoMock) ListStuckJobs(ctx context.Context, staleDuration time.Dura
 	return jobs, args.Error(1)
 }
 
-func (m *JobRepoMock) MarkCompleted(ctx context.Context, id uint, result datatypes.JSON) error {
-	args := m.Called(ctx, id, result)
+func (m *JobRepoMock) ListOrphanedJobs(ctx context.Context, heartbeatTimeout time.Duration) ([]models.Job, error) {
+	args := m.Called(ctx, heartbeatTimeout)
+
+	jobs, _ := args.Get(0).([]models.Job)
+	return jobs, args.Error(1)
+}
+
+func (m *JobRepoMock) MarkCompleted(ctx context.Context, id uint, workerID uint, result datatypes.JSON) error {
+	args := m.Called(ctx, id, workerID, result)
 	return args.Error(0)
 }
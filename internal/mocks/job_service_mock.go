@@ -18,6 +18,36 @@ func (m *JobServiceMock) CreateJob(ctx context.Context, dto *dto.JobCreateDTO) e
 	return args.Error(0)
 }
 
+func (m *JobServiceMock) CreateWorkflow(ctx context.Context, wf *dto.WorkflowCreateDTO) (*dto.WorkflowResponseDTO, error) {
+	args := m.Called(ctx, wf)
+	resp, _ := args.Get(0).(*dto.WorkflowResponseDTO)
+	return resp, args.Error(1)
+}
+
+func (m *JobServiceMock) CreateBatch(ctx context.Context, b *dto.BatchCreateDTO) (*dto.BatchResponseDTO, error) {
+	args := m.Called(ctx, b)
+	resp, _ := args.Get(0).(*dto.BatchResponseDTO)
+	return resp, args.Error(1)
+}
+
+func (m *JobServiceMock) GetBatch(ctx context.Context, id uint) (*dto.BatchResponseDTO, error) {
+	args := m.Called(ctx, id)
+	resp, _ := args.Get(0).(*dto.BatchResponseDTO)
+	return resp, args.Error(1)
+}
+
+func (m *JobServiceMock) CreateSaga(ctx context.Context, saga *dto.SagaCreateDTO) (*dto.SagaResponseDTO, error) {
+	args := m.Called(ctx, saga)
+	resp, _ := args.Get(0).(*dto.SagaResponseDTO)
+	return resp, args.Error(1)
+}
+
+func (m *JobServiceMock) GetSaga(ctx context.Context, id uint) (*dto.SagaResponseDTO, error) {
+	args := m.Called(ctx, id)
+	resp, _ := args.Get(0).(*dto.SagaResponseDTO)
+	return resp, args.Error(1)
+}
+
 func (m *JobServiceMock) GetJobByID(ctx context.Context, id uint) (*dto.JobResponseDTO, error) {
 	args := m.Called(ctx, id)
 	if args.Get(0) == nil {
@@ -48,3 +78,43 @@ func (m *JobServiceMock) ListJobs(ctx context.Context, queue string) ([]dto.JobR
 	}
 	return args.Get(0).([]dto.JobResponseDTO), args.Error(1)
 }
+
+func (m *JobServiceMock) QueueStats(ctx context.Context) ([]dto.QueueStatsDTO, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]dto.QueueStatsDTO), args.Error(1)
+}
+
+func (m *JobServiceMock) RetentionReport(ctx context.Context) ([]dto.RetentionReportDTO, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]dto.RetentionReportDTO), args.Error(1)
+}
+
+func (m *JobServiceMock) CreateJobs(ctx context.Context, req *dto.BulkCreateDTO) (*dto.BulkCreateResponseDTO, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.BulkCreateResponseDTO), args.Error(1)
+}
+
+func (m *JobServiceMock) CreateBulkOperation(ctx context.Context, action string, req *dto.BulkOperationCreateDTO) (*dto.BulkOperationResponseDTO, error) {
+	args := m.Called(ctx, action, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.BulkOperationResponseDTO), args.Error(1)
+}
+
+func (m *JobServiceMock) GetBulkOperation(ctx context.Context, id uint) (*dto.BulkOperationResponseDTO, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.BulkOperationResponseDTO), args.Error(1)
+}
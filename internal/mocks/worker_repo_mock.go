@@ -0,0 +1,34 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/joshu-sajeev/goqueue/internal/models"
+	"github.com/stretchr/testify/mock"
+)
+
+type WorkerRepoMock struct {
+	mock.Mock
+}
+
+func (m *WorkerRepoMock) Register(ctx context.Context, worker *models.Worker) error {
+	args := m.Called(ctx, worker)
+	return args.Error(0)
+}
+
+func (m *WorkerRepoMock) Heartbeat(ctx context.Context, id uint) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *WorkerRepoMock) Deregister(ctx context.Context, id uint) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *WorkerRepoMock) List(ctx context.Context) ([]models.Worker, error) {
+	args := m.Called(ctx)
+
+	workers, _ := args.Get(0).([]models.Worker)
+	return workers, args.Error(1)
+}
@@ -0,0 +1,58 @@
+package mocks
+
+import (
+	"context"
+	"time"
+
+	"github.com/joshu-sajeev/goqueue/internal/dto"
+	"github.com/stretchr/testify/mock"
+)
+
+type WorkerServiceMock struct {
+	mock.Mock
+}
+
+func (m *WorkerServiceMock) ListWorkers(ctx context.Context) ([]dto.WorkerResponseDTO, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]dto.WorkerResponseDTO), args.Error(1)
+}
+
+func (m *WorkerServiceMock) RegisterWorker(ctx context.Context, req *dto.WorkerRegisterDTO) (*dto.WorkerResponseDTO, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.WorkerResponseDTO), args.Error(1)
+}
+
+func (m *WorkerServiceMock) Heartbeat(ctx context.Context, id uint) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *WorkerServiceMock) Lease(ctx context.Context, req *dto.LeaseRequestDTO) (*dto.LeaseResponseDTO, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.LeaseResponseDTO), args.Error(1)
+}
+
+func (m *WorkerServiceMock) Ack(ctx context.Context, jobID uint, req *dto.AckRequestDTO) error {
+	args := m.Called(ctx, jobID, req)
+	return args.Error(0)
+}
+
+func (m *WorkerServiceMock) Nack(ctx context.Context, jobID uint, req *dto.NackRequestDTO) error {
+	args := m.Called(ctx, jobID, req)
+	return args.Error(0)
+}
+
+func (m *WorkerServiceMock) Extend(ctx context.Context, jobID uint, req *dto.ExtendRequestDTO) (time.Time, error) {
+	args := m.Called(ctx, jobID, req)
+	until, _ := args.Get(0).(time.Time)
+	return until, args.Error(1)
+}
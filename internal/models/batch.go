@@ -0,0 +1,35 @@
+// internal/models/batch.go
+package models
+
+import "time"
+
+// Batch groups jobs created together and counts how many of them are still
+// pending and how many succeeded or failed. Jobs that are skipped because
+// a dependency failed count as failed.
+type Batch struct {
+	ID        uint `gorm:"primaryKey"`
+	Total     int
+	Pending   int
+	Succeeded int
+	Failed    int
+
+	// OnCompleteJobID is a callback job queued once every job in the batch
+	// has finished. OnFailureJobID is queued when the first job fails, and
+	// skipped if the batch finishes without failures. Both wait until then.
+	OnCompleteJobID *uint
+	OnFailureJobID  *uint
+
+	// OnComplete and OnFailure are the callback jobs to create with the
+	// batch. They are only set when creating batches.
+	OnComplete *Job `gorm:"-"`
+	OnFailure  *Job `gorm:"-"`
+
+	FinishedAt *time.Time
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+// IsFinished reports whether every job in the batch has finished.
+func (b *Batch) IsFinished() bool {
+	return b.Pending == 0
+}
@@ -0,0 +1,82 @@
+// internal/models/bulk_operation.go
+package models
+
+import (
+	"time"
+
+	"github.com/joshu-sajeev/goqueue/internal/config"
+)
+
+// Bulk operation actions.
+const (
+	BulkActionRetry  = "retry"
+	BulkActionCancel = "cancel"
+	BulkActionDelete = "delete"
+	BulkActionMove   = "move"
+)
+
+// BulkActionStatuses lists the job statuses each bulk action applies to.
+// Jobs in other statuses are left alone.
+var BulkActionStatuses = map[string][]config.JobStatus{
+	BulkActionRetry: {
+		config.JobStatusFailed,
+		config.JobStatusSkipped,
+		config.JobStatusExpired,
+		config.JobStatusCancelled,
+	},
+	BulkActionCancel: {config.JobStatusQueued, config.JobStatusWaiting},
+	BulkActionDelete: {
+		config.JobStatusQueued,
+		config.JobStatusWaiting,
+		config.JobStatusFailed,
+		config.JobStatusCompleted,
+		config.JobStatusSkipped,
+		config.JobStatusExpired,
+		config.JobStatusCancelled,
+	},
+	BulkActionMove: {config.JobStatusQueued, config.JobStatusWaiting},
+}
+
+// Bulk operation statuses.
+const (
+	BulkOperationStatusPending   = "pending"
+	BulkOperationStatusRunning   = "running"
+	BulkOperationStatusCompleted = "completed"
+	BulkOperationStatusFailed    = "failed"
+)
+
+// BulkOperation applies an action to the jobs matching a filter. Workers
+// run it in the background, a batch of jobs at a time.
+type BulkOperation struct {
+	ID     uint `gorm:"primaryKey"`
+	Action string
+	Status string
+	// TargetQueue is the queue jobs are moved to by BulkActionMove.
+	TargetQueue string
+
+	// The filter. Empty fields match every job.
+	FilterQueue   string
+	FilterStatus  config.JobStatus
+	CreatedAfter  *time.Time
+	CreatedBefore *time.Time
+	ErrorContains string
+
+	// Total is the number of matching jobs when the operation was created.
+	// Only jobs up to MaxJobID are considered; LastJobID is the highest ID
+	// processed so far.
+	Total     int
+	Processed int
+	MaxJobID  uint
+	LastJobID uint
+	Error     string
+
+	StartedAt  *time.Time
+	FinishedAt *time.Time
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+// IsFinished reports whether the operation has completed or failed.
+func (o *BulkOperation) IsFinished() bool {
+	return o.Status == BulkOperationStatusCompleted || o.Status == BulkOperationStatusFailed
+}
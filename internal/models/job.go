@@ -18,14 +18,54 @@ type Job struct {
 	MaxRetries int
 
 	AvailableAt time.Time
-	LockedAt    *time.Time
-	LockedBy    *uint
+	// ExpiresAt is when the job stops being worth running. A job still
+	// queued at that time is expired instead of acquired.
+	ExpiresAt *time.Time
+	// TTLSeconds is the queue TTL a job created without an expires_at got.
+	// A job that starts waiting has no ExpiresAt until it is queued, when
+	// it is set to TTLSeconds after it becomes available.
+	TTLSeconds int
+	LockedAt   *time.Time
+	LockedBy   *uint
 
 	Result datatypes.JSON
 	Error  string
 
+	// ParentID is the job whose handler enqueued this one, if any.
+	ParentID *uint
+	// BatchID is the batch the job was created in, if any.
+	BatchID *uint
+
+	// GroupKey orders jobs of the same queue: a job is not acquired while
+	// an earlier job with the same key is queued or running.
+	GroupKey *string
+	// ConcurrencyKey limits how many jobs sharing it, in any queue, run at
+	// the same time. A job is not acquired while ConcurrencyLimit jobs with
+	// its key are running.
+	ConcurrencyKey   *string
+	ConcurrencyLimit int
+	// DebounceKey collapses or drops enqueues of jobs in the same queue
+	// that share it, according to DebounceMode and DebounceWindow. Those
+	// two are only set when creating jobs.
+	DebounceKey    *string
+	DebounceMode   string        `gorm:"-"`
+	DebounceWindow time.Duration `gorm:"-"`
+
+	// PassResults merges the results of the job's dependencies into its
+	// payload under "dependency_results" when it becomes ready to run.
+	PassResults bool
+	// OnDependencyFailure is config.DependencyFailureFail or
+	// config.DependencyFailureSkip.
+	OnDependencyFailure string
+	// DependsOn lists the jobs that must complete before this one runs.
+	// It is stored in job_dependencies and only set when creating jobs.
+	DependsOn []uint `gorm:"-"`
+
 	CreatedAt time.Time
 	UpdatedAt time.Time
+	// ArchivedAt is when the job was moved to jobs_archive. It is only set
+	// on jobs loaded from the archive.
+	ArchivedAt *time.Time `gorm:"-"`
 }
 
 func (j *Job) IsLocked() bool {
@@ -35,3 +75,9 @@ func (j *Job) IsLocked() bool {
 func (j *Job) IsAvailable() bool {
 	return !j.IsLocked() && j.AvailableAt.Before(time.Now())
 }
+
+// JobDependency records that JobID waits for DependsOnID to complete.
+type JobDependency struct {
+	JobID       uint `gorm:"primaryKey"`
+	DependsOnID uint `gorm:"primaryKey"`
+}
@@ -0,0 +1,72 @@
+// internal/models/payment.go
+package models
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Payment ledger statuses.
+const (
+	PaymentStatusPending   = "pending"
+	PaymentStatusCompleted = "completed"
+	PaymentStatusFailed    = "failed"
+)
+
+// PaymentTransaction is a row in the payment ledger. There is at most one
+// per PaymentID; it is written before the provider is charged so that a
+// retried job finds it and never charges twice.
+type PaymentTransaction struct {
+	ID        uint `gorm:"primaryKey"`
+	PaymentID string
+	UserID    string
+	Amount    float64
+	Currency  string
+	Method    string
+
+	Provider      string
+	TransactionID string
+	Status        string
+	Error         string
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (PaymentTransaction) TableName() string { return "payment_ledger" }
+
+// AmountScale is the number of decimal places the ledger stores amounts
+// with (NUMERIC(18, 4)).
+const AmountScale = 4
+
+// maxAmountUnits bounds amounts to the 14 integer digits of the ledger
+// column.
+const maxAmountUnits = 1e18
+
+// AmountUnits converts amount to ten-thousandths, the unit the ledger
+// stores. It reports false for amounts with more than AmountScale decimal
+// places or too large for the ledger, which would be rounded or rejected
+// when stored.
+func AmountUnits(amount float64) (int64, bool) {
+	s := strconv.FormatFloat(amount, 'f', -1, 64)
+	if i := strings.IndexByte(s, '.'); i >= 0 && len(s)-i-1 > AmountScale {
+		return 0, false
+	}
+	units := math.Round(amount * math.Pow10(AmountScale))
+	if math.Abs(units) >= maxAmountUnits {
+		return 0, false
+	}
+	return int64(units), true
+}
+
+// Matches reports whether the transaction was recorded for the same
+// charge as the given amount, currency and user. Amounts are compared at
+// the ledger's scale, since the stored amount is read back from NUMERIC
+// and need not be the float64 the job was created with.
+func (t *PaymentTransaction) Matches(userID string, amount float64, currency string) bool {
+	stored, _ := AmountUnits(t.Amount)
+	units, ok := AmountUnits(amount)
+	return t.UserID == userID && ok && stored == units && t.Currency == currency
+}
@@ -0,0 +1,72 @@
+// internal/models/saga.go
+package models
+
+import (
+	"time"
+
+	"github.com/joshu-sajeev/goqueue/internal/config"
+	"gorm.io/datatypes"
+)
+
+// Saga statuses.
+const (
+	SagaStatusRunning            = "running"
+	SagaStatusCompleted          = "completed"
+	SagaStatusCompensating       = "compensating"
+	SagaStatusCompensated        = "compensated"
+	SagaStatusCompensationFailed = "compensation_failed"
+)
+
+// Saga is a sequence of steps that run one after the other. When a step
+// fails permanently, the compensations of the steps before it run in
+// reverse order.
+type Saga struct {
+	ID     uint `gorm:"primaryKey"`
+	Status string
+	// Steps is the number of steps. CurrentStep is the step that is
+	// running or next to run, and FailedStep the step whose failure
+	// started compensation.
+	Steps       int
+	CurrentStep int
+	FailedStep  *int
+	Error       string
+
+	FinishedAt *time.Time
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+// IsFinished reports whether the saga has reached a final status.
+func (s *Saga) IsFinished() bool {
+	switch s.Status {
+	case SagaStatusCompleted, SagaStatusCompensated, SagaStatusCompensationFailed:
+		return true
+	}
+	return false
+}
+
+// SagaStep is one step of a saga. The compensation job is only created
+// when a later step fails, so until then it is stored as its queue,
+// payload and retries.
+type SagaStep struct {
+	SagaID   uint `gorm:"primaryKey"`
+	Position int  `gorm:"primaryKey"`
+	JobID    uint
+
+	CompensationQueue      string
+	CompensationPayload    datatypes.JSON
+	CompensationMaxRetries int
+	CompensationJobID      *uint
+
+	// Job is the step's job, only set when creating sagas. JobStatus and
+	// CompensationStatus are the statuses of the step's jobs, only set
+	// when reading sagas.
+	Job                *Job             `gorm:"-"`
+	JobStatus          config.JobStatus `gorm:"-"`
+	CompensationStatus config.JobStatus `gorm:"-"`
+}
+
+// HasCompensation reports whether the step can be compensated.
+func (s *SagaStep) HasCompensation() bool {
+	return s.CompensationQueue != ""
+}
@@ -0,0 +1,26 @@
+// internal/models/worker.go
+package models
+
+import (
+	"time"
+
+	"gorm.io/datatypes"
+)
+
+// Worker is a registered worker process. Its ID is what jobs store in
+// locked_by while the process is running them.
+type Worker struct {
+	ID       uint `gorm:"primaryKey"`
+	Hostname string
+	PID      int `gorm:"column:pid"`
+	Queues   datatypes.JSONSlice[string]
+	Version  string
+
+	StartedAt       time.Time
+	LastHeartbeatAt time.Time
+}
+
+// IsAlive reports whether the worker has sent a heartbeat within timeout.
+func (w *Worker) IsAlive(timeout time.Duration) bool {
+	return time.Since(w.LastHeartbeatAt) <= timeout
+}
@@ -3,39 +3,181 @@ package pool
 import (
 	"context"
 	"log"
+	"os"
 	"sync"
 	"time"
 
+	"github.com/joshu-sajeev/goqueue/internal/config"
+	"github.com/joshu-sajeev/goqueue/internal/job"
+	"github.com/joshu-sajeev/goqueue/internal/models"
 	"github.com/joshu-sajeev/goqueue/internal/storage/postgres"
+	"github.com/joshu-sajeev/goqueue/internal/templates"
 	"github.com/joshu-sajeev/goqueue/internal/worker"
+	"gorm.io/datatypes"
 )
 
+// JobStore is what the pool and its workers need from the job repository.
+// It is implemented by postgres.JobRepository.
+type JobStore interface {
+	worker.JobStore
+	ListStuckJobs(ctx context.Context, staleDuration time.Duration) ([]models.Job, error)
+	ListOrphanedJobs(ctx context.Context, heartbeatTimeout time.Duration) ([]models.Job, error)
+	ArchiveJobs(ctx context.Context, queue string, status config.JobStatus, cutoff time.Time, limit int) (int64, error)
+	PruneJobs(ctx context.Context, queue string, status config.JobStatus, cutoff time.Time, limit int) (int64, error)
+	ClaimBulkOperation(ctx context.Context, staleAfter time.Duration) (*models.BulkOperation, error)
+	ProcessBulkBatch(ctx context.Context, op *models.BulkOperation, limit int) error
+	FailBulkOperation(ctx context.Context, id uint, errMsg string) error
+}
+
+// WorkerStore registers the pool in the workers table. It is implemented
+// by postgres.WorkerRepository.
+type WorkerStore interface {
+	Register(ctx context.Context, worker *models.Worker) error
+	Heartbeat(ctx context.Context, id uint) error
+	Deregister(ctx context.Context, id uint) error
+	RemoveDead(ctx context.Context, olderThan time.Duration) (int64, error)
+}
+
 type WorkerPool struct {
-	workers      []*worker.Worker
-	jobRepo      *postgres.JobRepository
-	lockDuration time.Duration
-	wg           sync.WaitGroup
-	ctx          context.Context
-	cancel       context.CancelFunc
+	count           int
+	queues          []string
+	version         string
+	info            *models.Worker
+	workerRepo      WorkerStore
+	registry        *worker.Registry
+	jobService      *job.JobService
+	middleware      []worker.Middleware
+	workers         []*worker.Worker
+	jobRepo         JobStore
+	lockDuration    time.Duration
+	shutdownTimeout time.Duration
+	retention       job.Retention
+	archive         job.Retention
+	running         sync.WaitGroup
+	wg              sync.WaitGroup
+	ctx             context.Context
+	cancel          context.CancelFunc
 }
 
-func NewWorkerPool(count int, repo *postgres.JobRepository, queues []string, dur time.Duration) *WorkerPool {
-	ctx, cancel := context.WithCancel(context.Background())
-	p := &WorkerPool{jobRepo: repo, lockDuration: dur, ctx: ctx, cancel: cancel}
+// DefaultShutdownTimeout is the grace period Stop gives running jobs unless
+// SetShutdownTimeout is called.
+const DefaultShutdownTimeout = 30 * time.Second
 
-	for i := 1; i <= count; i++ {
-		p.workers = append(p.workers, worker.NewWorker(i, repo, queues, dur))
+func NewWorkerPool(count int, repo *postgres.JobRepository, workerRepo *postgres.WorkerRepository, queues []string, dur time.Duration) *WorkerPool {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &WorkerPool{
+		count:           count,
+		queues:          queues,
+		workerRepo:      workerRepo,
+		registry:        worker.NewDefaultRegistry(),
+		jobService:      job.NewJobService(repo),
+		jobRepo:         repo,
+		lockDuration:    dur,
+		shutdownTimeout: DefaultShutdownTimeout,
+		ctx:             ctx,
+		cancel:          cancel,
 	}
-	return p
 }
 
-func (p *WorkerPool) Start() {
-	for _, w := range p.workers {
-		w.Start(p.ctx)
+// SetShutdownTimeout sets how long Stop waits for running jobs to finish
+// before cancelling them and releasing them back to the queue.
+func (p *WorkerPool) SetShutdownTimeout(d time.Duration) {
+	p.shutdownTimeout = d
+}
+
+// SetVersion sets the version reported when the pool registers itself in
+// the workers table.
+func (p *WorkerPool) SetVersion(v string) {
+	p.version = v
+}
+
+// SetEmailTemplates sets the templates used to validate email jobs that
+// handlers enqueue with worker.Enqueue.
+func (p *WorkerPool) SetEmailTemplates(t *templates.Store) {
+	p.jobService.SetEmailTemplates(t)
+}
+
+// SetQueueTTLs sets the default TTLs of jobs that handlers enqueue with
+// worker.Enqueue, as for the API's JobService.
+func (p *WorkerPool) SetQueueTTLs(ttls map[string]time.Duration) {
+	p.jobService.SetQueueTTLs(ttls)
+}
+
+// SetRetention sets how long finished jobs are kept. When set, the pool
+// deletes older jobs every config.PruneInterval.
+func (p *WorkerPool) SetRetention(r job.Retention) {
+	p.retention = r
+}
+
+// SetArchive sets how old finished jobs are moved to the archive. When
+// set, the pool archives older jobs every config.PruneInterval, before
+// applying retention.
+func (p *WorkerPool) SetArchive(r job.Retention) {
+	p.archive = r
+}
+
+// Registry returns the handler registry used by the pool's workers. It is
+// pre-populated with the built-in handlers; register additional queues on
+// it with worker.Register before Start.
+func (p *WorkerPool) Registry() *worker.Registry {
+	return p.registry
+}
+
+// Use registers middleware that wraps every job executed by the pool's
+// workers. Middleware runs in the order it was added and must be
+// registered before Start.
+func (p *WorkerPool) Use(mws ...worker.Middleware) {
+	p.middleware = append(p.middleware, mws...)
+}
+
+// Start registers the process in the workers table and starts the workers,
+// the heartbeat, the janitor, the bulk operation runner and, if retention
+// or archiving is set, the pruner. Jobs acquired by the pool are locked
+// with the registered worker ID.
+func (p *WorkerPool) Start() error {
+	hostname, _ := os.Hostname()
+	p.info = &models.Worker{
+		Hostname: hostname,
+		PID:      os.Getpid(),
+		Queues:   datatypes.NewJSONSlice(p.queues),
+		Version:  p.version,
+	}
+	if err := p.workerRepo.Register(p.ctx, p.info); err != nil {
+		return err
+	}
+	log.Printf("Registered as worker %d (%s, pid %d)", p.info.ID, p.info.Hostname, p.info.PID)
+
+	for i := 1; i <= p.count; i++ {
+		w := worker.NewWorker(i, p.info.ID, p.jobRepo, p.queues, p.lockDuration, p.registry, p.jobService, p.middleware...)
+		p.workers = append(p.workers, w)
+		w.Start(p.ctx, &p.running)
 	}
 
-	p.wg.Add(1)
+	p.wg.Add(3)
+	go p.heartbeat()
 	go p.janitor()
+	go p.bulkRunner()
+	if len(p.retention) > 0 || len(p.archive) > 0 {
+		p.wg.Add(1)
+		go p.pruner()
+	}
+	return nil
+}
+
+func (p *WorkerPool) heartbeat() {
+	defer p.wg.Done()
+	ticker := time.NewTicker(config.WorkerHeartbeatInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if err := p.workerRepo.Heartbeat(p.ctx, p.info.ID); err != nil {
+				log.Printf("Heartbeat failed: %v", err)
+			}
+		case <-p.ctx.Done():
+			return
+		}
+	}
 }
 
 func (p *WorkerPool) janitor() {
@@ -49,16 +191,160 @@ func (p *WorkerPool) janitor() {
 				log.Printf("Recovering stuck job %d", j.ID)
 				p.jobRepo.Release(p.ctx, j.ID)
 			}
+
+			orphaned, _ := p.jobRepo.ListOrphanedJobs(p.ctx, config.WorkerHeartbeatTimeout)
+			for _, j := range orphaned {
+				log.Printf("Reclaiming job %d from dead worker %d", j.ID, *j.LockedBy)
+				p.jobRepo.Release(p.ctx, j.ID)
+			}
+
+			removed, err := p.workerRepo.RemoveDead(p.ctx, config.DeadWorkerRemoveAfter)
+			if err != nil {
+				log.Printf("Failed to remove dead workers: %v", err)
+			} else if removed > 0 {
+				log.Printf("Removed %d dead workers", removed)
+			}
+		case <-p.ctx.Done():
+			return
+		}
+	}
+}
+
+func (p *WorkerPool) pruner() {
+	defer p.wg.Done()
+	ticker := time.NewTicker(config.PruneInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			p.prune()
+		case <-p.ctx.Done():
+			return
+		}
+	}
+}
+
+// prune archives, then deletes, the jobs past their archive age or
+// retention in batches of config.PruneBatchSize, each in its own
+// statement.
+func (p *WorkerPool) prune() {
+	for _, rule := range p.archive.Rules() {
+		if !p.pruneRule(rule, "archive", "Archived", p.jobRepo.ArchiveJobs) {
+			return
+		}
+	}
+	for _, rule := range p.retention.Rules() {
+		if !p.pruneRule(rule, "prune", "Pruned", p.jobRepo.PruneJobs) {
+			return
+		}
+	}
+}
+
+// pruneRule calls batch until it handles fewer than a full batch of jobs.
+// It returns false if the pool is stopping.
+func (p *WorkerPool) pruneRule(
+	rule job.RetentionRule,
+	action, done string,
+	batch func(context.Context, string, config.JobStatus, time.Time, int) (int64, error),
+) bool {
+	cutoff := time.Now().Add(-rule.MaxAge)
+	var total int64
+	for {
+		n, err := batch(p.ctx, rule.Queue, rule.Status, cutoff, config.PruneBatchSize)
+		total += n
+		if p.ctx.Err() != nil {
+			return false
+		}
+		if err != nil {
+			log.Printf("Failed to %s %s %s jobs: %v", action, rule.Status, rule.Queue, err)
+			break
+		}
+		if n < config.PruneBatchSize {
+			break
+		}
+	}
+	if total > 0 {
+		log.Printf("%s %d %s %s jobs older than %s", done, total, rule.Status, rule.Queue, rule.MaxAge)
+	}
+	return true
+}
+
+func (p *WorkerPool) bulkRunner() {
+	defer p.wg.Done()
+	ticker := time.NewTicker(config.BulkPollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			p.runBulkOperations()
 		case <-p.ctx.Done():
 			return
 		}
 	}
 }
 
+// runBulkOperations claims and runs bulk operations one at a time until
+// none are left. Each batch of config.BulkBatchSize jobs is its own
+// transaction, so an operation interrupted by shutdown is resumed from its
+// last batch by whichever worker takes it over.
+func (p *WorkerPool) runBulkOperations() {
+	for {
+		op, err := p.jobRepo.ClaimBulkOperation(p.ctx, config.BulkOperationStaleAfter)
+		if err != nil {
+			if p.ctx.Err() == nil {
+				log.Printf("Failed to claim bulk operation: %v", err)
+			}
+			return
+		}
+		if op == nil {
+			return
+		}
+
+		log.Printf("Running bulk operation %d: %s %d jobs", op.ID, op.Action, op.Total)
+		for !op.IsFinished() {
+			if err := p.jobRepo.ProcessBulkBatch(p.ctx, op, config.BulkBatchSize); err != nil {
+				if p.ctx.Err() != nil {
+					return
+				}
+				log.Printf("Bulk operation %d failed: %v", op.ID, err)
+				if err := p.jobRepo.FailBulkOperation(p.ctx, op.ID, err.Error()); err != nil {
+					log.Printf("Failed to mark bulk operation %d failed: %v", op.ID, err)
+				}
+				break
+			}
+		}
+		if op.Status == models.BulkOperationStatusCompleted {
+			log.Printf("Bulk operation %d completed: %s %d jobs", op.ID, op.Action, op.Processed)
+		}
+	}
+}
+
+// Stop drains the pool. Workers stop acquiring jobs immediately and Stop
+// waits up to the shutdown timeout for running jobs to finish. Jobs still
+// running after that have their context cancelled and are released back
+// to the queue before Stop returns.
 func (p *WorkerPool) Stop() {
-	p.cancel()
 	for _, w := range p.workers {
 		w.Stop()
 	}
+
+	drained := make(chan struct{})
+	go func() {
+		p.running.Wait()
+		close(drained)
+	}()
+
+	select {
+	case <-drained:
+	case <-time.After(p.shutdownTimeout):
+		log.Printf("Shutdown timeout of %s exceeded, cancelling running jobs", p.shutdownTimeout)
+	}
+
+	p.cancel()
+	p.running.Wait()
 	p.wg.Wait()
+
+	if err := p.workerRepo.Deregister(context.Background(), p.info.ID); err != nil {
+		log.Printf("Failed to deregister worker %d: %v", p.info.ID, err)
+	}
 }
@@ -0,0 +1,243 @@
+package pool
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/joshu-sajeev/goqueue/internal/config"
+	"github.com/joshu-sajeev/goqueue/internal/dto"
+	"github.com/joshu-sajeev/goqueue/internal/models"
+	"github.com/joshu-sajeev/goqueue/internal/worker"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/datatypes"
+)
+
+// memoryJobStore is an in-memory JobStore. AcquireNext hands out queued
+// jobs in order and counts its calls; every outcome is recorded by job ID.
+type memoryJobStore struct {
+	mu       sync.Mutex
+	queued   []*dto.JobDTO
+	acquires int
+	outcomes map[uint]string
+}
+
+func (s *memoryJobStore) AcquireNext(ctx context.Context, queue string, workerID uint, lockDuration time.Duration) (*dto.JobDTO, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.acquires++
+	if len(s.queued) == 0 {
+		return nil, nil
+	}
+	j := s.queued[0]
+	s.queued = s.queued[1:]
+	return j, nil
+}
+
+func (s *memoryJobStore) CompleteWithChildren(ctx context.Context, id uint, workerID uint, result datatypes.JSON, children []*models.Job) error {
+	s.record(id, "completed")
+	return nil
+}
+
+func (s *memoryJobStore) MarkFailed(ctx context.Context, id uint, workerID uint, errMsg string) error {
+	s.record(id, "failed")
+	return nil
+}
+
+func (s *memoryJobStore) RetryLater(ctx context.Context, id uint, workerID uint, availableAt time.Time, errMsg string) error {
+	s.record(id, "retried")
+	return nil
+}
+
+func (s *memoryJobStore) Snooze(ctx context.Context, id uint, workerID uint, availableAt time.Time) error {
+	s.record(id, "snoozed")
+	return nil
+}
+
+func (s *memoryJobStore) Release(ctx context.Context, id uint) error {
+	s.record(id, "released")
+	return nil
+}
+
+func (s *memoryJobStore) ListStuckJobs(ctx context.Context, staleDuration time.Duration) ([]models.Job, error) {
+	return nil, nil
+}
+
+func (s *memoryJobStore) ListOrphanedJobs(ctx context.Context, heartbeatTimeout time.Duration) ([]models.Job, error) {
+	return nil, nil
+}
+
+func (s *memoryJobStore) ArchiveJobs(ctx context.Context, queue string, status config.JobStatus, cutoff time.Time, limit int) (int64, error) {
+	return 0, nil
+}
+
+func (s *memoryJobStore) PruneJobs(ctx context.Context, queue string, status config.JobStatus, cutoff time.Time, limit int) (int64, error) {
+	return 0, nil
+}
+
+func (s *memoryJobStore) ClaimBulkOperation(ctx context.Context, staleAfter time.Duration) (*models.BulkOperation, error) {
+	return nil, nil
+}
+
+func (s *memoryJobStore) ProcessBulkBatch(ctx context.Context, op *models.BulkOperation, limit int) error {
+	return nil
+}
+
+func (s *memoryJobStore) FailBulkOperation(ctx context.Context, id uint, errMsg string) error {
+	return nil
+}
+
+func (s *memoryJobStore) record(id uint, outcome string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.outcomes == nil {
+		s.outcomes = map[uint]string{}
+	}
+	s.outcomes[id] = outcome
+}
+
+func (s *memoryJobStore) snapshot() (int, map[uint]string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	outcomes := make(map[uint]string, len(s.outcomes))
+	for id, o := range s.outcomes {
+		outcomes[id] = o
+	}
+	return s.acquires, outcomes
+}
+
+// memoryWorkerStore registers the pool as worker 1.
+type memoryWorkerStore struct {
+	mu           sync.Mutex
+	deregistered bool
+}
+
+func (s *memoryWorkerStore) Register(ctx context.Context, w *models.Worker) error {
+	w.ID = 1
+	return nil
+}
+
+func (s *memoryWorkerStore) Heartbeat(ctx context.Context, id uint) error { return nil }
+
+func (s *memoryWorkerStore) RemoveDead(ctx context.Context, olderThan time.Duration) (int64, error) {
+	return 0, nil
+}
+
+func (s *memoryWorkerStore) Deregister(ctx context.Context, id uint) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.deregistered = true
+	return nil
+}
+
+// newTestPool returns a pool of one worker running handler on the
+// "default" queue.
+func newTestPool(jobs *memoryJobStore, workers *memoryWorkerStore, timeout time.Duration, handler worker.Handler) *WorkerPool {
+	ctx, cancel := context.WithCancel(context.Background())
+	registry := worker.NewRegistry()
+	registry.Handle("default", handler)
+	return &WorkerPool{
+		count:           1,
+		queues:          []string{"default"},
+		workerRepo:      workers,
+		registry:        registry,
+		jobRepo:         jobs,
+		lockDuration:    time.Minute,
+		shutdownTimeout: timeout,
+		ctx:             ctx,
+		cancel:          cancel,
+	}
+}
+
+// stopAsync calls p.Stop in a new goroutine and returns a channel closed
+// when it returns.
+func stopAsync(p *WorkerPool) <-chan struct{} {
+	stopped := make(chan struct{})
+	go func() {
+		p.Stop()
+		close(stopped)
+	}()
+	return stopped
+}
+
+func TestWorkerPool_Stop_WaitsForRunningJob(t *testing.T) {
+	jobs := &memoryJobStore{queued: []*dto.JobDTO{
+		{ID: 1, Queue: "default", MaxRetries: 3},
+		{ID: 2, Queue: "default", MaxRetries: 3},
+	}}
+	workers := &memoryWorkerStore{}
+	started := make(chan struct{})
+	finish := make(chan struct{})
+	p := newTestPool(jobs, workers, time.Minute, func(ctx context.Context, job *dto.JobDTO) (any, error) {
+		close(started)
+		<-finish
+		return "done", nil
+	})
+	require.NoError(t, p.Start())
+	<-started
+
+	stopped := stopAsync(p)
+	select {
+	case <-stopped:
+		t.Fatal("Stop returned while a job was running")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(finish)
+	select {
+	case <-stopped:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Stop did not return after the job finished")
+	}
+
+	// The finished job is completed and no further job is acquired.
+	acquires, outcomes := jobs.snapshot()
+	assert.Equal(t, 1, acquires)
+	assert.Equal(t, map[uint]string{1: "completed"}, outcomes)
+	assert.True(t, workers.deregistered)
+}
+
+func TestWorkerPool_Stop_ReleasesJobAfterTimeout(t *testing.T) {
+	jobs := &memoryJobStore{queued: []*dto.JobDTO{{ID: 1, Queue: "default", MaxRetries: 3}}}
+	workers := &memoryWorkerStore{}
+	started := make(chan struct{})
+	p := newTestPool(jobs, workers, 50*time.Millisecond, func(ctx context.Context, job *dto.JobDTO) (any, error) {
+		close(started)
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+	require.NoError(t, p.Start())
+	<-started
+
+	select {
+	case <-stopAsync(p):
+	case <-time.After(5 * time.Second):
+		t.Fatal("Stop did not return after the shutdown timeout")
+	}
+
+	_, outcomes := jobs.snapshot()
+	assert.Equal(t, map[uint]string{1: "released"}, outcomes)
+	assert.True(t, workers.deregistered)
+}
+
+func TestWorkerPool_Stop_NoAcquiresAfterStop(t *testing.T) {
+	jobs := &memoryJobStore{}
+	p := newTestPool(jobs, &memoryWorkerStore{}, time.Minute, func(ctx context.Context, job *dto.JobDTO) (any, error) {
+		return nil, nil
+	})
+	require.NoError(t, p.Start())
+	require.Eventually(t, func() bool {
+		acquires, _ := jobs.snapshot()
+		return acquires > 0
+	}, 5*time.Second, 10*time.Millisecond)
+
+	p.Stop()
+	acquires, _ := jobs.snapshot()
+
+	// The worker would poll again after its 2s backoff if it were running.
+	time.Sleep(2500 * time.Millisecond)
+	after, _ := jobs.snapshot()
+	assert.Equal(t, acquires, after)
+}
@@ -0,0 +1,176 @@
+// internal/storage/postgres/job_batches.go
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/joshu-sajeev/goqueue/internal/config"
+	"github.com/joshu-sajeev/goqueue/internal/job"
+	"github.com/joshu-sajeev/goqueue/internal/models"
+	"gorm.io/gorm"
+)
+
+// batchInsertSize is the number of jobs inserted per statement when
+// creating a batch.
+const batchInsertSize = 500
+
+// CreateBatch inserts batch and its jobs in one transaction. The callback
+// jobs in batch.OnComplete and batch.OnFailure are created waiting and
+// queued when the batch finishes or its first job fails. IDs are filled in
+// on success.
+func (r *JobRepository) CreateBatch(ctx context.Context, batch *models.Batch, jobs []*models.Job) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, cb := range []struct {
+			job *models.Job
+			id  **uint
+		}{
+			{batch.OnComplete, &batch.OnCompleteJobID},
+			{batch.OnFailure, &batch.OnFailureJobID},
+		} {
+			if cb.job == nil {
+				continue
+			}
+			prepareJob(cb.job)
+			startWaiting(cb.job)
+			if err := tx.Create(cb.job).Error; err != nil {
+				return fmt.Errorf("create callback job: %w", err)
+			}
+			*cb.id = &cb.job.ID
+		}
+
+		batch.Total = len(jobs)
+		batch.Pending = len(jobs)
+		if err := tx.Create(batch).Error; err != nil {
+			return err
+		}
+
+		var ready []*models.Job
+		for _, j := range jobs {
+			prepareJob(j)
+			j.BatchID = &batch.ID
+			if len(j.DependsOn) == 0 {
+				ready = append(ready, j)
+				continue
+			}
+			// Jobs whose dependencies already failed are settled against
+			// the batch by resolveWaiting.
+			if err := createWithDependencies(tx, j); err != nil {
+				return err
+			}
+		}
+		if len(ready) > 0 {
+			if err := tx.CreateInBatches(ready, batchInsertSize).Error; err != nil {
+				return err
+			}
+		}
+
+		return tx.First(batch, batch.ID).Error
+	})
+	if err != nil {
+		return fmt.Errorf("create batch: %w", err)
+	}
+	return nil
+}
+
+// GetBatch retrieves a batch and its progress counts by ID.
+func (r *JobRepository) GetBatch(ctx context.Context, id uint) (*models.Batch, error) {
+	var batch models.Batch
+	if err := r.db.WithContext(ctx).First(&batch, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w: %w", job.ErrBatchNotFound, err)
+		}
+		return nil, fmt.Errorf("get batch: %w", err)
+	}
+	return &batch, nil
+}
+
+// settleBatches counts the jobs in ids, which have just completed, failed,
+// expired or been skipped, against the batches they belong to. Callers
+// only pass jobs whose status they changed in the same transaction, with
+// the old status in the WHERE clause, so a job is never counted twice.
+// Callback jobs are queued for batches that saw their first failure or
+// finished. Updating the batch row locks it, so concurrent jobs of the
+// same batch are counted one after the other.
+func settleBatches(tx *gorm.DB, ids []uint) error {
+	var batches []models.Batch
+	if err := tx.Raw(`
+		UPDATE batches SET
+			pending = pending - c.finished,
+			succeeded = succeeded + c.succeeded,
+			failed = failed + c.finished - c.succeeded,
+			finished_at = CASE WHEN pending = c.finished THEN now() ELSE finished_at END,
+			updated_at = now()
+		FROM (
+			SELECT batch_id, COUNT(*) AS finished, COUNT(*) FILTER (WHERE status = ?) AS succeeded
+			FROM jobs
+			WHERE id IN ? AND batch_id IS NOT NULL
+			GROUP BY batch_id
+		) c
+		WHERE batches.id = c.batch_id
+		RETURNING batches.*`,
+		config.JobStatusCompleted, ids,
+	).Scan(&batches).Error; err != nil {
+		return fmt.Errorf("update batch counts: %w", err)
+	}
+
+	for _, b := range batches {
+		if b.Failed > 0 && b.OnFailureJobID != nil {
+			if err := queueCallback(tx, *b.OnFailureJobID, b.ID); err != nil {
+				return err
+			}
+		}
+		if !b.IsFinished() {
+			continue
+		}
+		if b.OnCompleteJobID != nil {
+			if err := queueCallback(tx, *b.OnCompleteJobID, b.ID); err != nil {
+				return err
+			}
+		}
+		if b.OnFailureJobID != nil {
+			if err := tx.Model(&models.Job{}).
+				Where("id = ? AND status = ?", *b.OnFailureJobID, config.JobStatusWaiting).
+				Updates(map[string]any{
+					"status": config.JobStatusSkipped,
+					"error":  "batch finished without failures",
+				}).Error; err != nil {
+				return fmt.Errorf("skip failure callback: %w", err)
+			}
+		}
+	}
+	return nil
+}
+
+// queueCallback queues a waiting batch callback job, adding the batch's
+// counts to its payload under "batch" when the payload is a JSON object.
+// A callback that was already queued is left alone.
+func queueCallback(tx *gorm.DB, jobID, batchID uint) error {
+	if err := tx.Exec(`
+		UPDATE jobs SET
+			status = ?,
+			available_at = now(),
+			expires_at = CASE WHEN ttl_seconds > 0
+				THEN now() + ttl_seconds * interval '1 second'
+				ELSE expires_at END,
+			payload = CASE WHEN jsonb_typeof(payload) = 'object' THEN
+				payload || jsonb_build_object('batch', (
+					SELECT jsonb_build_object(
+						'id', b.id,
+						'total', b.total,
+						'pending', b.pending,
+						'succeeded', b.succeeded,
+						'failed', b.failed
+					)
+					FROM batches b WHERE b.id = ?
+				))
+			ELSE payload END,
+			updated_at = now()
+		WHERE id = ? AND status = ?`,
+		config.JobStatusQueued, batchID, jobID, config.JobStatusWaiting,
+	).Error; err != nil {
+		return fmt.Errorf("queue batch callback: %w", err)
+	}
+	return nil
+}
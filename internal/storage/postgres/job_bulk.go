@@ -0,0 +1,303 @@
+// internal/storage/postgres/job_bulk.go
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/joshu-sajeev/goqueue/internal/config"
+	"github.com/joshu-sajeev/goqueue/internal/job"
+	"github.com/joshu-sajeev/goqueue/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// CreateJobs inserts jobs in one transaction with multi-row inserts of
+// batchInsertSize jobs each. Jobs must not have dependencies or debounce
+// keys. IDs are filled in on success.
+func (r *JobRepository) CreateJobs(ctx context.Context, jobs []*models.Job) error {
+	for _, j := range jobs {
+		prepareJob(j)
+	}
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return tx.CreateInBatches(jobs, batchInsertSize).Error
+	})
+	if err != nil {
+		return fmt.Errorf("create jobs: %w", err)
+	}
+	return nil
+}
+
+// CreateBulkOperation saves op as pending, to be run by a worker. Only jobs
+// that exist now are affected: op.MaxJobID is set to the highest job ID and
+// op.Total to the number of matching jobs. An operation that matches no
+// jobs is completed straight away.
+func (r *JobRepository) CreateBulkOperation(ctx context.Context, op *models.BulkOperation) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.Job{}).Select("COALESCE(MAX(id), 0)").Scan(&op.MaxJobID).Error; err != nil {
+			return err
+		}
+		var total int64
+		if err := bulkTargets(tx.Model(&models.Job{}), op).Count(&total).Error; err != nil {
+			return err
+		}
+		op.Total = int(total)
+		op.Status = models.BulkOperationStatusPending
+		if total == 0 {
+			now := tx.NowFunc()
+			op.Status = models.BulkOperationStatusCompleted
+			op.StartedAt = &now
+			op.FinishedAt = &now
+		}
+		return tx.Create(op).Error
+	})
+	if err != nil {
+		return fmt.Errorf("create bulk operation: %w", err)
+	}
+	return nil
+}
+
+// GetBulkOperation retrieves a bulk operation and its progress by ID.
+func (r *JobRepository) GetBulkOperation(ctx context.Context, id uint) (*models.BulkOperation, error) {
+	var op models.BulkOperation
+	if err := r.db.WithContext(ctx).First(&op, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w: %w", job.ErrBulkOperationNotFound, err)
+		}
+		return nil, fmt.Errorf("get bulk operation: %w", err)
+	}
+	return &op, nil
+}
+
+// ClaimBulkOperation marks the oldest pending bulk operation running and
+// returns it. Running operations without progress for staleAfter, whose
+// worker presumably died, are taken over too. It returns nil if there is
+// nothing to run.
+func (r *JobRepository) ClaimBulkOperation(ctx context.Context, staleAfter time.Duration) (*models.BulkOperation, error) {
+	var op models.BulkOperation
+	var found bool
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? OR (status = ? AND updated_at < ?)",
+				models.BulkOperationStatusPending,
+				models.BulkOperationStatusRunning, time.Now().Add(-staleAfter)).
+			Order("id").
+			Limit(1).
+			Find(&op)
+		if res.Error != nil || res.RowsAffected == 0 {
+			return res.Error
+		}
+		found = true
+		op.Status = models.BulkOperationStatusRunning
+		if op.StartedAt == nil {
+			now := tx.NowFunc()
+			op.StartedAt = &now
+		}
+		return tx.Save(&op).Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("claim bulk operation: %w", err)
+	}
+	if !found {
+		return nil, nil
+	}
+	return &op, nil
+}
+
+// ProcessBulkBatch applies op to up to limit of the jobs it has not
+// processed yet, in job ID order, and saves its progress in the same
+// transaction. op is updated; it is completed once a batch comes up short.
+func (r *JobRepository) ProcessBulkBatch(ctx context.Context, op *models.BulkOperation, limit int) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(op, op.ID).Error; err != nil {
+			return err
+		}
+		if op.Status != models.BulkOperationStatusRunning {
+			return nil
+		}
+
+		var ids []uint
+		if err := bulkTargets(tx.Model(&models.Job{}), op).
+			Clauses(clause.Locking{Strength: "UPDATE"}).
+			Order("id").
+			Limit(limit).
+			Pluck("id", &ids).Error; err != nil {
+			return fmt.Errorf("select jobs: %w", err)
+		}
+
+		if len(ids) > 0 {
+			n, err := applyBulkAction(tx, op, ids)
+			if err != nil {
+				return fmt.Errorf("%s jobs: %w", op.Action, err)
+			}
+			op.Processed += int(n)
+			op.LastJobID = ids[len(ids)-1]
+		}
+		if len(ids) < limit {
+			now := tx.NowFunc()
+			op.Status = models.BulkOperationStatusCompleted
+			op.FinishedAt = &now
+		}
+		return tx.Save(op).Error
+	})
+	if err != nil {
+		return fmt.Errorf("process bulk operation %d: %w", op.ID, err)
+	}
+	return nil
+}
+
+// FailBulkOperation stops a bulk operation with an error. Jobs it already
+// processed stay processed.
+func (r *JobRepository) FailBulkOperation(ctx context.Context, id uint, errMsg string) error {
+	if err := r.db.WithContext(ctx).
+		Model(&models.BulkOperation{}).
+		Where("id = ?", id).
+		Updates(map[string]any{
+			"status":      models.BulkOperationStatusFailed,
+			"error":       errMsg,
+			"finished_at": time.Now(),
+		}).Error; err != nil {
+		return fmt.Errorf("fail bulk operation: %w", err)
+	}
+	return nil
+}
+
+// bulkTargets restricts query to the jobs op applies to that it has not
+// processed yet.
+func bulkTargets(query *gorm.DB, op *models.BulkOperation) *gorm.DB {
+	query = query.
+		Where("id > ? AND id <= ?", op.LastJobID, op.MaxJobID).
+		Where("status IN ?", models.BulkActionStatuses[op.Action])
+	if op.FilterQueue != "" {
+		query = query.Where("queue = ?", op.FilterQueue)
+	}
+	if op.FilterStatus != "" {
+		query = query.Where("status = ?", op.FilterStatus)
+	}
+	if op.CreatedAfter != nil {
+		query = query.Where("created_at >= ?", *op.CreatedAfter)
+	}
+	if op.CreatedBefore != nil {
+		query = query.Where("created_at < ?", *op.CreatedBefore)
+	}
+	if op.ErrorContains != "" {
+		query = query.Where("strpos(lower(error), lower(?)) > 0", op.ErrorContains)
+	}
+	switch op.Action {
+	case models.BulkActionRetry:
+		// Batches and sagas have already counted these jobs as finished
+		// and moved on; running them again would count them twice.
+		query = query.Where("batch_id IS NULL").Where(`NOT EXISTS (
+			SELECT 1 FROM saga_steps s
+			WHERE s.job_id = jobs.id OR s.compensation_job_id = jobs.id
+		)`)
+	case models.BulkActionDelete:
+		// Deleting a step would remove it from its saga.
+		query = query.Where(`NOT EXISTS (
+			SELECT 1 FROM saga_steps s
+			WHERE s.job_id = jobs.id OR s.compensation_job_id = jobs.id
+		)`)
+	}
+	return query
+}
+
+// applyBulkAction applies op's action to the locked jobs in ids and
+// returns how many jobs it changed.
+func applyBulkAction(tx *gorm.DB, op *models.BulkOperation, ids []uint) (int64, error) {
+	jobs := tx.Model(&models.Job{}).Where("id IN ?", ids)
+
+	switch op.Action {
+	case models.BulkActionRetry:
+		// Jobs with dependencies wait for them again, so one whose
+		// dependency did not complete is failed or skipped again instead
+		// of running. Expired jobs would expire again straight away if
+		// they kept their expires_at; jobs with a queue TTL start it again
+		// when they are queued.
+		hasDeps := "EXISTS (SELECT 1 FROM job_dependencies d WHERE d.job_id = jobs.id)"
+		res := jobs.Updates(map[string]any{
+			"status":       gorm.Expr("CASE WHEN "+hasDeps+" THEN ? ELSE ? END", config.JobStatusWaiting, config.JobStatusQueued),
+			"attempts":     0,
+			"error":        "",
+			"available_at": time.Now(),
+			"expires_at": gorm.Expr("CASE WHEN ttl_seconds > 0 AND NOT " + hasDeps +
+				" THEN now() + ttl_seconds * interval '1 second' END"),
+			"locked_at": nil,
+			"locked_by": nil,
+		})
+		if res.Error != nil {
+			return 0, res.Error
+		}
+		if err := resolveWaiting(tx, ids); err != nil {
+			return 0, err
+		}
+		return res.RowsAffected, nil
+
+	case models.BulkActionCancel:
+		res := jobs.Updates(map[string]any{
+			"status": config.JobStatusCancelled,
+			"error":  fmt.Sprintf("cancelled by bulk operation %d", op.ID),
+		})
+		if res.Error != nil {
+			return 0, res.Error
+		}
+		// Cancelled jobs did not complete, like failed ones.
+		if err := releaseDependents(tx, ids); err != nil {
+			return 0, fmt.Errorf("release dependents: %w", err)
+		}
+		if err := settleBatches(tx, ids); err != nil {
+			return 0, err
+		}
+		for _, id := range ids {
+			if err := advanceSaga(tx, id); err != nil {
+				return 0, err
+			}
+		}
+		return res.RowsAffected, nil
+
+	case models.BulkActionDelete:
+		// Unfinished jobs are cancelled first, so their dependents and
+		// batches treat them as cancelled. Jobs waiting on deleted
+		// finished jobs no longer wait for them.
+		var unfinished []uint
+		if err := tx.Model(&models.Job{}).
+			Where("id IN ? AND status IN ?", ids, []config.JobStatus{config.JobStatusQueued, config.JobStatusWaiting}).
+			Pluck("id", &unfinished).Error; err != nil {
+			return 0, err
+		}
+		if len(unfinished) > 0 {
+			if err := tx.Model(&models.Job{}).
+				Where("id IN ?", unfinished).
+				Updates(map[string]any{
+					"status": config.JobStatusCancelled,
+					"error":  fmt.Sprintf("deleted by bulk operation %d", op.ID),
+				}).Error; err != nil {
+				return 0, err
+			}
+			if err := releaseDependents(tx, unfinished); err != nil {
+				return 0, fmt.Errorf("release dependents: %w", err)
+			}
+			if err := settleBatches(tx, unfinished); err != nil {
+				return 0, err
+			}
+		}
+		dependents, err := waitingDependents(tx, ids)
+		if err != nil {
+			return 0, err
+		}
+		res := tx.Where("id IN ?", ids).Delete(&models.Job{})
+		if res.Error != nil {
+			return 0, res.Error
+		}
+		if err := resolveWaiting(tx, dependents); err != nil {
+			return 0, err
+		}
+		return res.RowsAffected, nil
+
+	case models.BulkActionMove:
+		res := jobs.Update("queue", op.TargetQueue)
+		return res.RowsAffected, res.Error
+	}
+	return 0, fmt.Errorf("unknown bulk action %q", op.Action)
+}
@@ -0,0 +1,91 @@
+// internal/storage/postgres/job_debounce.go
+package postgres
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/joshu-sajeev/goqueue/internal/config"
+	"github.com/joshu-sajeev/goqueue/internal/job"
+	"github.com/joshu-sajeev/goqueue/internal/models"
+	"gorm.io/gorm"
+)
+
+// insertJob inserts a prepared job, applying its debounce key and
+// dependencies.
+func insertJob(tx *gorm.DB, j *models.Job) error {
+	if j.DebounceKey != nil {
+		return createDebounced(tx, j)
+	}
+	return createWithDependencies(tx, j)
+}
+
+// createDebounced inserts a job with a debounce key. In debounce mode a
+// job with the key that has not started yet takes j's payload and is
+// pushed out to the end of the window, and j is loaded with it instead of
+// being inserted. In throttle mode j is dropped with a *job.ThrottledError
+// if a job with the key was created within the window. Enqueues of the
+// same key are serialized with an advisory lock, so concurrent ones cannot
+// both insert.
+func createDebounced(tx *gorm.DB, j *models.Job) error {
+	if err := tx.Exec("SELECT pg_advisory_xact_lock(hashtextextended(?, 0))",
+		"debounce:"+j.Queue+":"+*j.DebounceKey).Error; err != nil {
+		return fmt.Errorf("lock debounce key: %w", err)
+	}
+
+	var existing models.Job
+	if j.DebounceMode == config.DebounceModeThrottle {
+		// The window and created_at both come from the database clock, so
+		// clock skew between API hosts cannot change what is throttled.
+		var now time.Time
+		if err := tx.Raw("SELECT clock_timestamp()").Scan(&now).Error; err != nil {
+			return fmt.Errorf("read database time: %w", err)
+		}
+		res := tx.Where("queue = ? AND debounce_key = ? AND created_at > ?", j.Queue, *j.DebounceKey, now.Add(-j.DebounceWindow)).
+			Order("created_at DESC").
+			Limit(1).
+			Find(&existing)
+		if res.Error != nil {
+			return fmt.Errorf("find throttling job: %w", res.Error)
+		}
+		if res.RowsAffected > 0 {
+			return &job.ThrottledError{JobID: existing.ID, Until: existing.CreatedAt.Add(j.DebounceWindow)}
+		}
+		j.CreatedAt = now
+		return tx.Create(j).Error
+	}
+
+	if end := time.Now().Add(j.DebounceWindow); j.AvailableAt.Before(end) {
+		j.AvailableAt = end
+	}
+	res := tx.Where("queue = ? AND debounce_key = ? AND status = ? AND attempts = 0", j.Queue, *j.DebounceKey, config.JobStatusQueued).
+		Order("id DESC").
+		Limit(1).
+		Find(&existing)
+	if res.Error != nil {
+		return fmt.Errorf("find pending job: %w", res.Error)
+	}
+	if res.RowsAffected == 0 {
+		return tx.Create(j).Error
+	}
+
+	// The job may be acquired between the lookup and the update. The update
+	// waits for the row lock of a concurrent acquire and checks the status
+	// again; if the job no longer matches, a new job is inserted.
+	upd := tx.Model(&models.Job{}).
+		Where("id = ? AND status = ?", existing.ID, config.JobStatusQueued).
+		Updates(map[string]any{
+			"payload":      j.Payload,
+			"max_retries":  j.MaxRetries,
+			"available_at": j.AvailableAt,
+			"expires_at":   j.ExpiresAt,
+			"ttl_seconds":  j.TTLSeconds,
+		})
+	if upd.Error != nil {
+		return fmt.Errorf("debounce job %d: %w", existing.ID, upd.Error)
+	}
+	if upd.RowsAffected == 0 {
+		return tx.Create(j).Error
+	}
+	return tx.First(j, existing.ID).Error
+}
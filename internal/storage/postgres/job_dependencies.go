@@ -0,0 +1,221 @@
+// internal/storage/postgres/job_dependencies.go
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/joshu-sajeev/goqueue/internal/config"
+	"github.com/joshu-sajeev/goqueue/internal/job"
+	"github.com/joshu-sajeev/goqueue/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// unrunnableStatuses are the dependency statuses that stop a waiting job
+// from ever running.
+var unrunnableStatuses = []config.JobStatus{
+	config.JobStatusFailed,
+	config.JobStatusSkipped,
+	config.JobStatusExpired,
+	config.JobStatusCancelled,
+}
+
+// queuedExpiresAt is the expires_at of a job being queued: its queue TTL
+// counted from when it becomes available, or its current expires_at if it
+// has no TTL.
+const queuedExpiresAt = `CASE WHEN ttl_seconds > 0
+	THEN GREATEST(now(), available_at) + ttl_seconds * interval '1 second'
+	ELSE expires_at END`
+
+// startWaiting marks j as waiting. Its queue TTL only starts when it is
+// queued, so an expires_at derived from the TTL is cleared until then.
+func startWaiting(j *models.Job) {
+	j.Status = config.JobStatusWaiting
+	if j.TTLSeconds > 0 {
+		j.ExpiresAt = nil
+	}
+}
+
+// CreateWorkflow inserts a graph of jobs in one transaction. jobs must be
+// in topological order: deps[i] lists the indexes of the jobs that job i
+// depends on, and every index must be smaller than i. Jobs may also
+// depend on existing jobs through their DependsOn field. IDs are filled
+// in on success.
+func (r *JobRepository) CreateWorkflow(ctx context.Context, jobs []*models.Job, deps [][]int) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for i, j := range jobs {
+			prepareJob(j)
+			for _, d := range deps[i] {
+				if d >= i {
+					return fmt.Errorf("job %d depends on job %d, which comes later", i, d)
+				}
+				j.DependsOn = append(j.DependsOn, jobs[d].ID)
+			}
+			if err := createWithDependencies(tx, j); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("create workflow: %w", err)
+	}
+	return nil
+}
+
+// createWithDependencies inserts job and its dependency edges. A job whose
+// dependencies have all completed is queued straight away; otherwise it
+// waits, or fails or is skipped if a dependency already failed.
+func createWithDependencies(tx *gorm.DB, j *models.Job) error {
+	if len(j.DependsOn) == 0 {
+		return tx.Create(j).Error
+	}
+
+	deps := slices.Clone(j.DependsOn)
+	slices.Sort(deps)
+	deps = slices.Compact(deps)
+
+	// FOR SHARE makes a dependency that is completing concurrently wait
+	// until this job is visible, so its completion can promote it.
+	var found []uint
+	if err := tx.Model(&models.Job{}).
+		Clauses(clause.Locking{Strength: "SHARE"}).
+		Where("id IN ?", deps).
+		Order("id").
+		Pluck("id", &found).Error; err != nil {
+		return err
+	}
+	if len(found) != len(deps) {
+		var missing []uint
+		for _, id := range deps {
+			if !slices.Contains(found, id) {
+				missing = append(missing, id)
+			}
+		}
+		return &job.DependencyNotFoundError{IDs: missing}
+	}
+
+	startWaiting(j)
+	if err := tx.Create(j).Error; err != nil {
+		return err
+	}
+
+	edges := make([]models.JobDependency, len(deps))
+	for i, id := range deps {
+		edges[i] = models.JobDependency{JobID: j.ID, DependsOnID: id}
+	}
+	if err := tx.Create(&edges).Error; err != nil {
+		return err
+	}
+
+	if err := resolveWaiting(tx, []uint{j.ID}); err != nil {
+		return err
+	}
+	// Reload the status resolveWaiting may have changed.
+	return tx.First(j, j.ID).Error
+}
+
+// releaseDependents re-evaluates the waiting jobs that depend on ids after
+// those jobs completed, failed, were skipped, expired or were cancelled.
+func releaseDependents(tx *gorm.DB, ids []uint) error {
+	dependents, err := waitingDependents(tx, ids)
+	if err != nil {
+		return err
+	}
+	return resolveWaiting(tx, dependents)
+}
+
+func waitingDependents(tx *gorm.DB, ids []uint) ([]uint, error) {
+	var dependents []uint
+	err := tx.Table("job_dependencies").
+		Distinct("job_dependencies.job_id").
+		Joins("JOIN jobs ON jobs.id = job_dependencies.job_id").
+		Where("job_dependencies.depends_on_id IN ?", ids).
+		Where("jobs.status = ?", config.JobStatusWaiting).
+		Pluck("job_dependencies.job_id", &dependents).Error
+	return dependents, err
+}
+
+// resolveWaiting queues the waiting jobs in ids whose dependencies have
+// all completed, starting their queue TTL. Jobs with a dependency that
+// failed, was skipped, expired or was cancelled are failed or skipped.
+// Failures cascade to the dependents of those jobs and are counted
+// against their batches.
+func resolveWaiting(tx *gorm.DB, ids []uint) error {
+	for len(ids) > 0 {
+		// Lock the candidates so that two dependencies completing at the
+		// same time are serialized here and the second one sees the first
+		// as completed.
+		var locked []uint
+		if err := tx.Model(&models.Job{}).
+			Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id IN ?", ids).
+			Where("status = ?", config.JobStatusWaiting).
+			Order("id").
+			Pluck("id", &locked).Error; err != nil {
+			return fmt.Errorf("lock waiting jobs: %w", err)
+		}
+		if len(locked) == 0 {
+			return nil
+		}
+
+		var failed []uint
+		if err := tx.Raw(`
+			UPDATE jobs SET
+				status = CASE WHEN on_dependency_failure = ? THEN ? ELSE ? END,
+				error = 'dependency ' || (
+					SELECT MIN(d.depends_on_id) FROM job_dependencies d
+					JOIN jobs p ON p.id = d.depends_on_id
+					WHERE d.job_id = jobs.id AND p.status IN ?
+				)::text || ' did not complete',
+				updated_at = now()
+			WHERE id IN ? AND EXISTS (
+				SELECT 1 FROM job_dependencies d
+				JOIN jobs p ON p.id = d.depends_on_id
+				WHERE d.job_id = jobs.id AND p.status IN ?
+			)
+			RETURNING id`,
+			config.DependencyFailureSkip, config.JobStatusSkipped, config.JobStatusFailed,
+			unrunnableStatuses, locked, unrunnableStatuses,
+		).Scan(&failed).Error; err != nil {
+			return fmt.Errorf("fail waiting jobs: %w", err)
+		}
+
+		if err := tx.Exec(`
+			UPDATE jobs SET
+				status = ?,
+				payload = CASE WHEN pass_results THEN
+					COALESCE(payload, '{}'::jsonb) || jsonb_build_object('dependency_results', (
+						SELECT jsonb_object_agg(p.id::text, COALESCE(p.result, 'null'::jsonb))
+						FROM job_dependencies d
+						JOIN jobs p ON p.id = d.depends_on_id
+						WHERE d.job_id = jobs.id
+					))
+				ELSE payload END,
+				expires_at = `+queuedExpiresAt+`,
+				updated_at = now()
+			WHERE id IN ? AND status = ? AND NOT EXISTS (
+				SELECT 1 FROM job_dependencies d
+				JOIN jobs p ON p.id = d.depends_on_id
+				WHERE d.job_id = jobs.id AND p.status <> ?
+			)`,
+			config.JobStatusQueued, locked, config.JobStatusWaiting, config.JobStatusCompleted,
+		).Error; err != nil {
+			return fmt.Errorf("queue ready jobs: %w", err)
+		}
+
+		if len(failed) == 0 {
+			return nil
+		}
+		if err := settleBatches(tx, failed); err != nil {
+			return err
+		}
+		var err error
+		if ids, err = waitingDependents(tx, failed); err != nil {
+			return err
+		}
+	}
+	return nil
+}
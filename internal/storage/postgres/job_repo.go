@@ -28,17 +28,38 @@ var _ job.JobRepoInterface = (*JobRepository)(nil)
 
 // Create inserts a new job record into the database. It uses the provided
 // context for cancellation and timeout propagation. Returns an error if the
-// database operation fails.
+// database operation fails. Jobs with DependsOn set wait until those jobs
+// have completed. Jobs with a DebounceKey may be merged into a pending job,
+// which job is then loaded with, or dropped with a *job.ThrottledError.
 func (r *JobRepository) Create(ctx context.Context, job *models.Job) error {
+	prepareJob(job)
+
+	var err error
+	if len(job.DependsOn) == 0 && job.DebounceKey == nil {
+		err = r.db.WithContext(ctx).Create(job).Error
+	} else {
+		err = r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			return insertJob(tx, job)
+		})
+	}
+	if err != nil {
+		return fmt.Errorf("create job: %w", err)
+	}
+	return nil
+}
+
+// prepareJob sets the fields every new job starts with.
+func prepareJob(job *models.Job) {
 	job.Status = config.JobStatusQueued
 	if job.AvailableAt.IsZero() {
 		job.AvailableAt = time.Now()
 	}
-
-	if err := r.db.WithContext(ctx).Create(job).Error; err != nil {
-		return fmt.Errorf("create job: %w", err)
+	if job.OnDependencyFailure == "" {
+		job.OnDependencyFailure = config.DependencyFailureFail
+	}
+	if job.ConcurrencyKey != nil && job.ConcurrencyLimit == 0 {
+		job.ConcurrencyLimit = 1
 	}
-	return nil
 }
 
 // Get retrieves a single job record by its ID. Returns the job if found,
@@ -108,6 +129,31 @@ func (r *JobRepository) List(ctx context.Context, queue string) ([]models.Job, e
 	return jobs, nil
 }
 
+// QueueStats counts the jobs of every queue by status.
+func (r *JobRepository) QueueStats(ctx context.Context) (map[string]map[config.JobStatus]int64, error) {
+	var rows []struct {
+		Queue  string
+		Status config.JobStatus
+		Count  int64
+	}
+	if err := r.db.WithContext(ctx).
+		Model(&models.Job{}).
+		Select("queue, status, COUNT(*) AS count").
+		Group("queue, status").
+		Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("queue stats: %w", err)
+	}
+
+	stats := make(map[string]map[config.JobStatus]int64)
+	for _, row := range rows {
+		if stats[row.Queue] == nil {
+			stats[row.Queue] = make(map[config.JobStatus]int64)
+		}
+		stats[row.Queue][row.Status] = row.Count
+	}
+	return stats, nil
+}
+
 // AcquireNext atomically claims the next available job for a worker
 // This is THE CRITICAL METHOD for queue operation
 func (r *JobRepository) AcquireNext(ctx context.Context, queue string, workerID uint, lockDuration time.Duration) (*dto.JobDTO, error) {
@@ -117,27 +163,70 @@ func (r *JobRepository) AcquireNext(ctx context.Context, queue string, workerID
 	var found bool
 	// Transaction to prevent race conditions
 	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := expireJobs(tx, queue, now); err != nil {
+			return err
+		}
+
 		// Find first available job:
 		// - status = 'queued'
 		// - available_at <= now (ready to run)
+		// - expires_at is unset or still in the future
 		// - (locked_at IS NULL OR locked_at < now - grace period)
-		query := tx.Where("queue = ?", queue).
-			Where("status = ?", config.JobStatusQueued).
-			Where("available_at <= ?", now).
-			Where("(locked_at IS NULL OR locked_at < ?)", now.Add(-lockDuration)).
-			Order("available_at ASC, id ASC"). // FIFO + priority
-			Limit(1).
-			Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}) // PostgreSQL row-level lock
-
-		if err := query.First(&job).Error; err != nil {
-			if errors.Is(err, gorm.ErrRecordNotFound) {
+		// - no earlier job in its group is queued or running
+		// - fewer running jobs with its concurrency key than its limit
+		var full []string
+		for {
+			query := tx.Where("queue = ?", queue).
+				Where("status = ?", config.JobStatusQueued).
+				Where("available_at <= ?", now).
+				Where("(expires_at IS NULL OR expires_at > ?)", now).
+				Where("(locked_at IS NULL OR locked_at < ?)", now.Add(-lockDuration)).
+				Where(`(group_key IS NULL OR NOT EXISTS (
+					SELECT 1 FROM jobs earlier
+					WHERE earlier.queue = jobs.queue
+					AND earlier.group_key = jobs.group_key
+					AND earlier.id < jobs.id
+					AND earlier.status IN ?
+				))`, []config.JobStatus{config.JobStatusQueued, config.JobStatusRunning}).
+				Where(`(concurrency_key IS NULL OR (
+					SELECT COUNT(*) FROM jobs running
+					WHERE running.concurrency_key = jobs.concurrency_key
+					AND running.status = ?
+				) < concurrency_limit)`, config.JobStatusRunning)
+			if len(full) > 0 {
+				query = query.Where("(concurrency_key IS NULL OR concurrency_key NOT IN ?)", full)
+			}
+			query = query.
+				Order("available_at ASC, id ASC"). // FIFO + priority
+				Limit(1).
+				Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}) // PostgreSQL row-level lock
+
+			job = models.Job{}
+			if err := query.First(&job).Error; err != nil {
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					return nil
+				}
+				return err
+			}
+
+			if job.ID == 0 {
 				return nil
 			}
-			return err
-		}
+			if job.ConcurrencyKey == nil {
+				break
+			}
 
-		if job.ID == 0 {
-			return nil
+			ok, err := claimConcurrencySlot(tx, *job.ConcurrencyKey, job.ConcurrencyLimit)
+			if err != nil {
+				return err
+			}
+			if ok {
+				break
+			}
+			// Another worker filled the last slot after the query above
+			// ran, or is claiming one right now; look for a job with a
+			// different key.
+			full = append(full, *job.ConcurrencyKey)
 		}
 
 		found = true
@@ -158,35 +247,175 @@ func (r *JobRepository) AcquireNext(ctx context.Context, queue string, workerID
 	}
 
 	return &dto.JobDTO{
-		ID:      job.ID,
-		Queue:   job.Queue,
-		Payload: job.Payload,
+		ID:         job.ID,
+		Queue:      job.Queue,
+		Payload:    job.Payload,
+		Attempts:   job.Attempts,
+		MaxRetries: job.MaxRetries,
 	}, nil
 }
 
+// expireJobs moves the queued jobs of queue whose expires_at is not after
+// now to the expired status. Jobs locked by another transaction are left
+// for the next call. Expired jobs count as failed for the jobs that depend
+// on them, their batches and their sagas.
+func expireJobs(tx *gorm.DB, queue string, now time.Time) error {
+	var expired []uint
+	if err := tx.Raw(`
+		UPDATE jobs SET
+			status = ?,
+			error = 'expired before it ran',
+			locked_at = NULL,
+			locked_by = NULL,
+			updated_at = now()
+		WHERE id IN (
+			SELECT id FROM jobs
+			WHERE queue = ? AND status = ? AND expires_at <= ?
+			ORDER BY id
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING id`,
+		config.JobStatusExpired, queue, config.JobStatusQueued, now,
+	).Scan(&expired).Error; err != nil {
+		return fmt.Errorf("expire jobs: %w", err)
+	}
+	if len(expired) == 0 {
+		return nil
+	}
+
+	if err := releaseDependents(tx, expired); err != nil {
+		return fmt.Errorf("release dependents: %w", err)
+	}
+	if err := settleBatches(tx, expired); err != nil {
+		return err
+	}
+	for _, id := range expired {
+		if err := advanceSaga(tx, id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// claimConcurrencySlot reports whether fewer than limit jobs with the
+// concurrency key are running. It takes a transaction-scoped advisory lock
+// on the key first, so workers acquiring jobs with the same key count one
+// at a time, each seeing the jobs the others have started. The lock is
+// only tried: AcquireNext can hold the locks of several keys until it
+// commits, and waiting for one while holding another could deadlock with
+// a worker that tried the keys in the opposite order. A key locked by
+// another worker is reported as full.
+func claimConcurrencySlot(tx *gorm.DB, key string, limit int) (bool, error) {
+	var locked bool
+	if err := tx.Raw("SELECT pg_try_advisory_xact_lock(hashtextextended(?, 0))", key).Scan(&locked).Error; err != nil {
+		return false, fmt.Errorf("lock concurrency key: %w", err)
+	}
+	if !locked {
+		return false, nil
+	}
+	var running int64
+	if err := tx.Model(&models.Job{}).
+		Where("concurrency_key = ? AND status = ?", key, config.JobStatusRunning).
+		Count(&running).Error; err != nil {
+		return false, fmt.Errorf("count running jobs: %w", err)
+	}
+	return running < int64(limit), nil
+}
+
+// finishRun applies updates to job id if it is running under workerID and
+// returns job.ErrLeaseLost otherwise. A run whose lock was released, for
+// example by the janitor, therefore cannot finish the job a second time.
+func finishRun(tx *gorm.DB, id uint, workerID uint, updates map[string]any) error {
+	res := tx.Model(&models.Job{}).
+		Where("id = ?", id).
+		Where("status = ?", config.JobStatusRunning).
+		Where("locked_by = ?", workerID).
+		Updates(updates)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return job.ErrLeaseLost
+	}
+	return nil
+}
+
 // MarkCompleted finalizes the job after successful execution.
 // It sets the status to 'completed', clears locks, and saves the final result.
-func (r *JobRepository) MarkCompleted(ctx context.Context, id uint, result datatypes.JSON) error {
+func (r *JobRepository) MarkCompleted(ctx context.Context, id uint, workerID uint, result datatypes.JSON) error {
+	return r.CompleteWithChildren(ctx, id, workerID, result, nil)
+}
+
+// CompleteWithChildren marks the job completed and inserts the child jobs
+// its handler enqueued in the same transaction, so children exist if and
+// only if the parent completed. Each child's ParentID is set to id. Jobs
+// waiting on the completed job are queued once all their dependencies
+// have completed, the job is counted against its batch, and its saga
+// moves on to the next step. Children with a debounce key are merged or
+// dropped as in Create. It returns job.ErrLeaseLost, and changes nothing,
+// unless the job is running under workerID.
+func (r *JobRepository) CompleteWithChildren(ctx context.Context, id uint, workerID uint, result datatypes.JSON, children []*models.Job) error {
 	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
-		if err := tx.Model(&models.Job{}).
-			Where("id = ?", id).
-			Updates(map[string]any{
-				"status":    config.JobStatusCompleted,
-				"result":    result,
-				"locked_at": nil,
-				"locked_by": nil,
-				"error":     nil,
-			}).Error; err != nil {
+		if err := finishRun(tx, id, workerID, map[string]any{
+			"status":    config.JobStatusCompleted,
+			"result":    result,
+			"locked_at": nil,
+			"locked_by": nil,
+			"error":     nil,
+		}); err != nil {
 			return fmt.Errorf("mark completed: %w", err)
 		}
+
+		if err := releaseDependents(tx, []uint{id}); err != nil {
+			return fmt.Errorf("release dependents: %w", err)
+		}
+		if err := settleBatches(tx, []uint{id}); err != nil {
+			return err
+		}
+		if err := advanceSaga(tx, id); err != nil {
+			return err
+		}
+
+		if len(children) == 0 {
+			return nil
+		}
+		for _, child := range children {
+			prepareJob(child)
+			child.ParentID = &id
+			err := insertJob(tx, child)
+			var throttled *job.ThrottledError
+			if errors.As(err, &throttled) {
+				continue
+			}
+			if err != nil {
+				return fmt.Errorf("create child jobs: %w", err)
+			}
+		}
 		return nil
 	})
 }
 
-// Release unlocks a job (used when worker fails without updating)
+// ExtendLock pushes the lock expiry of a running job held by workerID out
+// to until. It returns false if the job is not running or is locked by a
+// different worker.
+func (r *JobRepository) ExtendLock(ctx context.Context, id uint, workerID uint, until time.Time) (bool, error) {
+	res := r.db.WithContext(ctx).Model(&models.Job{}).
+		Where("id = ?", id).
+		Where("status = ?", config.JobStatusRunning).
+		Where("locked_by = ?", workerID).
+		Update("locked_at", until)
+	if res.Error != nil {
+		return false, fmt.Errorf("extend lock: %w", res.Error)
+	}
+	return res.RowsAffected > 0, nil
+}
+
+// Release unlocks a job (used when worker fails without updating). Jobs
+// that are no longer running are left alone.
 func (r *JobRepository) Release(ctx context.Context, id uint) error {
 	if err := r.db.WithContext(ctx).Model(&models.Job{}).
 		Where("id = ?", id).
+		Where("status = ?", config.JobStatusRunning).
 		Updates(map[string]any{
 			"locked_at": nil,
 			"locked_by": nil,
@@ -197,21 +426,70 @@ func (r *JobRepository) Release(ctx context.Context, id uint) error {
 	return nil
 }
 
-// RetryLater schedules a job for retry with exponential backoff
-func (r *JobRepository) RetryLater(ctx context.Context, id uint, availableAt time.Time) error {
-	if err := r.db.WithContext(ctx).Model(&models.Job{}).
-		Where("id = ?", id).
-		Updates(map[string]any{
-			"status":       config.JobStatusQueued,
-			"available_at": availableAt,
-			"locked_at":    nil,
-			"locked_by":    nil,
-		}).Error; err != nil {
+// RetryLater schedules a job for retry with exponential backoff.
+// The failed run counts as an attempt, and its error message is stored on
+// the job so it can be inspected while the job waits for its next attempt.
+// It returns job.ErrLeaseLost unless the job is running under workerID.
+func (r *JobRepository) RetryLater(ctx context.Context, id uint, workerID uint, availableAt time.Time, errMsg string) error {
+	if err := finishRun(r.db.WithContext(ctx), id, workerID, map[string]any{
+		"status":       config.JobStatusQueued,
+		"available_at": availableAt,
+		"attempts":     gorm.Expr("attempts + ?", 1),
+		"error":        errMsg,
+		"locked_at":    nil,
+		"locked_by":    nil,
+	}); err != nil {
 		return fmt.Errorf("retry later: %w", err)
 	}
 	return nil
 }
 
+// Snooze puts a running job back in the queue until availableAt without
+// counting the run as an attempt or touching its last error. It returns
+// job.ErrLeaseLost unless the job is running under workerID.
+func (r *JobRepository) Snooze(ctx context.Context, id uint, workerID uint, availableAt time.Time) error {
+	if err := finishRun(r.db.WithContext(ctx), id, workerID, map[string]any{
+		"status":       config.JobStatusQueued,
+		"available_at": availableAt,
+		"locked_at":    nil,
+		"locked_by":    nil,
+	}); err != nil {
+		return fmt.Errorf("snooze job: %w", err)
+	}
+	return nil
+}
+
+// MarkFailed finalizes a job that will not be retried, either because the
+// error was permanent or because it ran out of retries. The failed run
+// counts as an attempt and the error message is saved.
+// Jobs waiting on it are failed or skipped according to their
+// OnDependencyFailure setting, the job is counted against its batch, and
+// its saga, if any, starts compensating. It returns job.ErrLeaseLost, and
+// changes nothing, unless the job is running under workerID.
+func (r *JobRepository) MarkFailed(ctx context.Context, id uint, workerID uint, errMsg string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := finishRun(tx, id, workerID, map[string]any{
+			"status":    config.JobStatusFailed,
+			"attempts":  gorm.Expr("attempts + ?", 1),
+			"error":     errMsg,
+			"locked_at": nil,
+			"locked_by": nil,
+		}); err != nil {
+			return fmt.Errorf("mark failed: %w", err)
+		}
+		if err := releaseDependents(tx, []uint{id}); err != nil {
+			return fmt.Errorf("release dependents: %w", err)
+		}
+		if err := settleBatches(tx, []uint{id}); err != nil {
+			return err
+		}
+		if err := advanceSaga(tx, id); err != nil {
+			return err
+		}
+		return nil
+	})
+}
+
 // ListStuckJobs finds jobs locked longer than staleDuration
 func (r *JobRepository) ListStuckJobs(ctx context.Context, staleDuration time.Duration) ([]models.Job, error) {
 	var jobs []models.Job
@@ -225,3 +503,22 @@ func (r *JobRepository) ListStuckJobs(ctx context.Context, staleDuration time.Du
 	}
 	return jobs, nil
 }
+
+// ListOrphanedJobs finds running jobs whose lease has expired and whose
+// owning worker has not sent a heartbeat within heartbeatTimeout, or is no
+// longer registered at all. Jobs are kept until their lease expires so a
+// remote worker can hold a long lease without heartbeating. The timeout is
+// measured with the database clock, which also stamps the heartbeats.
+func (r *JobRepository) ListOrphanedJobs(ctx context.Context, heartbeatTimeout time.Duration) ([]models.Job, error) {
+	var jobs []models.Job
+
+	if err := r.db.WithContext(ctx).
+		Where("status = ?", config.JobStatusRunning).
+		Where("locked_by IS NOT NULL").
+		Where("(locked_at IS NULL OR locked_at <= now())").
+		Where("NOT EXISTS (SELECT 1 FROM workers WHERE workers.id = jobs.locked_by AND workers.last_heartbeat_at >= now() - ? * interval '1 second')", heartbeatTimeout.Seconds()).
+		Find(&jobs).Error; err != nil {
+		return nil, fmt.Errorf("list orphaned jobs: %w", err)
+	}
+	return jobs, nil
+}
@@ -0,0 +1,61 @@
+// internal/storage/postgres/job_retention.go
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/joshu-sajeev/goqueue/internal/config"
+	"github.com/joshu-sajeev/goqueue/internal/models"
+)
+
+// prunable selects the jobs of a queue in a status that were last updated
+// before a cutoff, for PruneJobs and ArchiveJobs. Jobs that a waiting job
+// depends on are kept so their results stay available. Jobs of a saga are
+// kept while the saga exists: deleting a step job deletes its saga_steps
+// row.
+const prunable = `queue = ? AND status = ? AND updated_at < ?
+	AND NOT EXISTS (
+		SELECT 1 FROM job_dependencies d
+		JOIN jobs w ON w.id = d.job_id
+		WHERE d.depends_on_id = jobs.id AND w.status = ?
+	)
+	AND NOT EXISTS (
+		SELECT 1 FROM saga_steps s
+		JOIN sagas ON sagas.id = s.saga_id
+		WHERE s.job_id = jobs.id OR s.compensation_job_id = jobs.id
+	)`
+
+// PruneJobs deletes up to limit jobs of queue in status that were last
+// updated before cutoff, oldest first, and returns how many it deleted.
+// Rows locked by other transactions are skipped.
+func (r *JobRepository) PruneJobs(ctx context.Context, queue string, status config.JobStatus, cutoff time.Time, limit int) (int64, error) {
+	res := r.db.WithContext(ctx).Exec(`
+		DELETE FROM jobs WHERE id IN (
+			SELECT id FROM jobs
+			WHERE `+prunable+`
+			ORDER BY id
+			LIMIT ?
+			FOR UPDATE SKIP LOCKED
+		)`,
+		queue, status, cutoff, config.JobStatusWaiting, limit,
+	)
+	if res.Error != nil {
+		return 0, fmt.Errorf("prune jobs: %w", res.Error)
+	}
+	return res.RowsAffected, nil
+}
+
+// CountPrunable returns the number of jobs PruneJobs would delete for
+// queue, status and cutoff if it had no limit.
+func (r *JobRepository) CountPrunable(ctx context.Context, queue string, status config.JobStatus, cutoff time.Time) (int64, error) {
+	var count int64
+	if err := r.db.WithContext(ctx).
+		Model(&models.Job{}).
+		Where(prunable, queue, status, cutoff, config.JobStatusWaiting).
+		Count(&count).Error; err != nil {
+		return 0, fmt.Errorf("count prunable jobs: %w", err)
+	}
+	return count, nil
+}
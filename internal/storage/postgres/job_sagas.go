@@ -0,0 +1,247 @@
+// internal/storage/postgres/job_sagas.go
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/joshu-sajeev/goqueue/internal/config"
+	"github.com/joshu-sajeev/goqueue/internal/job"
+	"github.com/joshu-sajeev/goqueue/internal/models"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// CreateSaga inserts saga and the jobs of its steps in one transaction.
+// Each step's job waits for the previous step to complete and is skipped
+// if it fails. IDs are filled in on success.
+func (r *JobRepository) CreateSaga(ctx context.Context, saga *models.Saga, steps []*models.SagaStep) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		saga.Status = models.SagaStatusRunning
+		saga.Steps = len(steps)
+		saga.CurrentStep = 1
+		if err := tx.Create(saga).Error; err != nil {
+			return err
+		}
+
+		var prev *models.Job
+		for i, step := range steps {
+			prepareJob(step.Job)
+			if prev != nil {
+				step.Job.DependsOn = []uint{prev.ID}
+				step.Job.OnDependencyFailure = config.DependencyFailureSkip
+			}
+			if err := createWithDependencies(tx, step.Job); err != nil {
+				return err
+			}
+			step.SagaID = saga.ID
+			step.Position = i + 1
+			step.JobID = step.Job.ID
+			prev = step.Job
+		}
+		return tx.Create(steps).Error
+	})
+	if err != nil {
+		return fmt.Errorf("create saga: %w", err)
+	}
+	return nil
+}
+
+// GetSaga retrieves a saga and its steps, with the status of each step's
+// job and compensation job.
+func (r *JobRepository) GetSaga(ctx context.Context, id uint) (*models.Saga, []models.SagaStep, error) {
+	db := r.db.WithContext(ctx)
+
+	var saga models.Saga
+	if err := db.First(&saga, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, fmt.Errorf("%w: %w", job.ErrSagaNotFound, err)
+		}
+		return nil, nil, fmt.Errorf("get saga: %w", err)
+	}
+
+	var steps []models.SagaStep
+	if err := db.Where("saga_id = ?", id).Order("position").Find(&steps).Error; err != nil {
+		return nil, nil, fmt.Errorf("get saga steps: %w", err)
+	}
+
+	var ids []uint
+	for _, s := range steps {
+		ids = append(ids, s.JobID)
+		if s.CompensationJobID != nil {
+			ids = append(ids, *s.CompensationJobID)
+		}
+	}
+	var jobs []models.Job
+	if err := db.Select("id", "status").Where("id IN ?", ids).Find(&jobs).Error; err != nil {
+		return nil, nil, fmt.Errorf("get saga jobs: %w", err)
+	}
+	statuses := make(map[uint]config.JobStatus, len(jobs))
+	for _, j := range jobs {
+		statuses[j.ID] = j.Status
+	}
+	for i := range steps {
+		steps[i].JobStatus = statuses[steps[i].JobID]
+		if id := steps[i].CompensationJobID; id != nil {
+			steps[i].CompensationStatus = statuses[*id]
+		}
+	}
+
+	return &saga, steps, nil
+}
+
+// advanceSaga moves the saga that job id belongs to forward after the job
+// completed, failed, expired or was cancelled. A step that did not
+// complete starts compensation; a compensation that did not complete stops
+// it. Jobs that are not part of a saga are ignored, and so are events the
+// saga is not waiting for: a step that is not the current step of a
+// running saga, or a compensation of a saga that is not compensating. A
+// job that runs again after being reclaimed therefore cannot compensate a
+// saga twice or complete one that is compensating.
+func advanceSaga(tx *gorm.DB, id uint) error {
+	var step models.SagaStep
+	res := tx.Where("job_id = ? OR compensation_job_id = ?", id, id).Limit(1).Find(&step)
+	if res.Error != nil {
+		return fmt.Errorf("find saga step: %w", res.Error)
+	}
+	if res.RowsAffected == 0 {
+		return nil
+	}
+
+	var saga models.Saga
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&saga, step.SagaID).Error; err != nil {
+		return fmt.Errorf("lock saga: %w", err)
+	}
+	var j models.Job
+	if err := tx.Select("id", "status", "error", "result").First(&j, id).Error; err != nil {
+		return fmt.Errorf("get saga job: %w", err)
+	}
+
+	if step.JobID == id {
+		if saga.Status != models.SagaStatusRunning || step.Position != saga.CurrentStep {
+			return nil
+		}
+		switch j.Status {
+		case config.JobStatusCompleted:
+			if step.Position == saga.Steps {
+				saga.Status = models.SagaStatusCompleted
+			} else {
+				saga.CurrentStep = step.Position + 1
+			}
+		case config.JobStatusFailed, config.JobStatusExpired, config.JobStatusCancelled:
+			saga.Status = models.SagaStatusCompensating
+			saga.FailedStep = &step.Position
+			saga.Error = fmt.Sprintf("step %d failed: %s", step.Position, j.Error)
+			started, err := compensate(tx, saga.ID, step.Position)
+			if err != nil {
+				return err
+			}
+			if !started {
+				saga.Status = models.SagaStatusCompensated
+			}
+		}
+	} else {
+		if saga.Status != models.SagaStatusCompensating {
+			return nil
+		}
+		switch j.Status {
+		case config.JobStatusCompleted:
+			var remaining int64
+			if err := tx.Model(&models.SagaStep{}).
+				Where("saga_id = ? AND position < ? AND compensation_job_id IS NOT NULL", saga.ID, step.Position).
+				Count(&remaining).Error; err != nil {
+				return fmt.Errorf("count compensations: %w", err)
+			}
+			if remaining == 0 {
+				saga.Status = models.SagaStatusCompensated
+			}
+		case config.JobStatusFailed, config.JobStatusExpired, config.JobStatusCancelled:
+			saga.Status = models.SagaStatusCompensationFailed
+			saga.Error = fmt.Sprintf("%s; compensation for step %d failed: %s", saga.Error, step.Position, j.Error)
+		}
+	}
+
+	if saga.IsFinished() && saga.FinishedAt == nil {
+		now := tx.NowFunc()
+		saga.FinishedAt = &now
+	}
+	if err := tx.Save(&saga).Error; err != nil {
+		return fmt.Errorf("update saga: %w", err)
+	}
+	return nil
+}
+
+// compensate creates the compensation jobs of the steps before failed,
+// chained so that they run one after the other in reverse step order. A
+// failed compensation skips the ones after it. Each compensation whose
+// payload is a JSON object gets its step's result under "step_result".
+// It reports whether any compensation was created. It does nothing if the
+// saga already has compensation jobs.
+func compensate(tx *gorm.DB, sagaID uint, failed int) (bool, error) {
+	var existing int64
+	if err := tx.Model(&models.SagaStep{}).
+		Where("saga_id = ? AND compensation_job_id IS NOT NULL", sagaID).
+		Count(&existing).Error; err != nil {
+		return false, fmt.Errorf("count compensations: %w", err)
+	}
+	if existing > 0 {
+		return true, nil
+	}
+
+	var steps []models.SagaStep
+	if err := tx.Where("saga_id = ? AND position < ? AND compensation_queue <> ''", sagaID, failed).
+		Order("position DESC").
+		Find(&steps).Error; err != nil {
+		return false, fmt.Errorf("get saga steps: %w", err)
+	}
+
+	var prev *models.Job
+	for _, step := range steps {
+		var stepJob models.Job
+		if err := tx.Select("id", "result").First(&stepJob, step.JobID).Error; err != nil {
+			return false, fmt.Errorf("get step %d: %w", step.Position, err)
+		}
+
+		c := &models.Job{
+			Queue:               step.CompensationQueue,
+			Payload:             withStepResult(step.CompensationPayload, stepJob.Result),
+			MaxRetries:          step.CompensationMaxRetries,
+			OnDependencyFailure: config.DependencyFailureSkip,
+		}
+		prepareJob(c)
+		if prev != nil {
+			c.DependsOn = []uint{prev.ID}
+		}
+		if err := createWithDependencies(tx, c); err != nil {
+			return false, fmt.Errorf("create compensation for step %d: %w", step.Position, err)
+		}
+		if err := tx.Model(&models.SagaStep{}).
+			Where("saga_id = ? AND position = ?", sagaID, step.Position).
+			Update("compensation_job_id", c.ID).Error; err != nil {
+			return false, fmt.Errorf("record compensation for step %d: %w", step.Position, err)
+		}
+		prev = c
+	}
+	return prev != nil, nil
+}
+
+// withStepResult adds result to payload under "step_result" if payload is
+// a JSON object.
+func withStepResult(payload, result datatypes.JSON) datatypes.JSON {
+	var obj map[string]json.RawMessage
+	if json.Unmarshal(payload, &obj) != nil || obj == nil {
+		return payload
+	}
+	if len(result) == 0 {
+		result = datatypes.JSON("null")
+	}
+	obj["step_result"] = json.RawMessage(result)
+	b, err := json.Marshal(obj)
+	if err != nil {
+		return payload
+	}
+	return b
+}
@@ -0,0 +1,76 @@
+// internal/storage/postgres/payment_ledger_repo.go
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/joshu-sajeev/goqueue/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type PaymentLedgerRepository struct {
+	db *gorm.DB
+}
+
+func NewPaymentLedgerRepository(db *gorm.DB) *PaymentLedgerRepository {
+	return &PaymentLedgerRepository{db: db}
+}
+
+// Begin records txn as pending unless the ledger already has a row for its
+// PaymentID. It returns the row in the ledger and whether it was created
+// by this call; when it was not, the existing row is returned unchanged.
+func (r *PaymentLedgerRepository) Begin(ctx context.Context, txn *models.PaymentTransaction) (*models.PaymentTransaction, bool, error) {
+	txn.Status = models.PaymentStatusPending
+
+	res := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "payment_id"}}, DoNothing: true}).
+		Create(txn)
+	if res.Error != nil {
+		return nil, false, fmt.Errorf("begin payment: %w", res.Error)
+	}
+	if res.RowsAffected > 0 {
+		return txn, true, nil
+	}
+
+	var existing models.PaymentTransaction
+	if err := r.db.WithContext(ctx).First(&existing, "payment_id = ?", txn.PaymentID).Error; err != nil {
+		return nil, false, fmt.Errorf("get payment: %w", err)
+	}
+	return &existing, false, nil
+}
+
+// Complete marks the payment as charged with the provider's transaction
+// ID and returns the updated row.
+func (r *PaymentLedgerRepository) Complete(ctx context.Context, paymentID, transactionID string) (*models.PaymentTransaction, error) {
+	return r.finish(ctx, paymentID, map[string]any{
+		"status":         models.PaymentStatusCompleted,
+		"transaction_id": transactionID,
+		"error":          "",
+	})
+}
+
+// Fail marks the payment as declined by the provider.
+func (r *PaymentLedgerRepository) Fail(ctx context.Context, paymentID, errMsg string) (*models.PaymentTransaction, error) {
+	return r.finish(ctx, paymentID, map[string]any{
+		"status": models.PaymentStatusFailed,
+		"error":  errMsg,
+	})
+}
+
+func (r *PaymentLedgerRepository) finish(ctx context.Context, paymentID string, updates map[string]any) (*models.PaymentTransaction, error) {
+	var txn models.PaymentTransaction
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.PaymentTransaction{}).
+			Where("payment_id = ?", paymentID).
+			Updates(updates).Error; err != nil {
+			return err
+		}
+		return tx.First(&txn, "payment_id = ?", paymentID).Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("update payment %s: %w", paymentID, err)
+	}
+	return &txn, nil
+}
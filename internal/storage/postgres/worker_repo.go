@@ -0,0 +1,95 @@
+// internal/storage/postgres/worker_repo.go
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/joshu-sajeev/goqueue/internal/config"
+	"github.com/joshu-sajeev/goqueue/internal/models"
+	"github.com/joshu-sajeev/goqueue/internal/workerapi"
+	"gorm.io/gorm"
+)
+
+type WorkerRepository struct {
+	db *gorm.DB
+}
+
+func NewWorkerRepository(db *gorm.DB) *WorkerRepository {
+	return &WorkerRepository{db: db}
+}
+
+var _ workerapi.WorkerRepoInterface = (*WorkerRepository)(nil)
+
+// Register inserts a worker process into the workers table and fills in
+// its generated ID. StartedAt and LastHeartbeatAt default to now.
+//
+// Heartbeats are written and judged with the database clock, so clock
+// skew between hosts cannot make a live worker look dead.
+func (r *WorkerRepository) Register(ctx context.Context, worker *models.Worker) error {
+	var now time.Time
+	if err := r.db.WithContext(ctx).Raw("SELECT now()").Scan(&now).Error; err != nil {
+		return fmt.Errorf("read database time: %w", err)
+	}
+	if worker.StartedAt.IsZero() {
+		worker.StartedAt = now
+	}
+	if worker.LastHeartbeatAt.IsZero() {
+		worker.LastHeartbeatAt = now
+	}
+
+	if err := r.db.WithContext(ctx).Create(worker).Error; err != nil {
+		return fmt.Errorf("register worker: %w", err)
+	}
+	return nil
+}
+
+// Heartbeat records that the worker is still alive. Returns an error
+// wrapping gorm.ErrRecordNotFound if the worker is not registered.
+func (r *WorkerRepository) Heartbeat(ctx context.Context, id uint) error {
+	res := r.db.WithContext(ctx).Model(&models.Worker{}).
+		Where("id = ?", id).
+		Update("last_heartbeat_at", gorm.Expr("now()"))
+	if res.Error != nil {
+		return fmt.Errorf("worker heartbeat: %w", res.Error)
+	}
+	if res.RowsAffected == 0 {
+		return fmt.Errorf("worker not found: %w", gorm.ErrRecordNotFound)
+	}
+	return nil
+}
+
+// Deregister removes a worker that shut down cleanly.
+func (r *WorkerRepository) Deregister(ctx context.Context, id uint) error {
+	if err := r.db.WithContext(ctx).Delete(&models.Worker{}, id).Error; err != nil {
+		return fmt.Errorf("deregister worker: %w", err)
+	}
+	return nil
+}
+
+// RemoveDead deletes the workers that have not sent a heartbeat within
+// olderThan and hold no running jobs, such as processes that crashed
+// without calling Deregister, and returns how many it deleted. Workers
+// still holding jobs are kept until the janitor has reclaimed them.
+func (r *WorkerRepository) RemoveDead(ctx context.Context, olderThan time.Duration) (int64, error) {
+	res := r.db.WithContext(ctx).
+		Where("last_heartbeat_at < now() - ? * interval '1 second'", olderThan.Seconds()).
+		Where("NOT EXISTS (SELECT 1 FROM jobs WHERE jobs.locked_by = workers.id AND jobs.status = ?)", config.JobStatusRunning).
+		Delete(&models.Worker{})
+	if res.Error != nil {
+		return 0, fmt.Errorf("remove dead workers: %w", res.Error)
+	}
+	return res.RowsAffected, nil
+}
+
+// List returns all registered workers, most recently started first.
+func (r *WorkerRepository) List(ctx context.Context) ([]models.Worker, error) {
+	var workers []models.Worker
+	if err := r.db.WithContext(ctx).
+		Order("started_at DESC, id DESC").
+		Find(&workers).Error; err != nil {
+		return nil, fmt.Errorf("list workers: %w", err)
+	}
+	return workers, nil
+}
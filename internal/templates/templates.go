@@ -0,0 +1,256 @@
+// Package templates loads named email templates and renders them with job
+// data. A template is a directory containing subject.tmpl and at least one
+// of body.txt.tmpl and body.html.tmpl. The subject and text body use
+// text/template, the HTML body uses html/template so data is escaped.
+package templates
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"io/fs"
+	"os"
+	"slices"
+	"text/template"
+	"text/template/parse"
+)
+
+const (
+	subjectFile = "subject.tmpl"
+	textFile    = "body.txt.tmpl"
+	htmlFile    = "body.html.tmpl"
+)
+
+// ErrNotFound is returned when rendering a template that was not loaded.
+var ErrNotFound = errors.New("template not found")
+
+// MissingVariableError is returned when a template references a variable
+// that is not present in the data.
+type MissingVariableError struct {
+	Template string
+	Variable string
+}
+
+func (e *MissingVariableError) Error() string {
+	return fmt.Sprintf("template %s: missing variable %q", e.Template, e.Variable)
+}
+
+// Rendered is the output of a template.
+type Rendered struct {
+	Subject string
+	Text    string
+	HTML    string
+}
+
+type emailTemplate struct {
+	subject *template.Template
+	text    *template.Template
+	html    *htmltemplate.Template
+	// required are the top-level data keys the templates use, in order of
+	// first use.
+	required []string
+}
+
+// Store holds parsed templates by name. It is safe for concurrent use.
+type Store struct {
+	templates map[string]*emailTemplate
+}
+
+// LoadDir loads every template directory under dir.
+func LoadDir(dir string) (*Store, error) {
+	return Load(os.DirFS(dir))
+}
+
+// Load loads every template directory at the root of fsys. The directory
+// name is the template name.
+func Load(fsys fs.FS) (*Store, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, fmt.Errorf("read templates: %w", err)
+	}
+
+	s := &Store{templates: map[string]*emailTemplate{}}
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		t, err := loadTemplate(fsys, e.Name())
+		if err != nil {
+			return nil, fmt.Errorf("load template %s: %w", e.Name(), err)
+		}
+		s.templates[e.Name()] = t
+	}
+	return s, nil
+}
+
+func loadTemplate(fsys fs.FS, name string) (*emailTemplate, error) {
+	read := func(file string) (string, bool, error) {
+		b, err := fs.ReadFile(fsys, name+"/"+file)
+		if errors.Is(err, fs.ErrNotExist) {
+			return "", false, nil
+		}
+		return string(b), err == nil, err
+	}
+
+	var t emailTemplate
+	src, ok, err := read(subjectFile)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, fmt.Errorf("missing %s", subjectFile)
+	}
+	if t.subject, err = template.New("subject").Option("missingkey=error").Parse(src); err != nil {
+		return nil, err
+	}
+
+	if src, ok, err = read(textFile); err != nil {
+		return nil, err
+	} else if ok {
+		if t.text, err = template.New("text").Option("missingkey=error").Parse(src); err != nil {
+			return nil, err
+		}
+	}
+
+	if src, ok, err = read(htmlFile); err != nil {
+		return nil, err
+	} else if ok {
+		if t.html, err = htmltemplate.New("html").Option("missingkey=error").Parse(src); err != nil {
+			return nil, err
+		}
+	}
+
+	if t.text == nil && t.html == nil {
+		return nil, fmt.Errorf("missing %s or %s", textFile, htmlFile)
+	}
+
+	trees := []*parse.Tree{t.subject.Tree}
+	if t.text != nil {
+		trees = append(trees, t.text.Tree)
+	}
+	if t.html != nil {
+		trees = append(trees, t.html.Tree)
+	}
+	t.required = requiredKeys(trees...)
+	return &t, nil
+}
+
+// requiredKeys returns the top-level data keys that trees reference as
+// .key or $.key, in order of first use. Fields used where the dot is not
+// the data, inside range and with, are not included.
+func requiredKeys(trees ...*parse.Tree) []string {
+	var keys []string
+	add := func(k string) {
+		if !slices.Contains(keys, k) {
+			keys = append(keys, k)
+		}
+	}
+
+	var walk func(n parse.Node, atRoot bool)
+	walk = func(n parse.Node, atRoot bool) {
+		switch n := n.(type) {
+		case *parse.ListNode:
+			if n == nil {
+				return
+			}
+			for _, c := range n.Nodes {
+				walk(c, atRoot)
+			}
+		case *parse.ActionNode:
+			walk(n.Pipe, atRoot)
+		case *parse.PipeNode:
+			if n == nil {
+				return
+			}
+			for _, c := range n.Cmds {
+				walk(c, atRoot)
+			}
+		case *parse.CommandNode:
+			for _, a := range n.Args {
+				walk(a, atRoot)
+			}
+		case *parse.ChainNode:
+			walk(n.Node, atRoot)
+		case *parse.FieldNode:
+			if atRoot {
+				add(n.Ident[0])
+			}
+		case *parse.VariableNode:
+			if n.Ident[0] == "$" && len(n.Ident) > 1 {
+				add(n.Ident[1])
+			}
+		case *parse.IfNode:
+			walk(n.Pipe, atRoot)
+			walk(n.List, atRoot)
+			walk(n.ElseList, atRoot)
+		case *parse.RangeNode:
+			walk(n.Pipe, atRoot)
+			walk(n.List, false)
+			walk(n.ElseList, atRoot)
+		case *parse.WithNode:
+			walk(n.Pipe, atRoot)
+			walk(n.List, false)
+			walk(n.ElseList, atRoot)
+		case *parse.TemplateNode:
+			walk(n.Pipe, atRoot)
+		}
+	}
+	for _, t := range trees {
+		walk(t.Root, true)
+	}
+	return keys
+}
+
+// Has reports whether a template called name was loaded.
+func (s *Store) Has(name string) bool {
+	_, ok := s.templates[name]
+	return ok
+}
+
+// Render renders the named template with data. It returns ErrNotFound for
+// unknown templates and a *MissingVariableError when data lacks a
+// variable the template uses.
+func (s *Store) Render(name string, data map[string]any) (Rendered, error) {
+	t, ok := s.templates[name]
+	if !ok {
+		return Rendered{}, fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+	for _, k := range t.required {
+		if _, ok := data[k]; !ok {
+			return Rendered{}, &MissingVariableError{Template: name, Variable: k}
+		}
+	}
+
+	var (
+		out Rendered
+		err error
+	)
+	if out.Subject, err = execute(name, t.subject, data); err != nil {
+		return Rendered{}, err
+	}
+	if t.text != nil {
+		if out.Text, err = execute(name, t.text, data); err != nil {
+			return Rendered{}, err
+		}
+	}
+	if t.html != nil {
+		if out.HTML, err = execute(name, t.html, data); err != nil {
+			return Rendered{}, err
+		}
+	}
+	return out, nil
+}
+
+type executor interface {
+	Execute(w io.Writer, data any) error
+}
+
+func execute(name string, t executor, data map[string]any) (string, error) {
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("render template %s: %w", name, err)
+	}
+	return buf.String(), nil
+}
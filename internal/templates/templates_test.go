@@ -0,0 +1,106 @@
+package templates
+
+import (
+	"errors"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testStore(t *testing.T) *Store {
+	t.Helper()
+	s, err := Load(fstest.MapFS{
+		"welcome/subject.tmpl":   {Data: []byte("Welcome, {{.name}}")},
+		"welcome/body.txt.tmpl":  {Data: []byte("Hi {{.name}}, your plan is {{.plan}}.")},
+		"welcome/body.html.tmpl": {Data: []byte("<p>Hi {{.name}}</p>")},
+		"reset/subject.tmpl":     {Data: []byte("Reset your password")},
+		"reset/body.txt.tmpl":    {Data: []byte("{{.link}}")},
+		"README.md":              {Data: []byte("not a template")},
+	})
+	require.NoError(t, err)
+	return s
+}
+
+func TestStore_Render(t *testing.T) {
+	s := testStore(t)
+
+	out, err := s.Render("welcome", map[string]any{"name": "<Ann>", "plan": "pro"})
+	require.NoError(t, err)
+	assert.Equal(t, "Welcome, <Ann>", out.Subject)
+	assert.Equal(t, "Hi <Ann>, your plan is pro.", out.Text)
+	assert.Equal(t, "<p>Hi &lt;Ann&gt;</p>", out.HTML)
+
+	out, err = s.Render("reset", map[string]any{"link": "https://x"})
+	require.NoError(t, err)
+	assert.Equal(t, "https://x", out.Text)
+	assert.Empty(t, out.HTML)
+
+	assert.True(t, s.Has("welcome"))
+	assert.False(t, s.Has("README.md"))
+}
+
+func TestStore_Render_Errors(t *testing.T) {
+	s := testStore(t)
+
+	_, err := s.Render("missing", nil)
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	_, err = s.Render("welcome", map[string]any{"name": "Ann"})
+	var missing *MissingVariableError
+	require.True(t, errors.As(err, &missing))
+	assert.Equal(t, "welcome", missing.Template)
+	assert.Equal(t, "plan", missing.Variable)
+
+	_, err = s.Render("reset", nil)
+	require.True(t, errors.As(err, &missing))
+	assert.Equal(t, "link", missing.Variable)
+}
+
+func TestStore_Render_RequiredKeys(t *testing.T) {
+	s, err := Load(fstest.MapFS{
+		"digest/subject.tmpl": {Data: []byte("{{.count}} updates")},
+		"digest/body.txt.tmpl": {Data: []byte(
+			"{{range .items}}{{.title}} {{$.footer}}\n{{end}}{{with .user}}{{.name}}{{end}}",
+		)},
+	})
+	require.NoError(t, err)
+
+	data := map[string]any{
+		"count":  1,
+		"items":  []map[string]any{{"title": "a"}},
+		"footer": "bye",
+		"user":   map[string]any{"name": "Ann"},
+	}
+	out, err := s.Render("digest", data)
+	require.NoError(t, err)
+	assert.Equal(t, "a bye\nAnn", out.Text)
+
+	delete(data, "footer")
+	_, err = s.Render("digest", data)
+	var missing *MissingVariableError
+	require.True(t, errors.As(err, &missing))
+	assert.Equal(t, "footer", missing.Variable)
+}
+
+func TestLoad_InvalidTemplates(t *testing.T) {
+	tests := []struct {
+		name string
+		fsys fstest.MapFS
+	}{
+		{"no subject", fstest.MapFS{"a/body.txt.tmpl": {Data: []byte("x")}}},
+		{"no body", fstest.MapFS{"a/subject.tmpl": {Data: []byte("x")}}},
+		{"parse error", fstest.MapFS{
+			"a/subject.tmpl":  {Data: []byte("{{.x")},
+			"a/body.txt.tmpl": {Data: []byte("x")},
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Load(tt.fsys)
+			assert.Error(t, err)
+		})
+	}
+}
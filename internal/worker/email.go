@@ -0,0 +1,358 @@
+package worker
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/joshu-sajeev/goqueue/internal/dto"
+	"github.com/joshu-sajeev/goqueue/internal/templates"
+)
+
+// SMTPMailer delivers email jobs through an SMTP server. The connection is
+// upgraded with STARTTLS when StartTLS is set, and PLAIN authentication is
+// used when Username is set.
+type SMTPMailer struct {
+	Host      string
+	Port      int
+	Username  string
+	Password  string
+	From      string
+	StartTLS  bool
+	TLSConfig *tls.Config
+	// Templates renders emails that name a template instead of carrying
+	// their own subject and body.
+	Templates *templates.Store
+}
+
+// Send delivers the email to its To, CC and BCC recipients. 5xx replies
+// from the server fail the job permanently; connection errors and 4xx
+// replies are retried. The generated Message-ID and the queue ID reported
+// by the server are returned as the result.
+func (m *SMTPMailer) Send(ctx context.Context, email dto.SendEmailPayload) (dto.SendEmailResult, error) {
+	email, err := renderTemplate(m.Templates, email)
+	if err != nil {
+		return dto.SendEmailResult{}, err
+	}
+
+	messageID := newMessageID(m.From)
+	msg, err := buildMessage(m.From, messageID, email)
+	if err != nil {
+		return dto.SendEmailResult{}, Permanent(fmt.Errorf("build email: %w", err))
+	}
+
+	addr := net.JoinHostPort(m.Host, strconv.Itoa(m.Port))
+	conn, err := (&net.Dialer{}).DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return dto.SendEmailResult{}, fmt.Errorf("connect to %s: %w", addr, err)
+	}
+	// net/smtp has no context support, so abort the conversation by closing
+	// the connection when ctx is done.
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	c, err := smtp.NewClient(conn, m.Host)
+	if err != nil {
+		conn.Close()
+		return dto.SendEmailResult{}, smtpError("greeting", err)
+	}
+	defer c.Close()
+
+	if m.StartTLS {
+		tlsConfig := m.TLSConfig
+		if tlsConfig == nil {
+			tlsConfig = &tls.Config{ServerName: m.Host}
+		}
+		if err := c.StartTLS(tlsConfig); err != nil {
+			return dto.SendEmailResult{}, smtpError("starttls", err)
+		}
+	}
+	if m.Username != "" {
+		if err := c.Auth(smtp.PlainAuth("", m.Username, m.Password, m.Host)); err != nil {
+			return dto.SendEmailResult{}, smtpError("auth", err)
+		}
+	}
+
+	if err := c.Mail(envelopeAddress(m.From)); err != nil {
+		return dto.SendEmailResult{}, smtpError("mail from", err)
+	}
+	for _, rcpt := range recipients(email) {
+		if err := c.Rcpt(rcpt); err != nil {
+			return dto.SendEmailResult{}, smtpError("rcpt to "+rcpt, err)
+		}
+	}
+
+	reply, err := sendData(c.Text, msg)
+	if err != nil {
+		return dto.SendEmailResult{}, smtpError("data", err)
+	}
+	c.Quit()
+
+	log.Printf("📧 Sent email to %s: %s", email.To, email.Subject)
+
+	return dto.SendEmailResult{
+		To:        email.To,
+		Subject:   email.Subject,
+		SentAt:    time.Now().Format(time.RFC3339),
+		MessageID: messageID,
+		ServerID:  parseQueueID(reply),
+	}, nil
+}
+
+// renderTemplate fills in the subject and bodies of an email that names a
+// template. Template errors are permanent since retrying cannot fix them.
+func renderTemplate(store *templates.Store, email dto.SendEmailPayload) (dto.SendEmailPayload, error) {
+	if email.Template == "" {
+		return email, nil
+	}
+	if store == nil {
+		return email, Permanent(fmt.Errorf("email template %s: templates are not configured", email.Template))
+	}
+
+	out, err := store.Render(email.Template, email.Data)
+	if err != nil {
+		return email, Permanent(err)
+	}
+	email.Subject, email.Body, email.HTML = out.Subject, out.Text, out.HTML
+	return email, nil
+}
+
+// sendData runs the DATA command and returns the server's final reply.
+// smtp.Client.Data discards that reply, and with it the queue ID.
+func sendData(text *textproto.Conn, msg []byte) (string, error) {
+	id, err := text.Cmd("DATA")
+	if err != nil {
+		return "", err
+	}
+	text.StartResponse(id)
+	_, _, err = text.ReadResponse(354)
+	text.EndResponse(id)
+	if err != nil {
+		return "", err
+	}
+
+	w := text.DotWriter()
+	if _, err := w.Write(msg); err != nil {
+		return "", err
+	}
+	if err := w.Close(); err != nil {
+		return "", err
+	}
+	_, reply, err := text.ReadResponse(250)
+	return reply, err
+}
+
+// parseQueueID extracts the queue ID from replies such as
+// "2.0.0 Ok: queued as 4Xb9kT1z". Replies in other formats are returned
+// unchanged.
+func parseQueueID(reply string) string {
+	if _, id, ok := strings.Cut(reply, "queued as "); ok {
+		if fields := strings.Fields(id); len(fields) > 0 {
+			return fields[0]
+		}
+	}
+	return reply
+}
+
+// smtpError wraps err with the SMTP stage it happened in. Errors with a
+// 5xx reply code are permanent.
+func smtpError(stage string, err error) error {
+	err = fmt.Errorf("smtp %s: %w", stage, err)
+	var tpErr *textproto.Error
+	if errors.As(err, &tpErr) && tpErr.Code >= 500 {
+		return Permanent(err)
+	}
+	return err
+}
+
+func recipients(email dto.SendEmailPayload) []string {
+	rcpts := []string{email.To}
+	rcpts = append(rcpts, email.CC...)
+	return append(rcpts, email.BCC...)
+}
+
+// envelopeAddress returns the bare address of a From header value such as
+// "GoQueue <noreply@example.com>".
+func envelopeAddress(from string) string {
+	if addr, err := mail.ParseAddress(from); err == nil {
+		return addr.Address
+	}
+	return from
+}
+
+func newMessageID(from string) string {
+	domain := "localhost"
+	if _, d, ok := strings.Cut(envelopeAddress(from), "@"); ok {
+		domain = d
+	}
+	b := make([]byte, 12)
+	rand.Read(b)
+	return fmt.Sprintf("<%s@%s>", hex.EncodeToString(b), domain)
+}
+
+// buildMessage renders the email as a MIME message. Text and HTML bodies
+// are sent as multipart/alternative, and attachments wrap the body in
+// multipart/mixed. BCC recipients are never written to the headers.
+func buildMessage(from, messageID string, email dto.SendEmailPayload) ([]byte, error) {
+	var buf bytes.Buffer
+	header := func(k, v string) { fmt.Fprintf(&buf, "%s: %s\r\n", k, v) }
+
+	header("From", from)
+	header("To", email.To)
+	if len(email.CC) > 0 {
+		header("Cc", strings.Join(email.CC, ", "))
+	}
+	if email.ReplyTo != "" {
+		header("Reply-To", email.ReplyTo)
+	}
+	header("Subject", mime.QEncoding.Encode("utf-8", email.Subject))
+	header("Date", time.Now().Format(time.RFC1123Z))
+	header("Message-ID", messageID)
+	header("MIME-Version", "1.0")
+
+	body, err := renderBody(email)
+	if err != nil {
+		return nil, err
+	}
+	if len(email.Attachments) == 0 {
+		for k, v := range body.header {
+			header(k, v[0])
+		}
+		buf.WriteString("\r\n")
+		buf.Write(body.content)
+		return buf.Bytes(), nil
+	}
+
+	mw := multipart.NewWriter(&buf)
+	header("Content-Type", mime.FormatMediaType("multipart/mixed", map[string]string{"boundary": mw.Boundary()}))
+	buf.WriteString("\r\n")
+
+	part, err := mw.CreatePart(body.header)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := part.Write(body.content); err != nil {
+		return nil, err
+	}
+
+	for _, a := range email.Attachments {
+		content, err := base64.StdEncoding.DecodeString(a.Content)
+		if err != nil {
+			return nil, fmt.Errorf("attachment %s: %w", a.Filename, err)
+		}
+		contentType := a.ContentType
+		if contentType == "" {
+			contentType = "application/octet-stream"
+		}
+		part, err := mw.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {contentType},
+			"Content-Transfer-Encoding": {"base64"},
+			"Content-Disposition":       {mime.FormatMediaType("attachment", map[string]string{"filename": a.Filename})},
+		})
+		if err != nil {
+			return nil, err
+		}
+		if err := writeBase64(part, content); err != nil {
+			return nil, err
+		}
+	}
+	if err := mw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+type mimePart struct {
+	header  textproto.MIMEHeader
+	content []byte
+}
+
+// renderBody renders the message body: a single text or HTML part, or
+// both as multipart/alternative.
+func renderBody(email dto.SendEmailPayload) (mimePart, error) {
+	if email.Body == "" || email.HTML == "" {
+		contentType, content := "text/plain; charset=utf-8", email.Body
+		if email.HTML != "" {
+			contentType, content = "text/html; charset=utf-8", email.HTML
+		}
+		var buf bytes.Buffer
+		if err := writeQuotedPrintable(&buf, content); err != nil {
+			return mimePart{}, err
+		}
+		return mimePart{
+			header: textproto.MIMEHeader{
+				"Content-Type":              {contentType},
+				"Content-Transfer-Encoding": {"quoted-printable"},
+			},
+			content: buf.Bytes(),
+		}, nil
+	}
+
+	var buf bytes.Buffer
+	mw := multipart.NewWriter(&buf)
+	for _, p := range []struct{ contentType, content string }{
+		{"text/plain; charset=utf-8", email.Body},
+		{"text/html; charset=utf-8", email.HTML},
+	} {
+		part, err := mw.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {p.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return mimePart{}, err
+		}
+		if err := writeQuotedPrintable(part, p.content); err != nil {
+			return mimePart{}, err
+		}
+	}
+	if err := mw.Close(); err != nil {
+		return mimePart{}, err
+	}
+	return mimePart{
+		header: textproto.MIMEHeader{
+			"Content-Type": {mime.FormatMediaType("multipart/alternative", map[string]string{"boundary": mw.Boundary()})},
+		},
+		content: buf.Bytes(),
+	}, nil
+}
+
+func writeQuotedPrintable(w io.Writer, s string) error {
+	qp := quotedprintable.NewWriter(w)
+	if _, err := io.WriteString(qp, s); err != nil {
+		return err
+	}
+	return qp.Close()
+}
+
+// writeBase64 writes b base64 encoded in lines of 76 characters.
+func writeBase64(w io.Writer, b []byte) error {
+	encoded := base64.StdEncoding.EncodeToString(b)
+	for len(encoded) > 76 {
+		if _, err := io.WriteString(w, encoded[:76]+"\r\n"); err != nil {
+			return err
+		}
+		encoded = encoded[76:]
+	}
+	_, err := io.WriteString(w, encoded+"\r\n")
+	return err
+}
@@ -0,0 +1,297 @@
+package worker
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/mail"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/joshu-sajeev/goqueue/internal/dto"
+	"github.com/joshu-sajeev/goqueue/internal/templates"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeSMTP is a minimal in-process SMTP server that records the envelope
+// and message of the last mail it accepted.
+type fakeSMTP struct {
+	addr    string
+	from    string
+	rcpts   []string
+	auth    string
+	data    string
+	rcptErr string
+}
+
+func startFakeSMTP(t *testing.T) *fakeSMTP {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { ln.Close() })
+
+	s := &fakeSMTP{addr: ln.Addr().String()}
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			s.serve(conn)
+		}
+	}()
+	return s
+}
+
+func (s *fakeSMTP) serve(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	reply := func(line string) { fmt.Fprintf(conn, "%s\r\n", line) }
+
+	reply("220 fake ESMTP")
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		cmd := strings.TrimRight(line, "\r\n")
+		verb := strings.ToUpper(strings.SplitN(cmd, " ", 2)[0])
+		switch verb {
+		case "EHLO":
+			reply("250-fake")
+			reply("250 AUTH PLAIN")
+		case "AUTH":
+			s.auth = cmd
+			reply("235 2.7.0 Authentication successful")
+		case "MAIL":
+			s.from = cmd
+			reply("250 2.1.0 Ok")
+		case "RCPT":
+			if s.rcptErr != "" {
+				reply(s.rcptErr)
+				continue
+			}
+			s.rcpts = append(s.rcpts, cmd)
+			reply("250 2.1.5 Ok")
+		case "DATA":
+			reply("354 End data with <CR><LF>.<CR><LF>")
+			var data strings.Builder
+			for {
+				l, err := r.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if l == ".\r\n" {
+					break
+				}
+				data.WriteString(strings.TrimPrefix(l, "."))
+			}
+			s.data = data.String()
+			reply("250 2.0.0 Ok: queued as ABC123")
+		case "QUIT":
+			reply("221 2.0.0 Bye")
+			return
+		default:
+			reply("502 5.5.2 Command not recognized")
+		}
+	}
+}
+
+func (s *fakeSMTP) mailer(t *testing.T) *SMTPMailer {
+	host, port, err := net.SplitHostPort(s.addr)
+	require.NoError(t, err)
+	var p int
+	fmt.Sscan(port, &p)
+	return &SMTPMailer{Host: host, Port: p, From: "GoQueue <noreply@example.com>"}
+}
+
+func TestSMTPMailer_Send(t *testing.T) {
+	srv := startFakeSMTP(t)
+	mailer := srv.mailer(t)
+	mailer.Username = "user"
+	mailer.Password = "secret"
+
+	res, err := mailer.Send(context.Background(), dto.SendEmailPayload{
+		To:      "alice@example.com",
+		CC:      []string{"bob@example.com"},
+		BCC:     []string{"audit@example.com"},
+		ReplyTo: "support@example.com",
+		Subject: "Your invoice",
+		Body:    "See attached.",
+		HTML:    "<p>See attached.</p>",
+		Attachments: []dto.EmailAttachment{{
+			Filename:    "invoice.txt",
+			ContentType: "text/plain",
+			Content:     base64.StdEncoding.EncodeToString([]byte("total: 42")),
+		}},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "ABC123", res.ServerID)
+	assert.Equal(t, "alice@example.com", res.To)
+	assert.NotEmpty(t, res.MessageID)
+
+	assert.Equal(t, "AUTH PLAIN "+base64.StdEncoding.EncodeToString([]byte("\x00user\x00secret")), srv.auth)
+	assert.Equal(t, "MAIL FROM:<noreply@example.com>", srv.from)
+	assert.Equal(t, []string{
+		"RCPT TO:<alice@example.com>",
+		"RCPT TO:<bob@example.com>",
+		"RCPT TO:<audit@example.com>",
+	}, srv.rcpts)
+
+	msg, err := mail.ReadMessage(strings.NewReader(srv.data))
+	require.NoError(t, err)
+	assert.Equal(t, "alice@example.com", msg.Header.Get("To"))
+	assert.Equal(t, "bob@example.com", msg.Header.Get("Cc"))
+	assert.Equal(t, "support@example.com", msg.Header.Get("Reply-To"))
+	assert.Equal(t, "Your invoice", msg.Header.Get("Subject"))
+	assert.Equal(t, res.MessageID, msg.Header.Get("Message-ID"))
+	assert.Empty(t, msg.Header.Get("Bcc"))
+	assert.NotContains(t, srv.data, "audit@example.com")
+
+	mediaType, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	require.NoError(t, err)
+	assert.Equal(t, "multipart/mixed", mediaType)
+
+	mr := multipart.NewReader(msg.Body, params["boundary"])
+	body, err := mr.NextPart()
+	require.NoError(t, err)
+	mediaType, params, err = mime.ParseMediaType(body.Header.Get("Content-Type"))
+	require.NoError(t, err)
+	assert.Equal(t, "multipart/alternative", mediaType)
+
+	alt := multipart.NewReader(body, params["boundary"])
+	var contents []string
+	for {
+		p, err := alt.NextPart()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		b, _ := io.ReadAll(p)
+		contents = append(contents, p.Header.Get("Content-Type")+"|"+strings.TrimSpace(string(b)))
+	}
+	assert.Equal(t, []string{
+		"text/plain; charset=utf-8|See attached.",
+		"text/html; charset=utf-8|<p>See attached.</p>",
+	}, contents)
+
+	attachment, err := mr.NextPart()
+	require.NoError(t, err)
+	assert.Equal(t, "invoice.txt", attachment.FileName())
+	raw, _ := io.ReadAll(attachment)
+	decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(raw)))
+	require.NoError(t, err)
+	assert.Equal(t, "total: 42", string(decoded))
+}
+
+func TestSMTPMailer_Send_TextOnly(t *testing.T) {
+	srv := startFakeSMTP(t)
+
+	_, err := srv.mailer(t).Send(context.Background(), dto.SendEmailPayload{
+		To:      "alice@example.com",
+		Subject: "Hi",
+		Body:    "Hello",
+	})
+	require.NoError(t, err)
+	assert.Empty(t, srv.auth)
+
+	msg, err := mail.ReadMessage(strings.NewReader(srv.data))
+	require.NoError(t, err)
+	assert.Equal(t, "text/plain; charset=utf-8", msg.Header.Get("Content-Type"))
+	b, _ := io.ReadAll(msg.Body)
+	assert.Equal(t, "Hello", strings.TrimSpace(string(b)))
+}
+
+func TestSMTPMailer_Send_Template(t *testing.T) {
+	store, err := templates.Load(fstest.MapFS{
+		"welcome/subject.tmpl":   {Data: []byte("Welcome, {{.name}}")},
+		"welcome/body.html.tmpl": {Data: []byte("<p>Hi {{.name}}</p>")},
+	})
+	require.NoError(t, err)
+
+	srv := startFakeSMTP(t)
+	mailer := srv.mailer(t)
+	mailer.Templates = store
+
+	res, err := mailer.Send(context.Background(), dto.SendEmailPayload{
+		To:       "alice@example.com",
+		Template: "welcome",
+		Data:     map[string]any{"name": "<Ann>"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "Welcome, <Ann>", res.Subject)
+
+	msg, err := mail.ReadMessage(strings.NewReader(srv.data))
+	require.NoError(t, err)
+	assert.Equal(t, "text/html; charset=utf-8", msg.Header.Get("Content-Type"))
+	b, _ := io.ReadAll(msg.Body)
+	assert.Equal(t, "<p>Hi &lt;Ann&gt;</p>", strings.TrimSpace(string(b)))
+
+	_, err = mailer.Send(context.Background(), dto.SendEmailPayload{
+		To:       "alice@example.com",
+		Template: "welcome",
+	})
+	assert.True(t, IsPermanent(err))
+}
+
+func TestLogMailer_Send_Template(t *testing.T) {
+	store, err := templates.Load(fstest.MapFS{
+		"welcome/subject.tmpl":  {Data: []byte("Welcome, {{.name}}")},
+		"welcome/body.txt.tmpl": {Data: []byte("Hi {{.name}}")},
+	})
+	require.NoError(t, err)
+
+	email := dto.SendEmailPayload{
+		To:       "alice@example.com",
+		Template: "welcome",
+		Data:     map[string]any{"name": "Ann"},
+	}
+	res, err := (&LogMailer{Templates: store}).Send(context.Background(), email)
+	require.NoError(t, err)
+	assert.Equal(t, "Welcome, Ann", res.Subject)
+
+	// Without templates the email cannot be rendered.
+	_, err = SendEmailHandler(context.Background(), email)
+	require.Error(t, err)
+	assert.True(t, IsPermanent(err))
+	assert.Contains(t, err.Error(), "templates are not configured")
+}
+
+func TestSMTPMailer_Send_Errors(t *testing.T) {
+	tests := []struct {
+		name      string
+		reply     string
+		permanent bool
+	}{
+		{"rejected recipient is permanent", "550 5.1.1 User unknown", true},
+		{"temporary failure is retried", "451 4.3.0 Try again later", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := startFakeSMTP(t)
+			srv.rcptErr = tt.reply
+
+			_, err := srv.mailer(t).Send(context.Background(), dto.SendEmailPayload{
+				To:      "nobody@example.com",
+				Subject: "Hi",
+				Body:    "Hello",
+			})
+			require.Error(t, err)
+			assert.Equal(t, tt.permanent, IsPermanent(err))
+		})
+	}
+}
+
+func TestParseQueueID(t *testing.T) {
+	assert.Equal(t, "4Xb9kT1z", parseQueueID("2.0.0 Ok: queued as 4Xb9kT1z"))
+	assert.Equal(t, "OK id=1abc", parseQueueID("OK id=1abc"))
+}
@@ -0,0 +1,62 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/joshu-sajeev/goqueue/internal/dto"
+	"github.com/joshu-sajeev/goqueue/internal/models"
+)
+
+// JobBuilder validates jobs enqueued by handlers and builds the models to
+// insert. It is implemented by job.JobService.
+type JobBuilder interface {
+	NewJob(dto *dto.JobCreateDTO) (*models.Job, error)
+}
+
+// ErrNoJobContext is returned by Enqueue when ctx does not belong to a job
+// run by a Worker.
+var ErrNoJobContext = errors.New("enqueue called outside of a job handler")
+
+type childJobsKey struct{}
+
+// childJobs collects the jobs a handler enqueues while it runs.
+type childJobs struct {
+	builder JobBuilder
+	mu      sync.Mutex
+	jobs    []*models.Job
+}
+
+func withChildJobs(ctx context.Context, builder JobBuilder) (context.Context, *childJobs) {
+	c := &childJobs{builder: builder}
+	return context.WithValue(ctx, childJobsKey{}, c), c
+}
+
+// Enqueue adds a child job of the job being handled. The job is validated
+// immediately but only inserted, with parent_id set to the running job,
+// in the transaction that marks the running job completed. If the handler
+// fails, its child jobs are discarded, so a retried handler can enqueue
+// them again without creating duplicates.
+func Enqueue(ctx context.Context, job *dto.JobCreateDTO) error {
+	c, ok := ctx.Value(childJobsKey{}).(*childJobs)
+	if !ok || c.builder == nil {
+		return ErrNoJobContext
+	}
+
+	model, err := c.builder.NewJob(job)
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.jobs = append(c.jobs, model)
+	return nil
+}
+
+func (c *childJobs) list() []*models.Job {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.jobs
+}
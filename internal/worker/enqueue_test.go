@@ -0,0 +1,45 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/joshu-sajeev/goqueue/internal/dto"
+	"github.com/joshu-sajeev/goqueue/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/datatypes"
+)
+
+type builderFunc func(*dto.JobCreateDTO) (*models.Job, error)
+
+func (f builderFunc) NewJob(d *dto.JobCreateDTO) (*models.Job, error) { return f(d) }
+
+func TestEnqueue(t *testing.T) {
+	builder := builderFunc(func(d *dto.JobCreateDTO) (*models.Job, error) {
+		if d.Queue == "bad" {
+			return nil, errors.New("invalid queue")
+		}
+		return &models.Job{Queue: d.Queue, Payload: datatypes.JSON(d.Payload)}, nil
+	})
+	ctx, children := withChildJobs(context.Background(), builder)
+
+	require.NoError(t, Enqueue(ctx, &dto.JobCreateDTO{Queue: "email", Payload: json.RawMessage(`{"a":1}`)}))
+	require.NoError(t, Enqueue(ctx, &dto.JobCreateDTO{Queue: "webhooks", Payload: json.RawMessage(`{}`)}))
+	assert.EqualError(t, Enqueue(ctx, &dto.JobCreateDTO{Queue: "bad"}), "invalid queue")
+
+	jobs := children.list()
+	require.Len(t, jobs, 2)
+	assert.Equal(t, "email", jobs[0].Queue)
+	assert.Equal(t, "webhooks", jobs[1].Queue)
+}
+
+func TestEnqueue_OutsideHandler(t *testing.T) {
+	err := Enqueue(context.Background(), &dto.JobCreateDTO{Queue: "email"})
+	assert.ErrorIs(t, err, ErrNoJobContext)
+
+	ctx, _ := withChildJobs(context.Background(), nil)
+	assert.ErrorIs(t, Enqueue(ctx, &dto.JobCreateDTO{Queue: "email"}), ErrNoJobContext)
+}
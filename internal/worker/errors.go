@@ -0,0 +1,81 @@
+package worker
+
+import (
+	"errors"
+	"time"
+)
+
+// permanentError marks a handler error that can never succeed on retry,
+// such as a payload that does not decode.
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// retryAfterError asks for the next attempt to be scheduled after a
+// specific delay instead of the default backoff.
+type retryAfterError struct {
+	err   error
+	delay time.Duration
+}
+
+func (e *retryAfterError) Error() string { return e.err.Error() }
+func (e *retryAfterError) Unwrap() error { return e.err }
+
+// snoozeError reschedules the job without counting the run as an attempt.
+type snoozeError struct {
+	delay time.Duration
+}
+
+func (e *snoozeError) Error() string { return "job snoozed for " + e.delay.String() }
+
+// Permanent wraps err so the job is marked failed immediately instead of
+// being retried. Returns nil if err is nil.
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentError{err: err}
+}
+
+// RetryAfter wraps err so the next attempt runs after d rather than after
+// the default backoff. The attempt still counts towards max retries.
+// Returns nil if err is nil.
+func RetryAfter(err error, d time.Duration) error {
+	if err == nil {
+		return nil
+	}
+	return &retryAfterError{err: err, delay: d}
+}
+
+// Snooze reschedules the job to run again after d without consuming an
+// attempt. Handlers return it when the job is not ready to be processed yet.
+func Snooze(d time.Duration) error {
+	return &snoozeError{delay: d}
+}
+
+// IsPermanent reports whether err was marked with Permanent.
+func IsPermanent(err error) bool {
+	var p *permanentError
+	return errors.As(err, &p)
+}
+
+// retryDelay returns the delay requested with RetryAfter, if any.
+func retryDelay(err error) (time.Duration, bool) {
+	var r *retryAfterError
+	if errors.As(err, &r) {
+		return r.delay, true
+	}
+	return 0, false
+}
+
+// snoozeDelay returns the delay requested with Snooze, if any.
+func snoozeDelay(err error) (time.Duration, bool) {
+	var s *snoozeError
+	if errors.As(err, &s) {
+		return s.delay, true
+	}
+	return 0, false
+}
@@ -0,0 +1,76 @@
+package worker
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestErrorClassification(t *testing.T) {
+	base := errors.New("boom")
+
+	tests := []struct {
+		name          string
+		err           error
+		wantPermanent bool
+		wantRetry     time.Duration
+		wantRetryOK   bool
+		wantSnooze    time.Duration
+		wantSnoozeOK  bool
+	}{
+		{
+			name: "plain error",
+			err:  base,
+		},
+		{
+			name:          "permanent",
+			err:           Permanent(base),
+			wantPermanent: true,
+		},
+		{
+			name:          "wrapped permanent",
+			err:           fmt.Errorf("handler: %w", Permanent(base)),
+			wantPermanent: true,
+		},
+		{
+			name:        "retry after",
+			err:         RetryAfter(base, time.Minute),
+			wantRetry:   time.Minute,
+			wantRetryOK: true,
+		},
+		{
+			name:         "snooze",
+			err:          Snooze(30 * time.Second),
+			wantSnooze:   30 * time.Second,
+			wantSnoozeOK: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.wantPermanent, IsPermanent(tt.err))
+
+			d, ok := retryDelay(tt.err)
+			assert.Equal(t, tt.wantRetryOK, ok)
+			assert.Equal(t, tt.wantRetry, d)
+
+			d, ok = snoozeDelay(tt.err)
+			assert.Equal(t, tt.wantSnoozeOK, ok)
+			assert.Equal(t, tt.wantSnooze, d)
+		})
+	}
+}
+
+func TestErrorClassification_KeepsMessage(t *testing.T) {
+	base := errors.New("unmarshal email payload")
+
+	assert.Equal(t, base.Error(), Permanent(base).Error())
+	assert.Equal(t, base.Error(), RetryAfter(base, time.Second).Error())
+	assert.ErrorIs(t, Permanent(base), base)
+	assert.ErrorIs(t, RetryAfter(base, time.Second), base)
+	assert.Nil(t, Permanent(nil))
+	assert.Nil(t, RetryAfter(nil, time.Second))
+}
@@ -0,0 +1,150 @@
+package worker
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/joshu-sajeev/goqueue/internal/dto"
+)
+
+// Environment variables set for commands run by an exec handler.
+const (
+	JobIDEnv      = "GOQUEUE_JOB_ID"
+	QueueEnv      = "GOQUEUE_QUEUE"
+	AttemptEnv    = "GOQUEUE_ATTEMPT"
+	MaxRetriesEnv = "GOQUEUE_MAX_RETRIES"
+
+	// maxExecOutput caps how much of a command's stdout is stored as the
+	// job result.
+	maxExecOutput = 1 << 20
+)
+
+// ExitTempFail is the sysexits.h EX_TEMPFAIL code. Commands exit with it
+// to ask for the job to be retried.
+const ExitTempFail = 75
+
+// ExecConfig describes the command a queue's jobs are run with.
+type ExecConfig struct {
+	Command string
+	Args    []string
+	Dir     string
+	// Env is added to the worker's environment.
+	Env     []string
+	Timeout time.Duration
+	// RetryExitCodes are exit codes that retry the job. Any other non-zero
+	// exit code fails it permanently. Defaults to ExitTempFail.
+	RetryExitCodes []int
+}
+
+// NewExecHandler returns a Handler that runs cfg.Command for each job with
+// the payload on stdin and the job ID, queue, attempt number and max
+// retries in GOQUEUE_* environment variables. Stdout becomes the job
+// result, stored as JSON when it is valid JSON and as a string otherwise.
+// On failure stderr becomes the job error. The command is killed when the
+// job is cancelled or cfg.Timeout elapses.
+func NewExecHandler(cfg ExecConfig) Handler {
+	retryCodes := cfg.RetryExitCodes
+	if len(retryCodes) == 0 {
+		retryCodes = []int{ExitTempFail}
+	}
+
+	return func(ctx context.Context, job *dto.JobDTO) (any, error) {
+		runCtx := ctx
+		if cfg.Timeout > 0 {
+			var cancel context.CancelFunc
+			runCtx, cancel = context.WithTimeout(ctx, cfg.Timeout)
+			defer cancel()
+		}
+
+		cmd := exec.CommandContext(runCtx, cfg.Command, cfg.Args...)
+		cmd.Dir = cfg.Dir
+		cmd.Env = append(os.Environ(), cfg.Env...)
+		cmd.Env = append(cmd.Env,
+			JobIDEnv+"="+strconv.FormatUint(uint64(job.ID), 10),
+			QueueEnv+"="+job.Queue,
+			AttemptEnv+"="+strconv.Itoa(job.Attempts+1),
+			MaxRetriesEnv+"="+strconv.Itoa(job.MaxRetries),
+		)
+		cmd.Stdin = bytes.NewReader(job.Payload)
+		// Don't wait forever for children that inherited stdout/stderr
+		// after the command itself was killed.
+		cmd.WaitDelay = 5 * time.Second
+
+		stdout := &limitedBuffer{limit: maxExecOutput}
+		stderr := &limitedBuffer{limit: maxResponseBody}
+		cmd.Stdout, cmd.Stderr = stdout, stderr
+
+		err := cmd.Run()
+		switch {
+		case err == nil:
+			return execResult(stdout.Bytes()), nil
+		case ctx.Err() != nil:
+			return nil, ctx.Err()
+		case runCtx.Err() != nil:
+			return nil, fmt.Errorf("%s timed out after %s: %s", cfg.Command, cfg.Timeout, stderr)
+		}
+
+		var exitErr *exec.ExitError
+		if !errors.As(err, &exitErr) {
+			// The command could not be started at all.
+			return nil, Permanent(fmt.Errorf("run %s: %w", cfg.Command, err))
+		}
+
+		code := exitErr.ExitCode()
+		msg := strings.TrimSpace(stderr.String())
+		if msg == "" {
+			msg = exitErr.Error()
+		}
+		err = fmt.Errorf("%s exited with %d: %s", cfg.Command, code, msg)
+		if code == -1 || slices.Contains(retryCodes, code) {
+			// Killed by a signal or asked to be retried.
+			return nil, err
+		}
+		return nil, Permanent(err)
+	}
+}
+
+// execResult stores stdout as-is when it is JSON and as a JSON string
+// otherwise.
+func execResult(out []byte) any {
+	trimmed := bytes.TrimSpace(out)
+	if len(trimmed) > 0 && json.Valid(trimmed) {
+		return json.RawMessage(trimmed)
+	}
+	return string(out)
+}
+
+// limitedBuffer keeps the first limit bytes written to it and discards the
+// rest, so a chatty command cannot exhaust the worker's memory.
+type limitedBuffer struct {
+	buf       bytes.Buffer
+	limit     int
+	truncated bool
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if room := b.limit - b.buf.Len(); room < len(p) {
+		b.truncated = true
+		b.buf.Write(p[:max(room, 0)])
+		return len(p), nil
+	}
+	return b.buf.Write(p)
+}
+
+func (b *limitedBuffer) Bytes() []byte { return b.buf.Bytes() }
+
+func (b *limitedBuffer) String() string {
+	if b.truncated {
+		return b.buf.String() + "...(truncated)"
+	}
+	return b.buf.String()
+}
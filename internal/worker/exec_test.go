@@ -0,0 +1,99 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/joshu-sajeev/goqueue/internal/dto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func shHandler(script string) Handler {
+	return NewExecHandler(ExecConfig{Command: "/bin/sh", Args: []string{"-c", script}})
+}
+
+func TestExecHandler_Success(t *testing.T) {
+	job := &dto.JobDTO{ID: 42, Queue: "reports", Payload: []byte(`{"n":1}`), Attempts: 1, MaxRetries: 3}
+
+	t.Run("json stdout is stored as json", func(t *testing.T) {
+		res, err := shHandler(`cat`)(context.Background(), job)
+		require.NoError(t, err)
+		assert.Equal(t, json.RawMessage(`{"n":1}`), res)
+	})
+
+	t.Run("plain stdout is stored as a string", func(t *testing.T) {
+		res, err := shHandler(`echo "$GOQUEUE_JOB_ID $GOQUEUE_QUEUE $GOQUEUE_ATTEMPT $GOQUEUE_MAX_RETRIES"`)(context.Background(), job)
+		require.NoError(t, err)
+		assert.Equal(t, "42 reports 2 3\n", res)
+	})
+
+	t.Run("extra env", func(t *testing.T) {
+		h := NewExecHandler(ExecConfig{Command: "/bin/sh", Args: []string{"-c", `printf %s "$REGION"`}, Env: []string{"REGION=eu"}})
+		res, err := h(context.Background(), job)
+		require.NoError(t, err)
+		assert.Equal(t, "eu", res)
+	})
+}
+
+func TestExecHandler_ExitCodes(t *testing.T) {
+	job := &dto.JobDTO{ID: 1, Queue: "reports", Payload: []byte(`{}`)}
+
+	tests := []struct {
+		name      string
+		script    string
+		retry     []int
+		permanent bool
+		errMsg    string
+	}{
+		{"temp fail is retried", `echo "db busy" >&2; exit 75`, nil, false, "exited with 75: db busy"},
+		{"other codes are permanent", `echo "bad input" >&2; exit 1`, nil, true, "exited with 1: bad input"},
+		{"custom retry codes", `exit 3`, []int{3}, false, "exited with 3"},
+		{"killed by signal is retried", `kill -9 $$`, nil, false, "exited with -1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewExecHandler(ExecConfig{Command: "/bin/sh", Args: []string{"-c", tt.script}, RetryExitCodes: tt.retry})
+			_, err := h(context.Background(), job)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errMsg)
+			assert.Equal(t, tt.permanent, IsPermanent(err))
+		})
+	}
+}
+
+func TestExecHandler_MissingCommandIsPermanent(t *testing.T) {
+	h := NewExecHandler(ExecConfig{Command: "/nonexistent/command"})
+	_, err := h(context.Background(), &dto.JobDTO{Payload: []byte(`{}`)})
+	require.Error(t, err)
+	assert.True(t, IsPermanent(err))
+}
+
+func TestExecHandler_KilledOnCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+
+	start := time.Now()
+	_, err := shHandler(`exec sleep 10`)(ctx, &dto.JobDTO{Payload: []byte(`{}`)})
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Less(t, time.Since(start), 5*time.Second)
+}
+
+func TestExecHandler_Timeout(t *testing.T) {
+	h := NewExecHandler(ExecConfig{Command: "/bin/sh", Args: []string{"-c", "exec sleep 10"}, Timeout: 50 * time.Millisecond})
+	_, err := h(context.Background(), &dto.JobDTO{Payload: []byte(`{}`)})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "timed out")
+	assert.False(t, IsPermanent(err))
+}
+
+func TestLimitedBuffer(t *testing.T) {
+	b := &limitedBuffer{limit: 4}
+	n, err := b.Write([]byte("abcdef"))
+	require.NoError(t, err)
+	assert.Equal(t, 6, n)
+	assert.Equal(t, "abcd...(truncated)", b.String())
+}
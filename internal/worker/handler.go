@@ -2,88 +2,70 @@ package worker
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"log"
 	"time"
 
 	"github.com/joshu-sajeev/goqueue/internal/dto"
-	"gorm.io/datatypes"
+	"github.com/joshu-sajeev/goqueue/internal/templates"
 )
 
-// SendEmailHandler simulates sending an email
-func SendEmailHandler(ctx context.Context, payload datatypes.JSON) (any, error) {
-	var email dto.SendEmailPayload
-	if err := json.Unmarshal(payload, &email); err != nil {
-		return nil, fmt.Errorf("unmarshal email payload: %w", err)
-	}
+// NewDefaultRegistry returns a registry with the built-in handlers for the
+// email, payment and webhooks queues. The default queue is processed as
+// email.
+func NewDefaultRegistry() *Registry {
+	r := NewRegistry()
+	Register(r, "default", SendEmailHandler)
+	Register(r, "email", SendEmailHandler)
+	Register(r, "payment", ProcessPaymentHandler)
+	Register(r, "webhooks", SendWebhookHandler)
+	return r
+}
 
-	// Simulate email sending delay
-	select {
-	case <-time.After(100 * time.Millisecond):
-	case <-ctx.Done():
-		return nil, ctx.Err()
-	}
+// LogMailer simulates sending emails by logging them. It is used when no
+// SMTP server is configured; see SMTPMailer. Emails that name a template
+// are rendered first, and fail permanently if Templates is not set.
+type LogMailer struct {
+	Templates *templates.Store
+}
 
-	log.Printf("📧 Sent email to %s: %s", email.To, email.Subject)
+var defaultLogMailer = &LogMailer{}
 
-	return map[string]any{
-		"to":         email.To,
-		"subject":    email.Subject,
-		"sent_at":    time.Now().Format(time.RFC3339),
-		"message_id": fmt.Sprintf("msg_%d", time.Now().Unix()),
-	}, nil
+// SendEmailHandler simulates sending an email without templates. Use a
+// LogMailer with Templates to render templated emails.
+func SendEmailHandler(ctx context.Context, email dto.SendEmailPayload) (dto.SendEmailResult, error) {
+	return defaultLogMailer.Send(ctx, email)
 }
 
-// ProcessPaymentHandler simulates payment processing
-func ProcessPaymentHandler(ctx context.Context, payload datatypes.JSON) (any, error) {
-	var payment dto.ProcessPaymentPayload
-	if err := json.Unmarshal(payload, &payment); err != nil {
-		return nil, fmt.Errorf("unmarshal payment payload: %w", err)
+// Send logs the email after a short delay, as if it had been sent.
+func (m *LogMailer) Send(ctx context.Context, email dto.SendEmailPayload) (dto.SendEmailResult, error) {
+	email, err := renderTemplate(m.Templates, email)
+	if err != nil {
+		return dto.SendEmailResult{}, err
 	}
 
-	// Simulate payment gateway delay
+	// Simulate email sending delay
 	select {
-	case <-time.After(200 * time.Millisecond):
+	case <-time.After(100 * time.Millisecond):
 	case <-ctx.Done():
-		return nil, ctx.Err()
+		return dto.SendEmailResult{}, ctx.Err()
 	}
 
-	log.Printf("💳 Processed payment %s: %.2f %s", payment.PaymentID, payment.Amount, payment.Currency)
+	log.Printf("📧 Sent email to %s: %s", email.To, email.Subject)
 
-	return map[string]any{
-		"payment_id":     payment.PaymentID,
-		"status":         "completed",
-		"amount":         payment.Amount,
-		"currency":       payment.Currency,
-		"transaction_id": fmt.Sprintf("txn_%d", time.Now().Unix()),
-		"processed_at":   time.Now().Format(time.RFC3339),
+	return dto.SendEmailResult{
+		To:        email.To,
+		Subject:   email.Subject,
+		SentAt:    time.Now().Format(time.RFC3339),
+		MessageID: fmt.Sprintf("msg_%d", time.Now().Unix()),
 	}, nil
 }
 
-// SendWebhookHandler sends an HTTP webhook
-func SendWebhookHandler(ctx context.Context, payload datatypes.JSON) (any, error) {
-	var webhook dto.SendWebhookPayload
-	if err := json.Unmarshal(payload, &webhook); err != nil {
-		return nil, fmt.Errorf("unmarshal webhook payload: %w", err)
-	}
+var defaultPaymentProcessor = &PaymentProcessor{}
 
-	// Simulate network delay
-	delay := time.Duration(webhook.Timeout) * time.Millisecond
-	log.Printf("🔔 Simulating webhook to %s with delay %v ms", webhook.URL, delay)
-	select {
-	case <-time.After(delay):
-		// Simulated successful response
-	case <-ctx.Done():
-		return nil, fmt.Errorf("webhook cancelled or timeout: %w", ctx.Err())
-	}
-
-	// Return a fake response
-	return map[string]any{
-		"url":          webhook.URL,
-		"method":       webhook.Method,
-		"status_code":  200,
-		"response":     fmt.Sprintf("Simulated payload: %s", string(webhook.Body)),
-		"delivered_at": time.Now().Format(time.RFC3339),
-	}, nil
+// ProcessPaymentHandler processes payments with a PaymentProcessor that
+// has no provider, so every payment job fails permanently. Register a
+// PaymentProcessor with a Provider and Ledger to charge payments.
+func ProcessPaymentHandler(ctx context.Context, payment dto.ProcessPaymentPayload) (dto.ProcessPaymentResult, error) {
+	return defaultPaymentProcessor.Process(ctx, payment)
 }
@@ -0,0 +1,74 @@
+package worker
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/joshu-sajeev/goqueue/internal/dto"
+)
+
+// Handler executes a single job and returns the value stored as its result.
+type Handler func(ctx context.Context, job *dto.JobDTO) (any, error)
+
+// Middleware wraps a Handler with cross-cutting behaviour such as logging,
+// metrics, tracing or adding values to the context.
+type Middleware func(next Handler) Handler
+
+// Chain wraps h with mws. The first middleware is the outermost, so it sees
+// the job first and the result last.
+func Chain(h Handler, mws ...Middleware) Handler {
+	for i := len(mws) - 1; i >= 0; i-- {
+		h = mws[i](h)
+	}
+	return h
+}
+
+// Recovery converts a panic in the wrapped handler into a *PanicError so a
+// single bad job cannot take down the worker process. Workers always run
+// it as the outermost middleware; add it again to recover closer to the
+// handler.
+func Recovery() Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, job *dto.JobDTO) (res any, err error) {
+			defer recoverPanic(&err)
+			return next(ctx, job)
+		}
+	}
+}
+
+// Timing measures how long the wrapped handler takes and reports it to
+// observe together with the handler's error.
+func Timing(observe func(job *dto.JobDTO, d time.Duration, err error)) Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, job *dto.JobDTO) (any, error) {
+			start := time.Now()
+			res, err := next(ctx, job)
+			observe(job, time.Since(start), err)
+			return res, err
+		}
+	}
+}
+
+// Logging logs the start and outcome of every job. A nil logger uses the
+// standard logger.
+func Logging(logger *log.Logger) Middleware {
+	if logger == nil {
+		logger = log.Default()
+	}
+	return func(next Handler) Handler {
+		return func(ctx context.Context, job *dto.JobDTO) (any, error) {
+			logger.Printf("job %d (%s) started, attempt %d", job.ID, job.Queue, job.Attempts+1)
+			start := time.Now()
+
+			res, err := next(ctx, job)
+			if err != nil {
+				logger.Printf("job %d (%s) failed after %s: %v", job.ID, job.Queue, time.Since(start), err)
+				return res, err
+			}
+
+			logger.Printf("job %d (%s) completed in %s", job.ID, job.Queue, time.Since(start))
+			return res, nil
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package worker
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/joshu-sajeev/goqueue/internal/dto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChain_Order(t *testing.T) {
+	var calls []string
+	trace := func(name string) Middleware {
+		return func(next Handler) Handler {
+			return func(ctx context.Context, job *dto.JobDTO) (any, error) {
+				calls = append(calls, name+":before")
+				res, err := next(ctx, job)
+				calls = append(calls, name+":after")
+				return res, err
+			}
+		}
+	}
+
+	h := Chain(func(ctx context.Context, job *dto.JobDTO) (any, error) {
+		calls = append(calls, "handler")
+		return "ok", nil
+	}, trace("a"), trace("b"))
+
+	res, err := h(context.Background(), &dto.JobDTO{ID: 1})
+	require.NoError(t, err)
+	assert.Equal(t, "ok", res)
+	assert.Equal(t, []string{"a:before", "b:before", "handler", "b:after", "a:after"}, calls)
+}
+
+func TestRecovery(t *testing.T) {
+	h := Chain(func(ctx context.Context, job *dto.JobDTO) (any, error) {
+		panic("boom")
+	}, Recovery())
+
+	_, err := h(context.Background(), &dto.JobDTO{ID: 1})
+
+	var panicErr *PanicError
+	require.ErrorAs(t, err, &panicErr)
+	assert.Equal(t, "boom", panicErr.Value)
+}
+
+func TestTiming(t *testing.T) {
+	wantErr := errors.New("failed")
+	var (
+		gotJob *dto.JobDTO
+		gotDur time.Duration
+		gotErr error
+	)
+
+	h := Chain(func(ctx context.Context, job *dto.JobDTO) (any, error) {
+		time.Sleep(5 * time.Millisecond)
+		return nil, wantErr
+	}, Timing(func(job *dto.JobDTO, d time.Duration, err error) {
+		gotJob, gotDur, gotErr = job, d, err
+	}))
+
+	job := &dto.JobDTO{ID: 7}
+	_, err := h(context.Background(), job)
+
+	assert.ErrorIs(t, err, wantErr)
+	assert.Same(t, job, gotJob)
+	assert.GreaterOrEqual(t, gotDur, 5*time.Millisecond)
+	assert.ErrorIs(t, gotErr, wantErr)
+}
+
+func TestLogging(t *testing.T) {
+	var buf bytes.Buffer
+	logger := log.New(&buf, "", 0)
+
+	ok := Chain(func(ctx context.Context, job *dto.JobDTO) (any, error) {
+		return nil, nil
+	}, Logging(logger))
+	failing := Chain(func(ctx context.Context, job *dto.JobDTO) (any, error) {
+		return nil, errors.New("smtp unavailable")
+	}, Logging(logger))
+
+	_, _ = ok(context.Background(), &dto.JobDTO{ID: 1, Queue: "email"})
+	_, _ = failing(context.Background(), &dto.JobDTO{ID: 2, Queue: "email", Attempts: 1})
+
+	out := buf.String()
+	assert.Contains(t, out, "job 1 (email) started, attempt 1")
+	assert.Contains(t, out, "job 1 (email) completed in")
+	assert.Contains(t, out, "job 2 (email) started, attempt 2")
+	assert.Contains(t, out, "job 2 (email) failed after")
+	assert.Contains(t, out, "smtp unavailable")
+}
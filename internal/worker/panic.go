@@ -0,0 +1,31 @@
+package worker
+
+import (
+	"fmt"
+	"runtime/debug"
+)
+
+// PanicError is returned in place of a handler result when the handler
+// panicked. It keeps the recovered value and the goroutine stack so the
+// failure can be stored on the job and debugged later.
+type PanicError struct {
+	Value any
+	Stack []byte
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("panic: %v", e.Value)
+}
+
+// Detail returns the panic message followed by the stack trace.
+func (e *PanicError) Detail() string {
+	return fmt.Sprintf("%s\n\n%s", e.Error(), e.Stack)
+}
+
+// recoverPanic converts a panic in the current goroutine into a *PanicError
+// assigned to errp. It must be called directly via defer.
+func recoverPanic(errp *error) {
+	if r := recover(); r != nil {
+		*errp = &PanicError{Value: r, Stack: debug.Stack()}
+	}
+}
@@ -0,0 +1,59 @@
+package worker
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecoverPanic(t *testing.T) {
+	tests := []struct {
+		name      string
+		fn        func() (any, error)
+		wantPanic bool
+		wantMsg   string
+	}{
+		{
+			name:    "no panic keeps handler error",
+			fn:      func() (any, error) { return nil, errors.New("boom") },
+			wantMsg: "boom",
+		},
+		{
+			name:      "panic with value",
+			fn:        func() (any, error) { panic("nil map write") },
+			wantPanic: true,
+			wantMsg:   "panic: nil map write",
+		},
+		{
+			name: "runtime panic",
+			fn: func() (any, error) {
+				var m map[string]int
+				m["x"] = 1
+				return nil, nil
+			},
+			wantPanic: true,
+			wantMsg:   "panic: assignment to entry in nil map",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			call := func() (res any, err error) {
+				defer recoverPanic(&err)
+				return tt.fn()
+			}
+
+			_, err := call()
+			require.Error(t, err)
+			assert.Equal(t, tt.wantMsg, err.Error())
+
+			var panicErr *PanicError
+			assert.Equal(t, tt.wantPanic, errors.As(err, &panicErr))
+			if tt.wantPanic {
+				assert.Contains(t, panicErr.Detail(), "goroutine")
+			}
+		})
+	}
+}
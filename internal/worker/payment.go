@@ -0,0 +1,173 @@
+package worker
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/joshu-sajeev/goqueue/internal/dto"
+	"github.com/joshu-sajeev/goqueue/internal/models"
+)
+
+// PaymentProvider charges payments with an external payment gateway.
+// Charge is called with the payment ID as idempotency key; a provider
+// must return the original transaction rather than charge again when it
+// sees the same key twice.
+type PaymentProvider interface {
+	Name() string
+	Charge(ctx context.Context, idempotencyKey string, payment dto.ProcessPaymentPayload) (transactionID string, err error)
+}
+
+// PaymentLedger records every payment by PaymentID. It is implemented by
+// postgres.PaymentLedgerRepository.
+type PaymentLedger interface {
+	Begin(ctx context.Context, txn *models.PaymentTransaction) (*models.PaymentTransaction, bool, error)
+	Complete(ctx context.Context, paymentID, transactionID string) (*models.PaymentTransaction, error)
+	Fail(ctx context.Context, paymentID, errMsg string) (*models.PaymentTransaction, error)
+}
+
+// ErrNoPaymentProvider fails payment jobs processed without a
+// PaymentProvider or PaymentLedger.
+var ErrNoPaymentProvider = errors.New("no payment provider configured")
+
+// PaymentProcessor handles payment jobs. The payment is written to the
+// ledger before the provider is charged, so a retried job finds the
+// completed transaction and returns it instead of charging twice. A job
+// that crashed between charging and recording the result charges again
+// with the same idempotency key, which the provider deduplicates.
+type PaymentProcessor struct {
+	Provider PaymentProvider
+	Ledger   PaymentLedger
+}
+
+// Process charges the payment once. Declines reported as Permanent errors
+// by the provider are recorded in the ledger and fail the job; other
+// provider errors leave the payment pending and the job is retried.
+// Without a Provider and Ledger every payment fails permanently with
+// ErrNoPaymentProvider.
+func (p *PaymentProcessor) Process(ctx context.Context, payment dto.ProcessPaymentPayload) (dto.ProcessPaymentResult, error) {
+	if p.Provider == nil || p.Ledger == nil {
+		return dto.ProcessPaymentResult{}, Permanent(ErrNoPaymentProvider)
+	}
+	if _, ok := models.AmountUnits(payment.Amount); !ok {
+		return dto.ProcessPaymentResult{}, Permanent(fmt.Errorf("payment %s amount %v cannot be stored with %d decimal places", payment.PaymentID, payment.Amount, models.AmountScale))
+	}
+
+	txn, created, err := p.Ledger.Begin(ctx, &models.PaymentTransaction{
+		PaymentID: payment.PaymentID,
+		UserID:    payment.UserID,
+		Amount:    payment.Amount,
+		Currency:  payment.Currency,
+		Method:    payment.Method,
+		Provider:  p.Provider.Name(),
+	})
+	if err != nil {
+		return dto.ProcessPaymentResult{}, err
+	}
+
+	if !created {
+		if !txn.Matches(payment.UserID, payment.Amount, payment.Currency) {
+			return dto.ProcessPaymentResult{}, Permanent(fmt.Errorf("payment %s is already recorded for a different charge", payment.PaymentID))
+		}
+		if txn.Status == models.PaymentStatusCompleted {
+			log.Printf("💳 Payment %s already charged as %s", payment.PaymentID, txn.TransactionID)
+			res := paymentResult(txn)
+			res.Replayed = true
+			return res, nil
+		}
+	}
+
+	transactionID, err := p.Provider.Charge(ctx, payment.PaymentID, payment)
+	if err != nil {
+		if IsPermanent(err) {
+			if _, ferr := p.Ledger.Fail(context.WithoutCancel(ctx), payment.PaymentID, err.Error()); ferr != nil {
+				log.Printf("Failed to record declined payment %s: %v", payment.PaymentID, ferr)
+			}
+		}
+		return dto.ProcessPaymentResult{}, err
+	}
+
+	// Record the charge even if the job is being cancelled; the money has
+	// already moved.
+	txn, err = p.Ledger.Complete(context.WithoutCancel(ctx), payment.PaymentID, transactionID)
+	if err != nil {
+		return dto.ProcessPaymentResult{}, err
+	}
+
+	log.Printf("💳 Processed payment %s: %.2f %s", payment.PaymentID, payment.Amount, payment.Currency)
+	return paymentResult(txn), nil
+}
+
+func paymentResult(txn *models.PaymentTransaction) dto.ProcessPaymentResult {
+	return dto.ProcessPaymentResult{
+		PaymentID:     txn.PaymentID,
+		Status:        txn.Status,
+		Amount:        txn.Amount,
+		Currency:      txn.Currency,
+		TransactionID: txn.TransactionID,
+		ProcessedAt:   txn.UpdatedAt.Format(time.RFC3339),
+	}
+}
+
+// fakePaymentProviderKeys is the number of idempotency keys a
+// FakePaymentProvider remembers; the oldest are forgotten first.
+const fakePaymentProviderKeys = 10000
+
+// FakePaymentProvider is an in-memory PaymentProvider for development and
+// tests. It honours idempotency keys like a real gateway, for the most
+// recent fakePaymentProviderKeys charges. Decline, when set, is consulted
+// before every new charge and its error returned.
+type FakePaymentProvider struct {
+	Decline func(payment dto.ProcessPaymentPayload) error
+
+	mu      sync.Mutex
+	charges map[string]string
+	keys    []string
+}
+
+func (f *FakePaymentProvider) Name() string { return "fake" }
+
+func (f *FakePaymentProvider) Charge(ctx context.Context, idempotencyKey string, payment dto.ProcessPaymentPayload) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if id, ok := f.charges[idempotencyKey]; ok {
+		return id, nil
+	}
+	if f.Decline != nil {
+		if err := f.Decline(payment); err != nil {
+			return "", err
+		}
+	}
+
+	b := make([]byte, 8)
+	rand.Read(b)
+	id := "txn_" + hex.EncodeToString(b)
+	if f.charges == nil {
+		f.charges = map[string]string{}
+	}
+	if len(f.keys) == fakePaymentProviderKeys {
+		delete(f.charges, f.keys[0])
+		f.keys = f.keys[1:]
+	}
+	f.charges[idempotencyKey] = id
+	f.keys = append(f.keys, idempotencyKey)
+	return id, nil
+}
+
+// Charges returns the number of distinct payments charged that are still
+// remembered.
+func (f *FakePaymentProvider) Charges() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.charges)
+}
@@ -0,0 +1,195 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/joshu-sajeev/goqueue/internal/dto"
+	"github.com/joshu-sajeev/goqueue/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryLedger is an in-memory PaymentLedger with the same semantics as
+// the postgres ledger.
+type memoryLedger struct {
+	mu   sync.Mutex
+	txns map[string]*models.PaymentTransaction
+}
+
+func (l *memoryLedger) Begin(ctx context.Context, txn *models.PaymentTransaction) (*models.PaymentTransaction, bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if existing, ok := l.txns[txn.PaymentID]; ok {
+		cp := *existing
+		return &cp, false, nil
+	}
+	if l.txns == nil {
+		l.txns = map[string]*models.PaymentTransaction{}
+	}
+	txn.Status = models.PaymentStatusPending
+	txn.CreatedAt, txn.UpdatedAt = time.Now(), time.Now()
+	cp := *txn
+	l.txns[txn.PaymentID] = &cp
+	return txn, true, nil
+}
+
+func (l *memoryLedger) Complete(ctx context.Context, paymentID, transactionID string) (*models.PaymentTransaction, error) {
+	return l.update(paymentID, func(t *models.PaymentTransaction) {
+		t.Status, t.TransactionID, t.Error = models.PaymentStatusCompleted, transactionID, ""
+	})
+}
+
+func (l *memoryLedger) Fail(ctx context.Context, paymentID, errMsg string) (*models.PaymentTransaction, error) {
+	return l.update(paymentID, func(t *models.PaymentTransaction) {
+		t.Status, t.Error = models.PaymentStatusFailed, errMsg
+	})
+}
+
+func (l *memoryLedger) update(paymentID string, fn func(*models.PaymentTransaction)) (*models.PaymentTransaction, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	t, ok := l.txns[paymentID]
+	if !ok {
+		return nil, errors.New("payment not found")
+	}
+	fn(t)
+	t.UpdatedAt = time.Now()
+	cp := *t
+	return &cp, nil
+}
+
+var testPayment = dto.ProcessPaymentPayload{
+	PaymentID: "pay_1",
+	UserID:    "user_1",
+	Amount:    99.99,
+	Currency:  "USD",
+	Method:    "card",
+}
+
+func TestPaymentProcessor_ChargesOnce(t *testing.T) {
+	provider := &FakePaymentProvider{}
+	p := &PaymentProcessor{Provider: provider, Ledger: &memoryLedger{}}
+
+	first, err := p.Process(context.Background(), testPayment)
+	require.NoError(t, err)
+	assert.Equal(t, models.PaymentStatusCompleted, first.Status)
+	assert.NotEmpty(t, first.TransactionID)
+	assert.False(t, first.Replayed)
+
+	second, err := p.Process(context.Background(), testPayment)
+	require.NoError(t, err)
+	assert.Equal(t, first.TransactionID, second.TransactionID)
+	assert.True(t, second.Replayed)
+	assert.Equal(t, 1, provider.Charges())
+}
+
+func TestPaymentProcessor_DifferentChargeForSamePaymentID(t *testing.T) {
+	p := &PaymentProcessor{Provider: &FakePaymentProvider{}, Ledger: &memoryLedger{}}
+
+	_, err := p.Process(context.Background(), testPayment)
+	require.NoError(t, err)
+
+	changed := testPayment
+	changed.Amount = 5
+	_, err = p.Process(context.Background(), changed)
+	require.Error(t, err)
+	assert.True(t, IsPermanent(err))
+}
+
+func TestPaymentProcessor_AmountFinerThanLedgerScale(t *testing.T) {
+	provider := &FakePaymentProvider{}
+	ledger := &memoryLedger{}
+	p := &PaymentProcessor{Provider: provider, Ledger: ledger}
+
+	payment := testPayment
+	payment.Amount = 99.99001
+	_, err := p.Process(context.Background(), payment)
+	require.Error(t, err)
+	assert.True(t, IsPermanent(err))
+	assert.Empty(t, ledger.txns)
+	assert.Zero(t, provider.Charges())
+}
+
+func TestPaymentProcessor_ProviderErrors(t *testing.T) {
+	t.Run("temporary error leaves payment pending and retries", func(t *testing.T) {
+		calls := 0
+		provider := &FakePaymentProvider{Decline: func(dto.ProcessPaymentPayload) error {
+			calls++
+			if calls == 1 {
+				return errors.New("gateway timeout")
+			}
+			return nil
+		}}
+		ledger := &memoryLedger{}
+		p := &PaymentProcessor{Provider: provider, Ledger: ledger}
+
+		_, err := p.Process(context.Background(), testPayment)
+		require.Error(t, err)
+		assert.False(t, IsPermanent(err))
+		assert.Equal(t, models.PaymentStatusPending, ledger.txns["pay_1"].Status)
+
+		res, err := p.Process(context.Background(), testPayment)
+		require.NoError(t, err)
+		assert.Equal(t, models.PaymentStatusCompleted, res.Status)
+		assert.Equal(t, 1, provider.Charges())
+	})
+
+	t.Run("decline is recorded and permanent", func(t *testing.T) {
+		provider := &FakePaymentProvider{Decline: func(dto.ProcessPaymentPayload) error {
+			return Permanent(errors.New("card declined"))
+		}}
+		ledger := &memoryLedger{}
+		p := &PaymentProcessor{Provider: provider, Ledger: ledger}
+
+		_, err := p.Process(context.Background(), testPayment)
+		require.Error(t, err)
+		assert.True(t, IsPermanent(err))
+		assert.Equal(t, models.PaymentStatusFailed, ledger.txns["pay_1"].Status)
+		assert.Contains(t, ledger.txns["pay_1"].Error, "card declined")
+		assert.Zero(t, provider.Charges())
+	})
+}
+
+func TestFakePaymentProvider_Idempotent(t *testing.T) {
+	f := &FakePaymentProvider{}
+
+	a, err := f.Charge(context.Background(), "key", testPayment)
+	require.NoError(t, err)
+	b, err := f.Charge(context.Background(), "key", testPayment)
+	require.NoError(t, err)
+	c, err := f.Charge(context.Background(), "other", testPayment)
+	require.NoError(t, err)
+
+	assert.Equal(t, a, b)
+	assert.NotEqual(t, a, c)
+	assert.Equal(t, 2, f.Charges())
+}
+
+func TestFakePaymentProvider_ForgetsOldestKeys(t *testing.T) {
+	f := &FakePaymentProvider{}
+
+	first, err := f.Charge(context.Background(), "key-0", testPayment)
+	require.NoError(t, err)
+	for i := 1; i <= fakePaymentProviderKeys; i++ {
+		_, err := f.Charge(context.Background(), fmt.Sprintf("key-%d", i), testPayment)
+		require.NoError(t, err)
+	}
+	assert.Equal(t, fakePaymentProviderKeys, f.Charges())
+
+	again, err := f.Charge(context.Background(), "key-0", testPayment)
+	require.NoError(t, err)
+	assert.NotEqual(t, first, again)
+	assert.Equal(t, fakePaymentProviderKeys, f.Charges())
+}
+
+func TestProcessPaymentHandler_NoProvider(t *testing.T) {
+	_, err := ProcessPaymentHandler(context.Background(), testPayment)
+	require.Error(t, err)
+	assert.True(t, IsPermanent(err))
+	assert.ErrorIs(t, err, ErrNoPaymentProvider)
+}
@@ -0,0 +1,123 @@
+package worker
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/joshu-sajeev/goqueue/internal/dto"
+)
+
+const (
+	JobIDHeader   = "X-GoQueue-Job-ID"
+	QueueHeader   = "X-GoQueue-Queue"
+	AttemptHeader = "X-GoQueue-Attempt"
+
+	// maxResponseBody caps how much of a response body is stored in the
+	// job result.
+	maxResponseBody = 4 << 10
+)
+
+// PushConfig describes the HTTP endpoint a queue's jobs are delivered to.
+type PushConfig struct {
+	URL     string
+	Secret  string
+	Timeout time.Duration
+	Client  *http.Client
+}
+
+// PushResult is stored as the job result after a successful delivery.
+type PushResult struct {
+	StatusCode int    `json:"status_code"`
+	Response   string `json:"response,omitempty"`
+}
+
+// NewPushHandler returns a Handler that POSTs each job's payload to
+// cfg.URL with the job ID, queue and attempt number in headers, signed
+// with cfg.Secret. A 2xx response completes the job, 429 and 5xx are
+// retried (honouring Retry-After), and any other status fails the job
+// permanently.
+func NewPushHandler(cfg PushConfig) Handler {
+	client := cfg.Client
+	if client == nil {
+		client = &http.Client{}
+	}
+	timeout := cfg.Timeout
+	if timeout == 0 {
+		timeout = 30 * time.Second
+	}
+
+	return func(ctx context.Context, job *dto.JobDTO) (any, error) {
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, cfg.URL, bytes.NewReader(job.Payload))
+		if err != nil {
+			return nil, Permanent(fmt.Errorf("build push request: %w", err))
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set(JobIDHeader, strconv.FormatUint(uint64(job.ID), 10))
+		req.Header.Set(QueueHeader, job.Queue)
+		req.Header.Set(AttemptHeader, strconv.Itoa(job.Attempts+1))
+		signRequest(req, cfg.Secret, job.Payload)
+
+		resp, err := client.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("push job to %s: %w", cfg.URL, err)
+		}
+		defer resp.Body.Close()
+
+		body, _ := readLimited(resp.Body)
+		if err := classifyStatus(resp, body); err != nil {
+			return nil, err
+		}
+
+		return PushResult{StatusCode: resp.StatusCode, Response: body}, nil
+	}
+}
+
+// classifyStatus maps an HTTP response to a handler error: nil for 2xx,
+// a retryable error for 429 and 5xx and a permanent error otherwise.
+func classifyStatus(resp *http.Response, body string) error {
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+
+	err := fmt.Errorf("%s returned %d: %s", resp.Request.URL, resp.StatusCode, body)
+	if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500 {
+		if d, ok := parseRetryAfter(resp.Header.Get("Retry-After")); ok {
+			return RetryAfter(err, d)
+		}
+		return err
+	}
+	return Permanent(err)
+}
+
+// parseRetryAfter understands both forms of the Retry-After header:
+// delay-seconds and an HTTP date.
+func parseRetryAfter(v string) (time.Duration, bool) {
+	if v == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(v); err == nil && secs >= 0 {
+		return time.Duration(secs) * time.Second, true
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		return max(time.Until(t), 0), true
+	}
+	return 0, false
+}
+
+// readLimited reads at most maxResponseBody bytes of r, marking the result
+// when it was truncated.
+func readLimited(r io.Reader) (string, error) {
+	b, err := io.ReadAll(io.LimitReader(r, maxResponseBody+1))
+	if len(b) > maxResponseBody {
+		return string(b[:maxResponseBody]) + "...(truncated)", err
+	}
+	return string(b), err
+}
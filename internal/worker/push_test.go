@@ -0,0 +1,105 @@
+package worker
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/joshu-sajeev/goqueue/internal/dto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/datatypes"
+)
+
+func TestPushHandler_DeliversSignedRequest(t *testing.T) {
+	var got *http.Request
+	var gotBody []byte
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r
+		gotBody, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusAccepted)
+		w.Write([]byte(`{"ok":true}`))
+	}))
+	defer srv.Close()
+
+	h := NewPushHandler(PushConfig{URL: srv.URL, Secret: "s3cret"})
+	job := &dto.JobDTO{ID: 42, Queue: "webhooks", Attempts: 2, Payload: datatypes.JSON(`{"a":1}`)}
+
+	res, err := h(context.Background(), job)
+	require.NoError(t, err)
+	assert.Equal(t, PushResult{StatusCode: http.StatusAccepted, Response: `{"ok":true}`}, res)
+
+	assert.Equal(t, http.MethodPost, got.Method)
+	assert.JSONEq(t, `{"a":1}`, string(gotBody))
+	assert.Equal(t, "42", got.Header.Get(JobIDHeader))
+	assert.Equal(t, "webhooks", got.Header.Get(QueueHeader))
+	assert.Equal(t, "3", got.Header.Get(AttemptHeader))
+
+	ts, err := strconv.ParseInt(got.Header.Get(TimestampHeader), 10, 64)
+	require.NoError(t, err)
+	assert.Equal(t, Sign("s3cret", ts, gotBody), got.Header.Get(SignatureHeader))
+}
+
+func TestPushHandler_StatusClassification(t *testing.T) {
+	tests := []struct {
+		name          string
+		status        int
+		retryAfter    string
+		wantErr       bool
+		wantPermanent bool
+		wantDelay     time.Duration
+		wantDelayOK   bool
+	}{
+		{name: "2xx succeeds", status: http.StatusOK},
+		{name: "429 retries with Retry-After", status: http.StatusTooManyRequests, retryAfter: "120", wantErr: true, wantDelay: 2 * time.Minute, wantDelayOK: true},
+		{name: "503 retries with default backoff", status: http.StatusServiceUnavailable, wantErr: true},
+		{name: "400 is permanent", status: http.StatusBadRequest, wantErr: true, wantPermanent: true},
+		{name: "404 is permanent", status: http.StatusNotFound, wantErr: true, wantPermanent: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if tt.retryAfter != "" {
+					w.Header().Set("Retry-After", tt.retryAfter)
+				}
+				w.WriteHeader(tt.status)
+			}))
+			defer srv.Close()
+
+			h := NewPushHandler(PushConfig{URL: srv.URL})
+			_, err := h(context.Background(), &dto.JobDTO{ID: 1, Payload: datatypes.JSON(`{}`)})
+
+			if !tt.wantErr {
+				require.NoError(t, err)
+				return
+			}
+
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), strconv.Itoa(tt.status))
+			assert.Equal(t, tt.wantPermanent, IsPermanent(err))
+
+			d, ok := retryDelay(err)
+			assert.Equal(t, tt.wantDelayOK, ok)
+			assert.Equal(t, tt.wantDelay, d)
+		})
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	d, ok := parseRetryAfter("30")
+	assert.True(t, ok)
+	assert.Equal(t, 30*time.Second, d)
+
+	d, ok = parseRetryAfter(time.Now().Add(time.Minute).UTC().Format(http.TimeFormat))
+	assert.True(t, ok)
+	assert.InDelta(t, time.Minute, d, float64(2*time.Second))
+
+	_, ok = parseRetryAfter("soon")
+	assert.False(t, ok)
+}
@@ -0,0 +1,65 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/joshu-sajeev/goqueue/internal/dto"
+)
+
+var validate = validator.New()
+
+// Registry maps queue names to the handlers that process their jobs.
+type Registry struct {
+	handlers map[string]Handler
+}
+
+func NewRegistry() *Registry {
+	return &Registry{handlers: map[string]Handler{}}
+}
+
+// Handle registers h for jobs in the named queue, replacing any handler
+// registered before.
+func (r *Registry) Handle(name string, h Handler) {
+	r.handlers[name] = h
+}
+
+// Lookup returns the handler registered for the named queue.
+func (r *Registry) Lookup(name string) (Handler, bool) {
+	h, ok := r.handlers[name]
+	return h, ok
+}
+
+// Register adds a typed handler for the named queue. The job payload is
+// decoded into T and validated with the same `validate` struct tags the API
+// uses on enqueue; decode and validation failures are permanent errors.
+// The returned R is stored as the job result.
+func Register[T, R any](r *Registry, name string, fn func(ctx context.Context, payload T) (R, error)) {
+	r.Handle(name, func(ctx context.Context, job *dto.JobDTO) (any, error) {
+		payload, err := decodePayload[T](name, job.Payload)
+		if err != nil {
+			return nil, err
+		}
+		return fn(ctx, payload)
+	})
+}
+
+func decodePayload[T any](name string, raw []byte) (T, error) {
+	var payload T
+	if err := json.Unmarshal(raw, &payload); err != nil {
+		return payload, Permanent(fmt.Errorf("unmarshal %s payload: %w", name, err))
+	}
+
+	if err := validate.Struct(payload); err != nil {
+		// Non-struct payloads such as maps have no tags to validate.
+		var invalid *validator.InvalidValidationError
+		if !errors.As(err, &invalid) {
+			return payload, Permanent(fmt.Errorf("validate %s payload: %w", name, err))
+		}
+	}
+
+	return payload, nil
+}
@@ -0,0 +1,92 @@
+package worker
+
+import (
+	"context"
+	"testing"
+
+	"github.com/joshu-sajeev/goqueue/internal/dto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/datatypes"
+)
+
+func TestRegister(t *testing.T) {
+	type result struct {
+		Greeting string `json:"greeting"`
+	}
+
+	r := NewRegistry()
+	Register(r, "email", func(ctx context.Context, p dto.SendEmailPayload) (result, error) {
+		return result{Greeting: "hello " + p.To}, nil
+	})
+	Register(r, "raw", func(ctx context.Context, p map[string]any) (map[string]any, error) {
+		return p, nil
+	})
+
+	tests := []struct {
+		name          string
+		queue         string
+		payload       string
+		want          any
+		wantErr       string
+		wantPermanent bool
+	}{
+		{
+			name:    "decodes and validates payload",
+			queue:   "email",
+			payload: `{"to":"a@example.com","subject":"Hi","body":"Hello"}`,
+			want:    result{Greeting: "hello a@example.com"},
+		},
+		{
+			name:          "invalid json is permanent",
+			queue:         "email",
+			payload:       `{"to":`,
+			wantErr:       "unmarshal email payload",
+			wantPermanent: true,
+		},
+		{
+			name:          "validation failure is permanent",
+			queue:         "email",
+			payload:       `{"to":"not-an-email","subject":"Hi","body":"Hello"}`,
+			wantErr:       "validate email payload",
+			wantPermanent: true,
+		},
+		{
+			name:    "non-struct payload skips validation",
+			queue:   "raw",
+			payload: `{"k":"v"}`,
+			want:    map[string]any{"k": "v"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, ok := r.Lookup(tt.queue)
+			require.True(t, ok)
+
+			res, err := h(context.Background(), &dto.JobDTO{ID: 1, Queue: tt.queue, Payload: datatypes.JSON(tt.payload)})
+
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				assert.Equal(t, tt.wantPermanent, IsPermanent(err))
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, res)
+		})
+	}
+}
+
+func TestNewDefaultRegistry(t *testing.T) {
+	r := NewDefaultRegistry()
+
+	for _, queue := range []string{"default", "email", "payment", "webhooks"} {
+		_, ok := r.Lookup(queue)
+		assert.True(t, ok, queue)
+	}
+
+	_, ok := r.Lookup("reports")
+	assert.False(t, ok)
+}
@@ -0,0 +1,105 @@
+package worker
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log"
+	"maps"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/joshu-sajeev/goqueue/internal/dto"
+)
+
+// WebhookSender delivers webhook jobs over HTTP. Requests to hosts listed
+// in Secrets are signed with that host's secret using the same
+// X-GoQueue-Signature scheme as push delivery.
+type WebhookSender struct {
+	Client  *http.Client
+	Secrets map[string]string
+}
+
+var defaultWebhookSender = &WebhookSender{}
+
+// SendWebhookHandler sends an HTTP webhook without signing it. Use a
+// WebhookSender with Secrets to sign requests.
+func SendWebhookHandler(ctx context.Context, webhook dto.SendWebhookPayload) (dto.SendWebhookResult, error) {
+	return defaultWebhookSender.Send(ctx, webhook)
+}
+
+// Send delivers the webhook with its method, headers and body, giving up
+// after webhook.Timeout seconds. A 2xx response completes the job, 429 and
+// 5xx are retried (honouring Retry-After), and other statuses fail the job
+// permanently. The status code, response headers and the start of the
+// response body are returned as the result, or in the error of a failed
+// delivery, since failed jobs only keep their error.
+func (s *WebhookSender) Send(ctx context.Context, webhook dto.SendWebhookPayload) (dto.SendWebhookResult, error) {
+	client := s.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(webhook.Timeout)*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, webhook.Method, webhook.URL, bytes.NewReader(webhook.Body))
+	if err != nil {
+		return dto.SendWebhookResult{}, Permanent(fmt.Errorf("build webhook request: %w", err))
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range webhook.Headers {
+		req.Header.Set(k, v)
+	}
+	signRequest(req, s.secretFor(webhook.URL), webhook.Body)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return dto.SendWebhookResult{}, fmt.Errorf("send webhook to %s: %w", webhook.URL, err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := readLimited(resp.Body)
+	if err := classifyStatus(resp, body); err != nil {
+		return dto.SendWebhookResult{}, fmt.Errorf("%w (response headers: %s)", err, formatHeaders(resp.Header))
+	}
+
+	log.Printf("🔔 Delivered webhook to %s: %d", webhook.URL, resp.StatusCode)
+
+	return dto.SendWebhookResult{
+		URL:         webhook.URL,
+		Method:      webhook.Method,
+		StatusCode:  resp.StatusCode,
+		Headers:     resp.Header,
+		Response:    body,
+		DeliveredAt: time.Now().Format(time.RFC3339),
+	}, nil
+}
+
+// secretFor returns the signing secret configured for the URL's host, or
+// an empty string if requests to it are not signed.
+func (s *WebhookSender) secretFor(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	return s.Secrets[u.Host]
+}
+
+// formatHeaders renders h as "Name: value, value; Name: value" in name
+// order, truncated like response bodies.
+func formatHeaders(h http.Header) string {
+	names := slices.Sorted(maps.Keys(h))
+	parts := make([]string, len(names))
+	for i, name := range names {
+		parts[i] = name + ": " + strings.Join(h[name], ", ")
+	}
+	s := strings.Join(parts, "; ")
+	if len(s) > maxResponseBody {
+		return s[:maxResponseBody] + "...(truncated)"
+	}
+	return s
+}
@@ -0,0 +1,107 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/joshu-sajeev/goqueue/internal/dto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookSender_Send(t *testing.T) {
+	var (
+		gotMethod string
+		gotBody   []byte
+		gotHeader http.Header
+	)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotMethod = r.Method
+		gotHeader = r.Header
+		gotBody, _ = io.ReadAll(r.Body)
+		w.Header().Set("X-Request-ID", "req-1")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(strings.Repeat("x", maxResponseBody+10)))
+	}))
+	defer srv.Close()
+
+	u, _ := url.Parse(srv.URL)
+	sender := &WebhookSender{Secrets: map[string]string{u.Host: "whsec"}}
+
+	res, err := sender.Send(context.Background(), dto.SendWebhookPayload{
+		URL:     srv.URL + "/hook",
+		Method:  http.MethodPut,
+		Headers: map[string]string{"X-Tenant": "acme"},
+		Body:    json.RawMessage(`{"event":"paid"}`),
+		Timeout: 5,
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, http.MethodPut, gotMethod)
+	assert.JSONEq(t, `{"event":"paid"}`, string(gotBody))
+	assert.Equal(t, "acme", gotHeader.Get("X-Tenant"))
+	assert.Equal(t, "application/json", gotHeader.Get("Content-Type"))
+
+	ts, err := strconv.ParseInt(gotHeader.Get(TimestampHeader), 10, 64)
+	require.NoError(t, err)
+	assert.Equal(t, Sign("whsec", ts, gotBody), gotHeader.Get(SignatureHeader))
+
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Equal(t, []string{"req-1"}, res.Headers["X-Request-Id"])
+	assert.True(t, strings.HasSuffix(res.Response, "...(truncated)"))
+	assert.Len(t, res.Response, maxResponseBody+len("...(truncated)"))
+}
+
+func TestWebhookSender_Unsigned(t *testing.T) {
+	var gotHeader http.Header
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotHeader = r.Header
+	}))
+	defer srv.Close()
+
+	_, err := SendWebhookHandler(context.Background(), dto.SendWebhookPayload{
+		URL: srv.URL, Method: http.MethodPost, Body: json.RawMessage(`{}`), Timeout: 5,
+	})
+	require.NoError(t, err)
+	assert.Empty(t, gotHeader.Get(SignatureHeader))
+}
+
+func TestWebhookSender_Failures(t *testing.T) {
+	tests := []struct {
+		name          string
+		status        int
+		wantPermanent bool
+	}{
+		{name: "server error is retried", status: http.StatusBadGateway},
+		{name: "rate limit is retried", status: http.StatusTooManyRequests},
+		{name: "client error is permanent", status: http.StatusUnprocessableEntity, wantPermanent: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("X-Request-Id", "req-1")
+				w.WriteHeader(tt.status)
+				w.Write([]byte("try later"))
+			}))
+			defer srv.Close()
+
+			_, err := SendWebhookHandler(context.Background(), dto.SendWebhookPayload{
+				URL: srv.URL, Method: http.MethodPost, Body: json.RawMessage(`{}`), Timeout: 5,
+			})
+			require.Error(t, err)
+			assert.Equal(t, tt.wantPermanent, IsPermanent(err))
+			assert.Contains(t, err.Error(), fmt.Sprintf("returned %d: try later", tt.status))
+			assert.Contains(t, err.Error(), "X-Request-Id: req-1")
+		})
+	}
+}
@@ -3,32 +3,73 @@ package worker
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"log"
+	"sync"
 	"time"
 
+	"github.com/joshu-sajeev/goqueue/internal/config"
 	"github.com/joshu-sajeev/goqueue/internal/dto"
-	"github.com/joshu-sajeev/goqueue/internal/storage/postgres"
+	"github.com/joshu-sajeev/goqueue/internal/job"
+	"github.com/joshu-sajeev/goqueue/internal/models"
 	"gorm.io/datatypes"
 )
 
+// JobStore acquires jobs and records their outcome. It is implemented by
+// postgres.JobRepository.
+type JobStore interface {
+	AcquireNext(ctx context.Context, queue string, workerID uint, lockDuration time.Duration) (*dto.JobDTO, error)
+	CompleteWithChildren(ctx context.Context, id uint, workerID uint, result datatypes.JSON, children []*models.Job) error
+	MarkFailed(ctx context.Context, id uint, workerID uint, errMsg string) error
+	RetryLater(ctx context.Context, id uint, workerID uint, availableAt time.Time, errMsg string) error
+	Snooze(ctx context.Context, id uint, workerID uint, availableAt time.Time) error
+	Release(ctx context.Context, id uint) error
+}
+
 type Worker struct {
 	ID           int
-	jobRepo      *postgres.JobRepository
+	OwnerID      uint
+	jobRepo      JobStore
 	queues       []string
 	lockDuration time.Duration
+	registry     *Registry
+	builder      JobBuilder
+	handler      Handler
 	quit         chan struct{}
 }
 
-func NewWorker(id int, repo *postgres.JobRepository, queues []string, dur time.Duration) *Worker {
-	return &Worker{ID: id, jobRepo: repo, queues: queues, lockDuration: dur, quit: make(chan struct{})}
+// NewWorker creates a worker that runs jobs with the handlers in registry,
+// wrapped by mws in order. Recovery always runs outermost. ownerID is the
+// ID of the registered worker process the worker runs in and is stored in
+// locked_by for every job it acquires. builder validates child jobs that
+// handlers add with Enqueue.
+func NewWorker(id int, ownerID uint, repo JobStore, queues []string, dur time.Duration, registry *Registry, builder JobBuilder, mws ...Middleware) *Worker {
+	w := &Worker{ID: id, OwnerID: ownerID, jobRepo: repo, queues: queues, lockDuration: dur, registry: registry, builder: builder, quit: make(chan struct{})}
+	w.handler = Chain(w.execute, append([]Middleware{Recovery()}, mws...)...)
+	return w
 }
 
-func (w *Worker) Start(ctx context.Context) {
+// Start runs the worker loop in a new goroutine tracked by wg. The loop
+// stops acquiring jobs once Stop is called and exits after the job in
+// flight, if any, has finished. Cancelling ctx aborts the running handler.
+func (w *Worker) Start(ctx context.Context, wg *sync.WaitGroup) {
+	wg.Add(1)
 	go func() {
+		defer wg.Done()
+
 		currentDelay := 1 * time.Second
 		maxDelay := 60 * time.Second
 
 		for {
+			select {
+			case <-w.quit:
+				return
+			case <-ctx.Done():
+				return
+			default:
+			}
+
 			job := w.pullJob(ctx)
 
 			if job != nil {
@@ -51,7 +92,7 @@ func (w *Worker) Start(ctx context.Context) {
 
 func (w *Worker) pullJob(ctx context.Context) *dto.JobDTO {
 	for _, q := range w.queues {
-		job, _ := w.jobRepo.AcquireNext(ctx, q, uint(w.ID), w.lockDuration)
+		job, _ := w.jobRepo.AcquireNext(ctx, q, w.OwnerID, w.lockDuration)
 		if job != nil {
 			return job
 		}
@@ -60,34 +101,91 @@ func (w *Worker) pullJob(ctx context.Context) *dto.JobDTO {
 }
 
 func (w *Worker) process(ctx context.Context, job *dto.JobDTO) {
-	res, err := w.execute(ctx, job)
+	handlerCtx, children := withChildJobs(ctx, w.builder)
+	res, err := w.handler(handlerCtx, job)
+
+	// Bookkeeping must still reach the database when ctx was cancelled
+	// during shutdown.
+	dbCtx := context.WithoutCancel(ctx)
+
+	if err != nil && ctx.Err() != nil {
+		// The pool gave up waiting and aborted the handler. Put the job
+		// back without consuming an attempt so another worker picks it up.
+		log.Printf("Worker %d: releasing unfinished job %d", w.ID, job.ID)
+		w.jobRepo.Release(dbCtx, job.ID)
+		return
+	}
 
 	if err != nil {
-		nextRun := time.Now().Add(10 * time.Second)
-		w.jobRepo.RetryLater(ctx, job.ID, nextRun)
+		w.fail(dbCtx, job, err)
 		return
 	}
 
 	b, _ := json.Marshal(res)
-	w.jobRepo.MarkCompleted(ctx, job.ID, datatypes.JSON(b))
+	if err := w.jobRepo.CompleteWithChildren(dbCtx, job.ID, w.OwnerID, datatypes.JSON(b), children.list()); err != nil {
+		log.Printf("Worker %d: failed to complete job %d: %v", w.ID, job.ID, err)
+		if err := completionError(err); err != nil {
+			w.fail(dbCtx, job, err)
+		}
+	}
 }
 
-func (w *Worker) execute(ctx context.Context, job *dto.JobDTO) (any, error) {
-	queue := job.Queue
-	if queue == "default" {
-		queue = "email"
+// completionError returns the error to fail or retry a job with after
+// CompleteWithChildren returned err. The transaction was rolled back, so
+// the job is still running unless its lease was lost, in which case it is
+// left to whoever holds it now and nil is returned. Children that depend
+// on missing jobs can never be inserted, so that error is permanent.
+func completionError(err error) error {
+	if errors.Is(err, job.ErrLeaseLost) {
+		return nil
+	}
+	err = fmt.Errorf("complete job: %w", err)
+	if errors.Is(err, job.ErrDependencyNotFound) {
+		return Permanent(err)
+	}
+	return err
+}
+
+// fail decides what happens to a job whose handler returned an error:
+// snoozed jobs are rescheduled without using an attempt, permanent errors
+// and exhausted jobs are marked failed, and everything else is retried
+// after the requested or default backoff.
+func (w *Worker) fail(ctx context.Context, job *dto.JobDTO, err error) {
+	if d, ok := snoozeDelay(err); ok {
+		w.jobRepo.Snooze(ctx, job.ID, w.OwnerID, time.Now().Add(d))
+		return
+	}
+
+	errMsg := err.Error()
+	var panicErr *PanicError
+	if errors.As(err, &panicErr) {
+		log.Printf("Worker %d: job %d panicked: %v", w.ID, job.ID, panicErr.Value)
+		errMsg = panicErr.Detail()
 	}
 
-	switch queue {
-	case "email":
-		return SendEmailHandler(ctx, job.Payload)
-	case "payment":
-		return ProcessPaymentHandler(ctx, job.Payload)
-	case "webhooks":
-		return SendWebhookHandler(ctx, job.Payload)
-	default:
-		return nil, fmt.Errorf("unknown queue: %s", job.Queue)
+	if IsPermanent(err) || job.Attempts >= job.MaxRetries {
+		log.Printf("Worker %d: job %d failed permanently: %s", w.ID, job.ID, err)
+		w.jobRepo.MarkFailed(ctx, job.ID, w.OwnerID, errMsg)
+		return
+	}
+
+	delay, ok := retryDelay(err)
+	if !ok {
+		delay = config.RetryBackoff(job.Attempts)
+	}
+	w.jobRepo.RetryLater(ctx, job.ID, w.OwnerID, time.Now().Add(delay), errMsg)
+}
+
+// execute runs the registered handler for the job's queue. It is the
+// innermost Handler of the worker's middleware chain.
+func (w *Worker) execute(ctx context.Context, job *dto.JobDTO) (any, error) {
+	h, ok := w.registry.Lookup(job.Queue)
+	if !ok {
+		return nil, Permanent(fmt.Errorf("unknown queue: %s", job.Queue))
 	}
+	return h(ctx, job)
 }
 
+// Stop tells the worker to stop acquiring new jobs. It does not wait for
+// the job in flight; use the WaitGroup passed to Start for that.
 func (w *Worker) Stop() { close(w.quit) }
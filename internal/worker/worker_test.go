@@ -0,0 +1,205 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/joshu-sajeev/goqueue/internal/dto"
+	"github.com/joshu-sajeev/goqueue/internal/job"
+	"github.com/joshu-sajeev/goqueue/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/datatypes"
+)
+
+// memoryJobStore is an in-memory JobStore that records what happened to
+// each job. It never hands out jobs; tests call Worker.process directly.
+type memoryJobStore struct {
+	mu       sync.Mutex
+	outcomes map[uint]string
+	results  map[uint]datatypes.JSON
+	errors   map[uint]string
+	children map[uint][]*models.Job
+	// completeErr is returned by CompleteWithChildren when set.
+	completeErr error
+}
+
+func (s *memoryJobStore) AcquireNext(ctx context.Context, queue string, workerID uint, lockDuration time.Duration) (*dto.JobDTO, error) {
+	return nil, nil
+}
+
+func (s *memoryJobStore) CompleteWithChildren(ctx context.Context, id uint, workerID uint, result datatypes.JSON, children []*models.Job) error {
+	if s.completeErr != nil {
+		return s.completeErr
+	}
+	s.record(id, "completed", "")
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.results == nil {
+		s.results = map[uint]datatypes.JSON{}
+		s.children = map[uint][]*models.Job{}
+	}
+	s.results[id] = result
+	s.children[id] = children
+	return nil
+}
+
+func (s *memoryJobStore) MarkFailed(ctx context.Context, id uint, workerID uint, errMsg string) error {
+	s.record(id, "failed", errMsg)
+	return nil
+}
+
+func (s *memoryJobStore) RetryLater(ctx context.Context, id uint, workerID uint, availableAt time.Time, errMsg string) error {
+	s.record(id, "retried", errMsg)
+	return nil
+}
+
+func (s *memoryJobStore) Snooze(ctx context.Context, id uint, workerID uint, availableAt time.Time) error {
+	s.record(id, "snoozed", "")
+	return nil
+}
+
+func (s *memoryJobStore) Release(ctx context.Context, id uint) error {
+	s.record(id, "released", "")
+	return nil
+}
+
+func (s *memoryJobStore) record(id uint, outcome, errMsg string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.outcomes == nil {
+		s.outcomes = map[uint]string{}
+		s.errors = map[uint]string{}
+	}
+	s.outcomes[id] = outcome
+	s.errors[id] = errMsg
+}
+
+func (s *memoryJobStore) outcome(id uint) (string, string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.outcomes[id], s.errors[id]
+}
+
+// staticBuilder builds child jobs without validating them.
+type staticBuilder struct{}
+
+func (staticBuilder) NewJob(d *dto.JobCreateDTO) (*models.Job, error) {
+	return &models.Job{Queue: d.Queue, Payload: datatypes.JSON(d.Payload)}, nil
+}
+
+func newTestWorker(store JobStore, h Handler) *Worker {
+	r := NewRegistry()
+	r.Handle("default", h)
+	return NewWorker(1, 7, store, []string{"default"}, time.Minute, r, staticBuilder{})
+}
+
+func TestWorker_Process(t *testing.T) {
+	tests := []struct {
+		name     string
+		attempts int
+		handler  Handler
+		outcome  string
+		errMsg   string
+	}{
+		{
+			name:    "success",
+			handler: func(ctx context.Context, job *dto.JobDTO) (any, error) { return map[string]int{"rows": 2}, nil },
+			outcome: "completed",
+		},
+		{
+			name:    "error is retried",
+			handler: func(ctx context.Context, job *dto.JobDTO) (any, error) { return nil, errors.New("timeout") },
+			outcome: "retried",
+			errMsg:  "timeout",
+		},
+		{
+			name:     "error on the last attempt fails",
+			attempts: 3,
+			handler:  func(ctx context.Context, job *dto.JobDTO) (any, error) { return nil, errors.New("timeout") },
+			outcome:  "failed",
+			errMsg:   "timeout",
+		},
+		{
+			name: "permanent error fails",
+			handler: func(ctx context.Context, job *dto.JobDTO) (any, error) {
+				return nil, Permanent(errors.New("bad input"))
+			},
+			outcome: "failed",
+			errMsg:  "bad input",
+		},
+		{
+			name:    "snooze",
+			handler: func(ctx context.Context, job *dto.JobDTO) (any, error) { return nil, Snooze(time.Minute) },
+			outcome: "snoozed",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &memoryJobStore{}
+			w := newTestWorker(store, tt.handler)
+			w.process(context.Background(), &dto.JobDTO{ID: 1, Queue: "default", Attempts: tt.attempts, MaxRetries: 3})
+
+			outcome, errMsg := store.outcome(1)
+			assert.Equal(t, tt.outcome, outcome)
+			assert.Equal(t, tt.errMsg, errMsg)
+		})
+	}
+}
+
+func TestWorker_Process_InsertsChildren(t *testing.T) {
+	store := &memoryJobStore{}
+	w := newTestWorker(store, func(ctx context.Context, job *dto.JobDTO) (any, error) {
+		return "done", Enqueue(ctx, &dto.JobCreateDTO{Queue: "email", Payload: []byte(`{}`)})
+	})
+	w.process(context.Background(), &dto.JobDTO{ID: 1, Queue: "default", MaxRetries: 3})
+
+	outcome, _ := store.outcome(1)
+	assert.Equal(t, "completed", outcome)
+	assert.JSONEq(t, `"done"`, string(store.results[1]))
+	require.Len(t, store.children[1], 1)
+	assert.Equal(t, "email", store.children[1][0].Queue)
+}
+
+func TestWorker_Process_ReleasesCancelledJob(t *testing.T) {
+	store := &memoryJobStore{}
+	w := newTestWorker(store, func(ctx context.Context, job *dto.JobDTO) (any, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	w.process(ctx, &dto.JobDTO{ID: 1, Queue: "default", MaxRetries: 3})
+
+	outcome, _ := store.outcome(1)
+	assert.Equal(t, "released", outcome)
+}
+
+func TestWorker_Process_CompletionFails(t *testing.T) {
+	tests := []struct {
+		name    string
+		err     error
+		outcome string
+	}{
+		{"insert error is retried", errors.New("connection reset"), "retried"},
+		{"missing dependency fails", fmt.Errorf("create child jobs: %w", &job.DependencyNotFoundError{IDs: []uint{9}}), "failed"},
+		{"lost lease is left alone", fmt.Errorf("mark completed: %w", job.ErrLeaseLost), ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &memoryJobStore{completeErr: tt.err}
+			w := newTestWorker(store, func(ctx context.Context, job *dto.JobDTO) (any, error) { return "done", nil })
+			w.process(context.Background(), &dto.JobDTO{ID: 1, Queue: "default", MaxRetries: 3})
+
+			outcome, errMsg := store.outcome(1)
+			assert.Equal(t, tt.outcome, outcome)
+			if tt.outcome != "" {
+				assert.Contains(t, errMsg, tt.err.Error())
+			}
+		})
+	}
+}
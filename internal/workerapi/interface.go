@@ -0,0 +1,40 @@
+package workerapi
+
+import (
+	"context"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/joshu-sajeev/goqueue/internal/dto"
+	"github.com/joshu-sajeev/goqueue/internal/models"
+)
+
+// WorkerRepoInterface defines the contract for worker registry operations.
+type WorkerRepoInterface interface {
+	Register(ctx context.Context, worker *models.Worker) error
+	Heartbeat(ctx context.Context, id uint) error
+	Deregister(ctx context.Context, id uint) error
+	List(ctx context.Context) ([]models.Worker, error)
+}
+
+// WorkerServiceInterface defines the contract for worker business logic operations.
+type WorkerServiceInterface interface {
+	ListWorkers(ctx context.Context) ([]dto.WorkerResponseDTO, error)
+	RegisterWorker(ctx context.Context, req *dto.WorkerRegisterDTO) (*dto.WorkerResponseDTO, error)
+	Heartbeat(ctx context.Context, id uint) error
+	Lease(ctx context.Context, req *dto.LeaseRequestDTO) (*dto.LeaseResponseDTO, error)
+	Ack(ctx context.Context, jobID uint, req *dto.AckRequestDTO) error
+	Nack(ctx context.Context, jobID uint, req *dto.NackRequestDTO) error
+	Extend(ctx context.Context, jobID uint, req *dto.ExtendRequestDTO) (time.Time, error)
+}
+
+// WorkerHandlerInterface defines the contract for worker HTTP request handlers.
+type WorkerHandlerInterface interface {
+	List(c *gin.Context)
+	Register(c *gin.Context)
+	Heartbeat(c *gin.Context)
+	Lease(c *gin.Context)
+	Ack(c *gin.Context)
+	Nack(c *gin.Context)
+	Extend(c *gin.Context)
+}
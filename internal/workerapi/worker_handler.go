@@ -0,0 +1,156 @@
+package workerapi
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/joshu-sajeev/goqueue/common"
+	"github.com/joshu-sajeev/goqueue/internal/dto"
+	"github.com/joshu-sajeev/goqueue/middleware"
+)
+
+type WorkerHandler struct {
+	service WorkerServiceInterface
+}
+
+func NewWorkerHandler(s WorkerServiceInterface) *WorkerHandler {
+	return &WorkerHandler{service: s}
+}
+
+var _ WorkerHandlerInterface = (*WorkerHandler)(nil)
+
+// List handles HTTP requests to retrieve all registered worker processes
+// and returns them as JSON with HTTP 200.
+func (h *WorkerHandler) List(c *gin.Context) {
+	workers, err := h.service.ListWorkers(c.Request.Context())
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, workers)
+}
+
+// Register handles HTTP requests from remote workers registering themselves.
+// It returns HTTP 201 with the assigned worker ID.
+func (h *WorkerHandler) Register(c *gin.Context) {
+	var req dto.WorkerRegisterDTO
+	if !middleware.Bind(c, &req) {
+		return
+	}
+
+	resp, err := h.service.RegisterWorker(c.Request.Context(), &req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, resp)
+}
+
+// Heartbeat handles HTTP requests from remote workers reporting they are
+// still alive. It returns HTTP 204 on success.
+func (h *WorkerHandler) Heartbeat(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.Heartbeat(c.Request.Context(), id); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// Lease handles HTTP requests from remote workers asking for jobs. It
+// returns HTTP 200 with the leased jobs, which may be empty.
+func (h *WorkerHandler) Lease(c *gin.Context) {
+	var req dto.LeaseRequestDTO
+	if !middleware.Bind(c, &req) {
+		return
+	}
+
+	resp, err := h.service.Lease(c.Request.Context(), &req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// Ack handles HTTP requests completing a leased job with its result.
+// It returns HTTP 204 on success.
+func (h *WorkerHandler) Ack(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	var req dto.AckRequestDTO
+	if !middleware.Bind(c, &req) {
+		return
+	}
+
+	if err := h.service.Ack(c.Request.Context(), id, &req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// Nack handles HTTP requests reporting a failed run of a leased job.
+// It returns HTTP 204 on success.
+func (h *WorkerHandler) Nack(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	var req dto.NackRequestDTO
+	if !middleware.Bind(c, &req) {
+		return
+	}
+
+	if err := h.service.Nack(c.Request.Context(), id, &req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// Extend handles HTTP requests renewing the lease on a job. It returns
+// HTTP 200 with the new lease expiry.
+func (h *WorkerHandler) Extend(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	var req dto.ExtendRequestDTO
+	if !middleware.Bind(c, &req) {
+		return
+	}
+
+	until, err := h.service.Extend(c.Request.Context(), id, &req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"lease_expires_at": until})
+}
+
+func parseID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 0)
+	if err != nil || id < 1 {
+		c.Error(common.Errf(http.StatusBadRequest, "invalid ID"))
+		return 0, false
+	}
+	return uint(id), true
+}
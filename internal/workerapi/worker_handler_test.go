@@ -0,0 +1,206 @@
+package workerapi
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/joshu-sajeev/goqueue/common"
+	"github.com/joshu-sajeev/goqueue/internal/dto"
+	"github.com/joshu-sajeev/goqueue/internal/mocks"
+	"github.com/joshu-sajeev/goqueue/middleware"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestWorkerHandler_List(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		setupMock      func(*mocks.WorkerServiceMock)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "success",
+			setupMock: func(m *mocks.WorkerServiceMock) {
+				m.On("ListWorkers", mock.Anything).Return([]dto.WorkerResponseDTO{
+					{ID: 1, Hostname: "host-a", PID: 10, Queues: []string{"email"}, Version: "dev", Alive: true},
+				}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: `[{"id":1,"hostname":"host-a","pid":10,"queues":["email"],"version":"dev","alive":true,
+				"started_at":"0001-01-01T00:00:00Z","last_heartbeat_at":"0001-01-01T00:00:00Z"}]`,
+		},
+		{
+			name: "service error",
+			setupMock: func(m *mocks.WorkerServiceMock) {
+				m.On("ListWorkers", mock.Anything).
+					Return(nil, common.Errf(http.StatusInternalServerError, "failed to list workers"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   `{"error":"failed to list workers"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(mocks.WorkerServiceMock)
+			tt.setupMock(mockService)
+
+			r := gin.New()
+			r.Use(middleware.TimeoutMiddleware(5*time.Second), middleware.ErrorHandler())
+			handler := NewWorkerHandler(mockService)
+			r.GET("/workers", handler.List)
+
+			req := httptest.NewRequest(http.MethodGet, "/workers", nil)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.JSONEq(t, tt.expectedBody, w.Body.String())
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func newRouter(s WorkerServiceInterface) *gin.Engine {
+	r := gin.New()
+	r.Use(middleware.TimeoutMiddleware(5*time.Second), middleware.ErrorHandler())
+	h := NewWorkerHandler(s)
+
+	r.POST("/workers/register", h.Register)
+	r.POST("/workers/lease", h.Lease)
+	r.POST("/workers/:id/heartbeat", h.Heartbeat)
+	r.POST("/jobs/:id/ack", h.Ack)
+	r.POST("/jobs/:id/nack", h.Nack)
+	r.POST("/jobs/:id/extend", h.Extend)
+	return r
+}
+
+func TestWorkerHandler_RemoteProtocol(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		path           string
+		body           string
+		setupMock      func(*mocks.WorkerServiceMock)
+		expectedStatus int
+	}{
+		{
+			name: "register",
+			path: "/workers/register",
+			body: `{"hostname":"py-worker","pid":42,"queues":["email"]}`,
+			setupMock: func(m *mocks.WorkerServiceMock) {
+				m.On("RegisterWorker", mock.Anything, mock.Anything).Return(&dto.WorkerResponseDTO{ID: 1}, nil)
+			},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:           "register requires queues",
+			path:           "/workers/register",
+			body:           `{"hostname":"py-worker","pid":42}`,
+			setupMock:      func(m *mocks.WorkerServiceMock) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "lease",
+			path: "/workers/lease",
+			body: `{"worker_id":1,"queues":["email"],"batch_size":10}`,
+			setupMock: func(m *mocks.WorkerServiceMock) {
+				m.On("Lease", mock.Anything, mock.MatchedBy(func(req *dto.LeaseRequestDTO) bool {
+					return req.WorkerID == 1 && req.BatchSize == 10
+				})).Return(&dto.LeaseResponseDTO{Jobs: []dto.JobDTO{{ID: 5}}}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "heartbeat",
+			path: "/workers/1/heartbeat",
+			setupMock: func(m *mocks.WorkerServiceMock) {
+				m.On("Heartbeat", mock.Anything, uint(1)).Return(nil)
+			},
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name: "ack",
+			path: "/jobs/5/ack",
+			body: `{"worker_id":1,"result":{"ok":true}}`,
+			setupMock: func(m *mocks.WorkerServiceMock) {
+				m.On("Ack", mock.Anything, uint(5), mock.Anything).Return(nil)
+			},
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name: "ack by another worker",
+			path: "/jobs/5/ack",
+			body: `{"worker_id":2}`,
+			setupMock: func(m *mocks.WorkerServiceMock) {
+				m.On("Ack", mock.Anything, uint(5), mock.Anything).
+					Return(common.Errf(http.StatusConflict, "job is not leased by this worker"))
+			},
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name: "nack",
+			path: "/jobs/5/nack",
+			body: `{"worker_id":1,"error":"timeout","retry_in_seconds":30}`,
+			setupMock: func(m *mocks.WorkerServiceMock) {
+				m.On("Nack", mock.Anything, uint(5), mock.MatchedBy(func(req *dto.NackRequestDTO) bool {
+					return req.RetryInSeconds != nil && *req.RetryInSeconds == 30
+				})).Return(nil)
+			},
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name:           "nack retry too far out",
+			path:           "/jobs/5/nack",
+			body:           `{"worker_id":1,"error":"timeout","retry_in_seconds":864000}`,
+			setupMock:      func(m *mocks.WorkerServiceMock) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "nack requires error",
+			path:           "/jobs/5/nack",
+			body:           `{"worker_id":1}`,
+			setupMock:      func(m *mocks.WorkerServiceMock) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "extend",
+			path: "/jobs/5/extend",
+			body: `{"worker_id":1,"lease_seconds":60}`,
+			setupMock: func(m *mocks.WorkerServiceMock) {
+				m.On("Extend", mock.Anything, uint(5), mock.Anything).Return(time.Now().Add(time.Minute), nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "invalid job ID",
+			path:           "/jobs/abc/ack",
+			body:           `{"worker_id":1}`,
+			setupMock:      func(m *mocks.WorkerServiceMock) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(mocks.WorkerServiceMock)
+			tt.setupMock(mockService)
+
+			req := httptest.NewRequest(http.MethodPost, tt.path, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			newRouter(mockService).ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,274 @@
+package workerapi
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/joshu-sajeev/goqueue/common"
+	"github.com/joshu-sajeev/goqueue/internal/config"
+	"github.com/joshu-sajeev/goqueue/internal/dto"
+	"github.com/joshu-sajeev/goqueue/internal/job"
+	"github.com/joshu-sajeev/goqueue/internal/models"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+const (
+	defaultLeaseDuration = time.Minute
+	defaultBatchSize     = 1
+)
+
+type WorkerService struct {
+	repo    WorkerRepoInterface
+	jobRepo job.JobRepoInterface
+}
+
+func NewWorkerService(repo WorkerRepoInterface, jobRepo job.JobRepoInterface) *WorkerService {
+	return &WorkerService{repo: repo, jobRepo: jobRepo}
+}
+
+var _ WorkerServiceInterface = (*WorkerService)(nil)
+
+// ListWorkers returns every registered worker process along with whether
+// it is still sending heartbeats. It maps repository or context errors to
+// appropriate API errors.
+func (s *WorkerService) ListWorkers(ctx context.Context) ([]dto.WorkerResponseDTO, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, common.Errf(
+			http.StatusRequestTimeout,
+			"request timed out",
+		)
+	}
+
+	workers, err := s.repo.List(ctx)
+	if err != nil {
+		return nil, mapRepoError(err, "failed to list workers")
+	}
+
+	dtos := make([]dto.WorkerResponseDTO, len(workers))
+	for i, w := range workers {
+		dtos[i] = toWorkerResponse(&w)
+	}
+
+	return dtos, nil
+}
+
+// RegisterWorker registers a remote worker process. The returned ID must be
+// sent with every lease, ack, nack and extend request, and kept alive with
+// heartbeats or its leased jobs are reclaimed.
+func (s *WorkerService) RegisterWorker(ctx context.Context, req *dto.WorkerRegisterDTO) (*dto.WorkerResponseDTO, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, common.Errf(http.StatusRequestTimeout, "request timed out")
+	}
+
+	if err := validateQueues(req.Queues); err != nil {
+		return nil, err
+	}
+
+	w := models.Worker{
+		Hostname: req.Hostname,
+		PID:      req.PID,
+		Queues:   datatypes.NewJSONSlice(req.Queues),
+		Version:  req.Version,
+	}
+	if err := s.repo.Register(ctx, &w); err != nil {
+		return nil, mapRepoError(err, "failed to register worker")
+	}
+
+	resp := toWorkerResponse(&w)
+	return &resp, nil
+}
+
+// Heartbeat records that a remote worker is still alive.
+func (s *WorkerService) Heartbeat(ctx context.Context, id uint) error {
+	if err := ctx.Err(); err != nil {
+		return common.Errf(http.StatusRequestTimeout, "request timed out")
+	}
+
+	if err := s.repo.Heartbeat(ctx, id); err != nil {
+		return mapRepoError(err, "failed to record heartbeat")
+	}
+	return nil
+}
+
+// Lease acquires up to BatchSize jobs from the requested queues for a
+// remote worker, locking each for LeaseSeconds. Queues are tried in order.
+// Leasing also counts as a heartbeat.
+func (s *WorkerService) Lease(ctx context.Context, req *dto.LeaseRequestDTO) (*dto.LeaseResponseDTO, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, common.Errf(http.StatusRequestTimeout, "request timed out")
+	}
+
+	if err := validateQueues(req.Queues); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Heartbeat(ctx, req.WorkerID); err != nil {
+		return nil, mapRepoError(err, "failed to record heartbeat")
+	}
+
+	batchSize := req.BatchSize
+	if batchSize == 0 {
+		batchSize = defaultBatchSize
+	}
+	lease := defaultLeaseDuration
+	if req.LeaseSeconds > 0 {
+		lease = time.Duration(req.LeaseSeconds) * time.Second
+	}
+
+	resp := &dto.LeaseResponseDTO{
+		Jobs:           []dto.JobDTO{},
+		LeaseExpiresAt: time.Now().Add(lease),
+	}
+	for _, q := range req.Queues {
+		for len(resp.Jobs) < batchSize {
+			j, err := s.jobRepo.AcquireNext(ctx, q, req.WorkerID, lease)
+			if err != nil {
+				return nil, mapRepoError(err, "failed to lease jobs")
+			}
+			if j == nil {
+				break
+			}
+			resp.Jobs = append(resp.Jobs, *j)
+		}
+	}
+
+	return resp, nil
+}
+
+// Ack marks a job leased by the worker as completed with the given result.
+// It returns a 409 if the worker no longer holds the job.
+func (s *WorkerService) Ack(ctx context.Context, jobID uint, req *dto.AckRequestDTO) error {
+	if _, err := s.leasedJob(ctx, jobID, req.WorkerID); err != nil {
+		return err
+	}
+
+	if err := s.jobRepo.MarkCompleted(ctx, jobID, req.WorkerID, datatypes.JSON(req.Result)); err != nil {
+		return mapRepoError(err, "failed to complete job")
+	}
+	return nil
+}
+
+// Nack records a failed run of a job leased by the worker. The job is
+// marked failed if the worker says the error is permanent or the job has
+// no retries left; otherwise it is retried after RetryInSeconds, or after
+// the default backoff when no hint is given.
+func (s *WorkerService) Nack(ctx context.Context, jobID uint, req *dto.NackRequestDTO) error {
+	j, err := s.leasedJob(ctx, jobID, req.WorkerID)
+	if err != nil {
+		return err
+	}
+
+	if req.Permanent || j.Attempts >= j.MaxRetries {
+		if err := s.jobRepo.MarkFailed(ctx, jobID, req.WorkerID, req.Error); err != nil {
+			return mapRepoError(err, "failed to fail job")
+		}
+		return nil
+	}
+
+	delay := config.RetryBackoff(j.Attempts)
+	if req.RetryInSeconds != nil {
+		delay = time.Duration(*req.RetryInSeconds) * time.Second
+	}
+	if err := s.jobRepo.RetryLater(ctx, jobID, req.WorkerID, time.Now().Add(delay), req.Error); err != nil {
+		return mapRepoError(err, "failed to retry job")
+	}
+	return nil
+}
+
+// Extend renews the lease on a job held by the worker and returns the new
+// expiry. Extending also counts as a heartbeat.
+func (s *WorkerService) Extend(ctx context.Context, jobID uint, req *dto.ExtendRequestDTO) (time.Time, error) {
+	if err := ctx.Err(); err != nil {
+		return time.Time{}, common.Errf(http.StatusRequestTimeout, "request timed out")
+	}
+
+	if err := s.repo.Heartbeat(ctx, req.WorkerID); err != nil {
+		return time.Time{}, mapRepoError(err, "failed to record heartbeat")
+	}
+
+	until := time.Now().Add(time.Duration(req.LeaseSeconds) * time.Second)
+	ok, err := s.jobRepo.ExtendLock(ctx, jobID, req.WorkerID, until)
+	if err != nil {
+		return time.Time{}, mapRepoError(err, "failed to extend lease")
+	}
+	if !ok {
+		return time.Time{}, common.Errf(http.StatusConflict, "job is not leased by this worker")
+	}
+	return until, nil
+}
+
+// leasedJob loads a job and checks that it is running under workerID.
+// This only rejects stale requests early: the janitor can still reclaim the
+// job before the write, so the repository checks ownership again in the
+// UPDATE that finishes the run and returns job.ErrLeaseLost.
+func (s *WorkerService) leasedJob(ctx context.Context, jobID, workerID uint) (*models.Job, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, common.Errf(http.StatusRequestTimeout, "request timed out")
+	}
+
+	j, err := s.jobRepo.Get(ctx, jobID)
+	if err != nil {
+		return nil, mapRepoError(err, "failed to get job")
+	}
+
+	if j.Status != config.JobStatusRunning || j.LockedBy == nil || *j.LockedBy != workerID {
+		return nil, common.Errf(http.StatusConflict, "job is not leased by this worker")
+	}
+	return j, nil
+}
+
+func validateQueues(queues []string) error {
+	for _, q := range queues {
+		if !slices.Contains(config.AllowedQueues, q) {
+			return common.NewAPIError(
+				http.StatusBadRequest,
+				"invalid queue",
+				map[string]any{
+					"provided": q,
+					"allowed":  config.AllowedQueues,
+				},
+			)
+		}
+	}
+	return nil
+}
+
+// mapRepoError converts a repository error into an API error, using msg
+// for unexpected failures.
+func mapRepoError(err error, msg string) error {
+	switch {
+	case errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled):
+		return common.Errf(http.StatusRequestTimeout, "request timed out")
+	case errors.Is(err, job.ErrLeaseLost):
+		return common.Errf(http.StatusConflict, "job is not leased by this worker")
+	case errors.Is(err, gorm.ErrRecordNotFound) || strings.Contains(err.Error(), "not found"):
+		return common.Errf(http.StatusNotFound, "%s", notFoundMessage(err))
+	default:
+		return common.Errf(http.StatusInternalServerError, "%s", msg)
+	}
+}
+
+func notFoundMessage(err error) string {
+	if strings.Contains(err.Error(), "worker not found") {
+		return "worker not found"
+	}
+	return "job not found"
+}
+
+func toWorkerResponse(w *models.Worker) dto.WorkerResponseDTO {
+	return dto.WorkerResponseDTO{
+		ID:              w.ID,
+		Hostname:        w.Hostname,
+		PID:             w.PID,
+		Queues:          w.Queues,
+		Version:         w.Version,
+		Alive:           w.IsAlive(config.WorkerHeartbeatTimeout),
+		StartedAt:       w.StartedAt,
+		LastHeartbeatAt: w.LastHeartbeatAt,
+	}
+}
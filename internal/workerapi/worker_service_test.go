@@ -0,0 +1,321 @@
+package workerapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/joshu-sajeev/goqueue/internal/config"
+	"github.com/joshu-sajeev/goqueue/internal/dto"
+	"github.com/joshu-sajeev/goqueue/internal/job"
+	"github.com/joshu-sajeev/goqueue/internal/mocks"
+	"github.com/joshu-sajeev/goqueue/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+func TestWorkerService_ListWorkers(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name        string
+		setupMock   func(*mocks.WorkerRepoMock)
+		setupCtx    func() context.Context
+		wantErr     bool
+		errContains string
+		wantAlive   []bool
+	}{
+		{
+			name: "marks workers alive by heartbeat",
+			setupMock: func(m *mocks.WorkerRepoMock) {
+				m.On("List", mock.Anything).Return([]models.Worker{
+					{ID: 1, Hostname: "host-a", PID: 10, Queues: []string{"email"}, LastHeartbeatAt: now},
+					{ID: 2, Hostname: "host-b", PID: 20, Queues: []string{"payment"}, LastHeartbeatAt: now.Add(-time.Hour)},
+				}, nil)
+			},
+			setupCtx:  context.Background,
+			wantAlive: []bool{true, false},
+		},
+		{
+			name: "repository error",
+			setupMock: func(m *mocks.WorkerRepoMock) {
+				m.On("List", mock.Anything).Return(nil, errors.New("db down"))
+			},
+			setupCtx:    context.Background,
+			wantErr:     true,
+			errContains: "failed to list workers",
+		},
+		{
+			name: "repository timeout",
+			setupMock: func(m *mocks.WorkerRepoMock) {
+				m.On("List", mock.Anything).Return(nil, context.DeadlineExceeded)
+			},
+			setupCtx:    context.Background,
+			wantErr:     true,
+			errContains: "request timed out",
+		},
+		{
+			name:      "cancelled context",
+			setupMock: func(m *mocks.WorkerRepoMock) {},
+			setupCtx: func() context.Context {
+				ctx, cancel := context.WithCancel(context.Background())
+				cancel()
+				return ctx
+			},
+			wantErr:     true,
+			errContains: "request timed out",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(mocks.WorkerRepoMock)
+			tt.setupMock(repo)
+
+			svc := NewWorkerService(repo, new(mocks.JobRepoMock))
+			workers, err := svc.ListWorkers(tt.setupCtx())
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errContains)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Len(t, workers, len(tt.wantAlive))
+			for i, alive := range tt.wantAlive {
+				assert.Equal(t, alive, workers[i].Alive)
+			}
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestWorkerService_Lease(t *testing.T) {
+	tests := []struct {
+		name        string
+		req         *dto.LeaseRequestDTO
+		setupMock   func(*mocks.WorkerRepoMock, *mocks.JobRepoMock)
+		wantErr     bool
+		errContains string
+		wantJobs    []uint
+	}{
+		{
+			name: "fills batch across queues in order",
+			req:  &dto.LeaseRequestDTO{WorkerID: 7, Queues: []string{"email", "payment"}, BatchSize: 3},
+			setupMock: func(w *mocks.WorkerRepoMock, j *mocks.JobRepoMock) {
+				w.On("Heartbeat", mock.Anything, uint(7)).Return(nil)
+				j.On("AcquireNext", mock.Anything, "email", uint(7), time.Minute).Return(&dto.JobDTO{ID: 1, Queue: "email"}, nil).Once()
+				j.On("AcquireNext", mock.Anything, "email", uint(7), time.Minute).Return(nil, nil).Once()
+				j.On("AcquireNext", mock.Anything, "payment", uint(7), time.Minute).Return(&dto.JobDTO{ID: 2, Queue: "payment"}, nil).Once()
+				j.On("AcquireNext", mock.Anything, "payment", uint(7), time.Minute).Return(nil, nil).Once()
+			},
+			wantJobs: []uint{1, 2},
+		},
+		{
+			name: "stops at batch size",
+			req:  &dto.LeaseRequestDTO{WorkerID: 7, Queues: []string{"email"}, LeaseSeconds: 30},
+			setupMock: func(w *mocks.WorkerRepoMock, j *mocks.JobRepoMock) {
+				w.On("Heartbeat", mock.Anything, uint(7)).Return(nil)
+				j.On("AcquireNext", mock.Anything, "email", uint(7), 30*time.Second).Return(&dto.JobDTO{ID: 1, Queue: "email"}, nil).Once()
+			},
+			wantJobs: []uint{1},
+		},
+		{
+			name:        "invalid queue",
+			req:         &dto.LeaseRequestDTO{WorkerID: 7, Queues: []string{"reports"}},
+			setupMock:   func(w *mocks.WorkerRepoMock, j *mocks.JobRepoMock) {},
+			wantErr:     true,
+			errContains: "invalid queue",
+		},
+		{
+			name: "unknown worker",
+			req:  &dto.LeaseRequestDTO{WorkerID: 99, Queues: []string{"email"}},
+			setupMock: func(w *mocks.WorkerRepoMock, j *mocks.JobRepoMock) {
+				w.On("Heartbeat", mock.Anything, uint(99)).Return(fmt.Errorf("worker not found: %w", gorm.ErrRecordNotFound))
+			},
+			wantErr:     true,
+			errContains: "worker not found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			workerRepo := new(mocks.WorkerRepoMock)
+			jobRepo := new(mocks.JobRepoMock)
+			tt.setupMock(workerRepo, jobRepo)
+
+			svc := NewWorkerService(workerRepo, jobRepo)
+			resp, err := svc.Lease(context.Background(), tt.req)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errContains)
+				return
+			}
+
+			assert.NoError(t, err)
+			var ids []uint
+			for _, j := range resp.Jobs {
+				ids = append(ids, j.ID)
+			}
+			assert.Equal(t, tt.wantJobs, ids)
+			workerRepo.AssertExpectations(t)
+			jobRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestWorkerService_Ack(t *testing.T) {
+	owner := uint(7)
+	running := &models.Job{ID: 1, Status: config.JobStatusRunning, LockedBy: &owner}
+
+	t.Run("completes the job", func(t *testing.T) {
+		jobRepo := new(mocks.JobRepoMock)
+		jobRepo.On("Get", mock.Anything, uint(1)).Return(running, nil)
+		jobRepo.On("MarkCompleted", mock.Anything, uint(1), uint(7), mock.Anything).Return(nil)
+
+		err := NewWorkerService(new(mocks.WorkerRepoMock), jobRepo).Ack(context.Background(), 1, &dto.AckRequestDTO{WorkerID: 7})
+		assert.NoError(t, err)
+		jobRepo.AssertExpectations(t)
+	})
+
+	t.Run("lease lost before the update", func(t *testing.T) {
+		jobRepo := new(mocks.JobRepoMock)
+		jobRepo.On("Get", mock.Anything, uint(1)).Return(running, nil)
+		jobRepo.On("MarkCompleted", mock.Anything, uint(1), uint(7), mock.Anything).
+			Return(fmt.Errorf("mark completed: %w", job.ErrLeaseLost))
+
+		err := NewWorkerService(new(mocks.WorkerRepoMock), jobRepo).Ack(context.Background(), 1, &dto.AckRequestDTO{WorkerID: 7})
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "not leased by this worker")
+	})
+}
+
+func TestWorkerService_Nack(t *testing.T) {
+	retryIn := 90
+
+	running := func(attempts, maxRetries int, owner uint) *models.Job {
+		return &models.Job{ID: 1, Status: config.JobStatusRunning, Attempts: attempts, MaxRetries: maxRetries, LockedBy: &owner}
+	}
+
+	tests := []struct {
+		name        string
+		req         *dto.NackRequestDTO
+		setupMock   func(*mocks.JobRepoMock)
+		wantErr     bool
+		errContains string
+	}{
+		{
+			name: "retries with default backoff",
+			req:  &dto.NackRequestDTO{WorkerID: 7, Error: "timeout"},
+			setupMock: func(m *mocks.JobRepoMock) {
+				m.On("Get", mock.Anything, uint(1)).Return(running(1, 3, 7), nil)
+				m.On("RetryLater", mock.Anything, uint(1), uint(7), mock.MatchedBy(func(at time.Time) bool {
+					return time.Until(at) > 15*time.Second && time.Until(at) <= 20*time.Second
+				}), "timeout").Return(nil)
+			},
+		},
+		{
+			name: "retries after hint",
+			req:  &dto.NackRequestDTO{WorkerID: 7, Error: "rate limited", RetryInSeconds: &retryIn},
+			setupMock: func(m *mocks.JobRepoMock) {
+				m.On("Get", mock.Anything, uint(1)).Return(running(0, 3, 7), nil)
+				m.On("RetryLater", mock.Anything, uint(1), uint(7), mock.MatchedBy(func(at time.Time) bool {
+					return time.Until(at) > 85*time.Second && time.Until(at) <= 90*time.Second
+				}), "rate limited").Return(nil)
+			},
+		},
+		{
+			name: "permanent failure",
+			req:  &dto.NackRequestDTO{WorkerID: 7, Error: "bad payload", Permanent: true},
+			setupMock: func(m *mocks.JobRepoMock) {
+				m.On("Get", mock.Anything, uint(1)).Return(running(0, 3, 7), nil)
+				m.On("MarkFailed", mock.Anything, uint(1), uint(7), "bad payload").Return(nil)
+			},
+		},
+		{
+			name: "out of retries",
+			req:  &dto.NackRequestDTO{WorkerID: 7, Error: "timeout"},
+			setupMock: func(m *mocks.JobRepoMock) {
+				m.On("Get", mock.Anything, uint(1)).Return(running(3, 3, 7), nil)
+				m.On("MarkFailed", mock.Anything, uint(1), uint(7), "timeout").Return(nil)
+			},
+		},
+		{
+			name: "lease lost before the update",
+			req:  &dto.NackRequestDTO{WorkerID: 7, Error: "timeout"},
+			setupMock: func(m *mocks.JobRepoMock) {
+				m.On("Get", mock.Anything, uint(1)).Return(running(1, 3, 7), nil)
+				m.On("RetryLater", mock.Anything, uint(1), uint(7), mock.Anything, "timeout").
+					Return(fmt.Errorf("retry later: %w", job.ErrLeaseLost))
+			},
+			wantErr:     true,
+			errContains: "not leased by this worker",
+		},
+		{
+			name: "job leased by another worker",
+			req:  &dto.NackRequestDTO{WorkerID: 7, Error: "timeout"},
+			setupMock: func(m *mocks.JobRepoMock) {
+				m.On("Get", mock.Anything, uint(1)).Return(running(0, 3, 8), nil)
+			},
+			wantErr:     true,
+			errContains: "not leased by this worker",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			jobRepo := new(mocks.JobRepoMock)
+			tt.setupMock(jobRepo)
+
+			svc := NewWorkerService(new(mocks.WorkerRepoMock), jobRepo)
+			err := svc.Nack(context.Background(), 1, tt.req)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errContains)
+				return
+			}
+
+			assert.NoError(t, err)
+			jobRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestWorkerService_Extend(t *testing.T) {
+	tests := []struct {
+		name        string
+		extended    bool
+		wantErr     bool
+		errContains string
+	}{
+		{name: "extends lease", extended: true},
+		{name: "job not held by worker", extended: false, wantErr: true, errContains: "not leased by this worker"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			workerRepo := new(mocks.WorkerRepoMock)
+			jobRepo := new(mocks.JobRepoMock)
+			workerRepo.On("Heartbeat", mock.Anything, uint(7)).Return(nil)
+			jobRepo.On("ExtendLock", mock.Anything, uint(1), uint(7), mock.Anything).Return(tt.extended, nil)
+
+			svc := NewWorkerService(workerRepo, jobRepo)
+			until, err := svc.Extend(context.Background(), 1, &dto.ExtendRequestDTO{WorkerID: 7, LeaseSeconds: 120})
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errContains)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.WithinDuration(t, time.Now().Add(2*time.Minute), until, time.Second)
+		})
+	}
+}
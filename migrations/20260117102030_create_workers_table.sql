@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE workers (
+    id BIGSERIAL PRIMARY KEY,
+    hostname VARCHAR(255) NOT NULL,
+    pid INT NOT NULL,
+    queues JSONB NOT NULL DEFAULT '[]',
+    version VARCHAR(64) NOT NULL DEFAULT '',
+
+    started_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+    last_heartbeat_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_workers_last_heartbeat_at ON workers(last_heartbeat_at);
+CREATE INDEX IF NOT EXISTS idx_jobs_locked_by ON jobs(locked_by);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_jobs_locked_by;
+DROP TABLE workers;
+-- +goose StatementEnd
@@ -0,0 +1,24 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE payment_ledger (
+    id BIGSERIAL PRIMARY KEY,
+    payment_id VARCHAR(255) NOT NULL UNIQUE,
+    user_id VARCHAR(255) NOT NULL,
+    amount NUMERIC(18, 4) NOT NULL,
+    currency CHAR(3) NOT NULL,
+    method VARCHAR(32) NOT NULL,
+
+    provider VARCHAR(64) NOT NULL,
+    transaction_id VARCHAR(255) NOT NULL DEFAULT '',
+    status VARCHAR(16) NOT NULL DEFAULT 'pending',
+    error TEXT NOT NULL DEFAULT '',
+
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now()
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE payment_ledger;
+-- +goose StatementEnd
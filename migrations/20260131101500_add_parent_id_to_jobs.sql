@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE jobs ADD COLUMN parent_id BIGINT REFERENCES jobs(id) ON DELETE SET NULL;
+CREATE INDEX idx_jobs_parent_id ON jobs(parent_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_jobs_parent_id;
+ALTER TABLE jobs DROP COLUMN parent_id;
+-- +goose StatementEnd
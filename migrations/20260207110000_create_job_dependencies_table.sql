@@ -0,0 +1,20 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE jobs ADD COLUMN pass_results BOOLEAN NOT NULL DEFAULT false;
+ALTER TABLE jobs ADD COLUMN on_dependency_failure VARCHAR(16) NOT NULL DEFAULT 'fail';
+
+CREATE TABLE job_dependencies (
+    job_id BIGINT NOT NULL REFERENCES jobs(id) ON DELETE CASCADE,
+    depends_on_id BIGINT NOT NULL REFERENCES jobs(id) ON DELETE CASCADE,
+    PRIMARY KEY (job_id, depends_on_id)
+);
+
+CREATE INDEX idx_job_dependencies_depends_on_id ON job_dependencies(depends_on_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE job_dependencies;
+ALTER TABLE jobs DROP COLUMN on_dependency_failure;
+ALTER TABLE jobs DROP COLUMN pass_results;
+-- +goose StatementEnd
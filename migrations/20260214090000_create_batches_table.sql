@@ -0,0 +1,24 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE batches (
+    id BIGSERIAL PRIMARY KEY,
+    total INTEGER NOT NULL,
+    pending INTEGER NOT NULL,
+    succeeded INTEGER NOT NULL DEFAULT 0,
+    failed INTEGER NOT NULL DEFAULT 0,
+    on_complete_job_id BIGINT REFERENCES jobs(id) ON DELETE SET NULL,
+    on_failure_job_id BIGINT REFERENCES jobs(id) ON DELETE SET NULL,
+    finished_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now()
+);
+
+ALTER TABLE jobs ADD COLUMN batch_id BIGINT REFERENCES batches(id) ON DELETE SET NULL;
+CREATE INDEX idx_jobs_batch_id ON jobs(batch_id) WHERE batch_id IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE jobs DROP COLUMN batch_id;
+DROP TABLE batches;
+-- +goose StatementEnd
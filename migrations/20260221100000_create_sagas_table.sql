@@ -0,0 +1,34 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE sagas (
+    id BIGSERIAL PRIMARY KEY,
+    status VARCHAR(32) NOT NULL,
+    steps INTEGER NOT NULL,
+    current_step INTEGER NOT NULL,
+    failed_step INTEGER,
+    error TEXT NOT NULL DEFAULT '',
+    finished_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now()
+);
+
+CREATE TABLE saga_steps (
+    saga_id BIGINT NOT NULL REFERENCES sagas(id) ON DELETE CASCADE,
+    position INTEGER NOT NULL,
+    job_id BIGINT NOT NULL REFERENCES jobs(id) ON DELETE CASCADE,
+    compensation_queue VARCHAR(255) NOT NULL DEFAULT '',
+    compensation_payload JSONB,
+    compensation_max_retries INTEGER NOT NULL DEFAULT 0,
+    compensation_job_id BIGINT REFERENCES jobs(id) ON DELETE SET NULL,
+    PRIMARY KEY (saga_id, position)
+);
+
+CREATE INDEX idx_saga_steps_job_id ON saga_steps(job_id);
+CREATE INDEX idx_saga_steps_compensation_job_id ON saga_steps(compensation_job_id) WHERE compensation_job_id IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE saga_steps;
+DROP TABLE sagas;
+-- +goose StatementEnd
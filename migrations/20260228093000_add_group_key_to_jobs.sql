@@ -0,0 +1,12 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE jobs ADD COLUMN group_key VARCHAR(255);
+CREATE INDEX idx_jobs_group_key ON jobs(queue, group_key, id)
+    WHERE group_key IS NOT NULL AND status IN ('queued', 'running');
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_jobs_group_key;
+ALTER TABLE jobs DROP COLUMN group_key;
+-- +goose StatementEnd
@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE jobs ADD COLUMN concurrency_key VARCHAR(255);
+ALTER TABLE jobs ADD COLUMN concurrency_limit INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE jobs ADD CONSTRAINT jobs_concurrency_limit_check
+    CHECK (concurrency_key IS NULL OR concurrency_limit >= 1);
+CREATE INDEX idx_jobs_running_concurrency_key ON jobs(concurrency_key)
+    WHERE concurrency_key IS NOT NULL AND status = 'running';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_jobs_running_concurrency_key;
+ALTER TABLE jobs DROP CONSTRAINT jobs_concurrency_limit_check;
+ALTER TABLE jobs DROP COLUMN concurrency_limit;
+ALTER TABLE jobs DROP COLUMN concurrency_key;
+-- +goose StatementEnd
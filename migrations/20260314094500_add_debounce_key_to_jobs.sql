@@ -0,0 +1,12 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE jobs ADD COLUMN debounce_key VARCHAR(255);
+CREATE INDEX idx_jobs_debounce_key ON jobs(queue, debounce_key, created_at)
+    WHERE debounce_key IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_jobs_debounce_key;
+ALTER TABLE jobs DROP COLUMN debounce_key;
+-- +goose StatementEnd
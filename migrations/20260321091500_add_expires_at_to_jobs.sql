@@ -0,0 +1,12 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE jobs ADD COLUMN expires_at TIMESTAMP WITH TIME ZONE;
+CREATE INDEX idx_jobs_expires_at ON jobs(queue, expires_at)
+    WHERE expires_at IS NOT NULL AND status = 'queued';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_jobs_expires_at;
+ALTER TABLE jobs DROP COLUMN expires_at;
+-- +goose StatementEnd
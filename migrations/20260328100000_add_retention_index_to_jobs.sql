@@ -0,0 +1,10 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE INDEX idx_jobs_retention ON jobs(queue, status, updated_at)
+    WHERE status IN ('completed', 'failed', 'cancelled');
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_jobs_retention;
+-- +goose StatementEnd
@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+-- jobs_archive has the columns of jobs followed by archived_at. Migrations
+-- that add columns to jobs must add them here and to archiveColumns in
+-- internal/storage/postgres/job_archive.go too.
+-- Monthly partitions are created by the archiver as it needs them.
+CREATE TABLE jobs_archive (
+    LIKE jobs,
+    archived_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+    PRIMARY KEY (id, created_at)
+) PARTITION BY RANGE (created_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS jobs_archive;
+-- +goose StatementEnd
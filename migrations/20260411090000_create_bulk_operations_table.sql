@@ -0,0 +1,31 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE bulk_operations (
+    id BIGSERIAL PRIMARY KEY,
+    action VARCHAR(16) NOT NULL,
+    status VARCHAR(16) NOT NULL,
+    target_queue VARCHAR(255) NOT NULL DEFAULT '',
+    filter_queue VARCHAR(255) NOT NULL DEFAULT '',
+    filter_status VARCHAR(50) NOT NULL DEFAULT '',
+    created_after TIMESTAMP WITH TIME ZONE,
+    created_before TIMESTAMP WITH TIME ZONE,
+    error_contains TEXT NOT NULL DEFAULT '',
+    total INTEGER NOT NULL DEFAULT 0,
+    processed INTEGER NOT NULL DEFAULT 0,
+    max_job_id BIGINT NOT NULL DEFAULT 0,
+    last_job_id BIGINT NOT NULL DEFAULT 0,
+    error TEXT NOT NULL DEFAULT '',
+    started_at TIMESTAMP WITH TIME ZONE,
+    finished_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_bulk_operations_unfinished ON bulk_operations(id)
+    WHERE status IN ('pending', 'running');
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE bulk_operations;
+-- +goose StatementEnd
@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE jobs ADD COLUMN ttl_seconds INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE jobs_archive ADD COLUMN ttl_seconds INTEGER NOT NULL DEFAULT 0;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE jobs_archive DROP COLUMN ttl_seconds;
+ALTER TABLE jobs DROP COLUMN ttl_seconds;
+-- +goose StatementEnd
@@ -477,6 +477,24 @@ func setupTestDB(tb testing.TB) (*gorm.DB, context.Context) {
 	if err := db.Exec("DELETE FROM jobs").Error; err != nil {
 		tb.Logf("Warning: Failed to clean jobs table: %v", err)
 	}
+	if err := db.Exec("DELETE FROM bulk_operations").Error; err != nil {
+		tb.Logf("Warning: Failed to clean bulk_operations table: %v", err)
+	}
+	if err := db.Exec("DELETE FROM jobs_archive").Error; err != nil {
+		tb.Logf("Warning: Failed to clean jobs_archive table: %v", err)
+	}
+	if err := db.Exec("DELETE FROM sagas").Error; err != nil {
+		tb.Logf("Warning: Failed to clean sagas table: %v", err)
+	}
+	if err := db.Exec("DELETE FROM batches").Error; err != nil {
+		tb.Logf("Warning: Failed to clean batches table: %v", err)
+	}
+	if err := db.Exec("DELETE FROM workers").Error; err != nil {
+		tb.Logf("Warning: Failed to clean workers table: %v", err)
+	}
+	if err := db.Exec("DELETE FROM payment_ledger").Error; err != nil {
+		tb.Logf("Warning: Failed to clean payment_ledger table: %v", err)
+	}
 
 	// Register cleanup
 	tb.Cleanup(func() {
@@ -0,0 +1,86 @@
+package integration
+
+import (
+	"testing"
+	"time"
+
+	"github.com/joshu-sajeev/goqueue/internal/config"
+	"github.com/joshu-sajeev/goqueue/internal/models"
+	"github.com/joshu-sajeev/goqueue/internal/storage/postgres"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/datatypes"
+)
+
+func TestJobRepository_ArchiveJobs(t *testing.T) {
+	db, ctx := setupTestDB(t)
+	defer closeTestDB(db)
+
+	repo := postgres.NewJobRepository(db)
+	old := finishedJob(t, db, repo, "email", config.JobStatusCompleted, 48*time.Hour)
+	require.NoError(t, db.Exec("UPDATE jobs SET result = ?, created_at = ? WHERE id = ?",
+		datatypes.JSON(`{"sent":true}`), time.Date(2025, 11, 30, 23, 0, 0, 0, time.UTC), old.ID).Error)
+	older := finishedJob(t, db, repo, "email", config.JobStatusCompleted, 72*time.Hour)
+	recent := finishedJob(t, db, repo, "email", config.JobStatusCompleted, time.Hour)
+
+	n, err := repo.ArchiveJobs(ctx, "email", config.JobStatusCompleted, time.Now().Add(-24*time.Hour), 10)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), n)
+
+	// Archived jobs leave the hot table.
+	_, err = repo.Get(ctx, old.ID)
+	assert.ErrorContains(t, err, "job not found")
+	_, err = repo.Get(ctx, recent.ID)
+	assert.NoError(t, err)
+
+	got, err := repo.GetArchived(ctx, old.ID)
+	require.NoError(t, err)
+	assert.Equal(t, config.JobStatusCompleted, got.Status)
+	assert.JSONEq(t, `{"sent":true}`, string(got.Result))
+	require.NotNil(t, got.ArchivedAt)
+	assert.WithinDuration(t, time.Now(), *got.ArchivedAt, time.Minute)
+
+	_, err = repo.GetArchived(ctx, older.ID)
+	assert.NoError(t, err)
+	_, err = repo.GetArchived(ctx, recent.ID)
+	assert.ErrorContains(t, err, "job not found")
+
+	// One partition per month of created_at.
+	var partitions []string
+	require.NoError(t, db.Raw(`
+		SELECT c.relname FROM pg_inherits i
+		JOIN pg_class c ON c.oid = i.inhrelid
+		WHERE i.inhparent = 'jobs_archive'::regclass
+		ORDER BY c.relname`).Scan(&partitions).Error)
+	assert.Contains(t, partitions, "jobs_archive_2025_11")
+	assert.Contains(t, partitions, time.Now().Add(-72*time.Hour).UTC().Format("jobs_archive_2006_01"))
+
+	// Archiving again creates no duplicates.
+	n, err = repo.ArchiveJobs(ctx, "email", config.JobStatusCompleted, time.Now().Add(-24*time.Hour), 10)
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), n)
+}
+
+func TestJobRepository_ArchiveJobs_KeepsSagaSteps(t *testing.T) {
+	db, ctx := setupTestDB(t)
+	defer closeTestDB(db)
+
+	repo := postgres.NewJobRepository(db)
+	saga, steps := createSaga(t, repo, 1)
+	runNext(t, repo, "saga", steps[0].JobID)
+	require.NoError(t, repo.MarkCompleted(ctx, steps[0].JobID, 1, datatypes.JSON(`{}`)))
+	require.NoError(t, db.Exec("UPDATE jobs SET updated_at = ? WHERE id = ?", time.Now().Add(-48*time.Hour), steps[0].JobID).Error)
+
+	cutoff := time.Now().Add(-24 * time.Hour)
+	n, err := repo.ArchiveJobs(ctx, "saga", config.JobStatusCompleted, cutoff, 10)
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), n)
+	n, err = repo.PruneJobs(ctx, "saga", config.JobStatusCompleted, cutoff, 10)
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), n)
+
+	got, gotSteps, err := repo.GetSaga(ctx, saga.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.SagaStatusCompleted, got.Status)
+	assert.Len(t, gotSteps, 1)
+}
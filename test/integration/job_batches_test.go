@@ -0,0 +1,174 @@
+package integration
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/joshu-sajeev/goqueue/internal/config"
+	"github.com/joshu-sajeev/goqueue/internal/job"
+	"github.com/joshu-sajeev/goqueue/internal/models"
+	"github.com/joshu-sajeev/goqueue/internal/storage/postgres"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/datatypes"
+)
+
+// createBatch creates a batch of n jobs on the "batch" queue with both
+// callbacks on the "callbacks" queue.
+func createBatch(t *testing.T, repo *postgres.JobRepository, n int) (*models.Batch, []*models.Job) {
+	t.Helper()
+	batch := &models.Batch{
+		OnComplete: &models.Job{Queue: "callbacks", Payload: datatypes.JSON(`{"event":"complete"}`)},
+		OnFailure:  &models.Job{Queue: "callbacks", Payload: datatypes.JSON(`{"event":"failure"}`)},
+	}
+	jobs := make([]*models.Job, n)
+	for i := range jobs {
+		jobs[i] = &models.Job{Queue: "batch", Payload: datatypes.JSON(`{}`), MaxRetries: 3}
+	}
+	require.NoError(t, repo.CreateBatch(t.Context(), batch, jobs))
+	return batch, jobs
+}
+
+func acquireAll(t *testing.T, repo *postgres.JobRepository, queue string, n int) {
+	t.Helper()
+	for range n {
+		j, err := repo.AcquireNext(t.Context(), queue, 1, time.Minute)
+		require.NoError(t, err)
+		require.NotNil(t, j)
+	}
+}
+
+func TestJobRepository_CreateBatch(t *testing.T) {
+	db, ctx := setupTestDB(t)
+	defer closeTestDB(db)
+
+	repo := postgres.NewJobRepository(db)
+	batch, jobs := createBatch(t, repo, 3)
+
+	assert.NotZero(t, batch.ID)
+	assert.Equal(t, 3, batch.Total)
+	assert.Equal(t, 3, batch.Pending)
+	require.NotNil(t, batch.OnCompleteJobID)
+	require.NotNil(t, batch.OnFailureJobID)
+
+	for _, j := range jobs {
+		got, err := repo.Get(ctx, j.ID)
+		require.NoError(t, err)
+		assert.Equal(t, config.JobStatusQueued, got.Status)
+		assert.Equal(t, &batch.ID, got.BatchID)
+	}
+
+	callback, err := repo.Get(ctx, *batch.OnCompleteJobID)
+	require.NoError(t, err)
+	assert.Equal(t, config.JobStatusWaiting, callback.Status)
+	assert.Nil(t, callback.BatchID)
+}
+
+func TestJobRepository_Batch_CompletesWithoutFailures(t *testing.T) {
+	db, ctx := setupTestDB(t)
+	defer closeTestDB(db)
+
+	repo := postgres.NewJobRepository(db)
+	batch, jobs := createBatch(t, repo, 2)
+	acquireAll(t, repo, "batch", 2)
+
+	require.NoError(t, repo.MarkCompleted(ctx, jobs[0].ID, 1, datatypes.JSON(`{}`)))
+	got, err := repo.GetBatch(ctx, batch.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 1, got.Pending)
+	assert.Equal(t, 1, got.Succeeded)
+	assert.Nil(t, got.FinishedAt)
+
+	callback, err := repo.Get(ctx, *batch.OnCompleteJobID)
+	require.NoError(t, err)
+	assert.Equal(t, config.JobStatusWaiting, callback.Status)
+
+	require.NoError(t, repo.MarkCompleted(ctx, jobs[1].ID, 1, datatypes.JSON(`{}`)))
+	got, err = repo.GetBatch(ctx, batch.ID)
+	require.NoError(t, err)
+	assert.True(t, got.IsFinished())
+	assert.Equal(t, 2, got.Succeeded)
+	assert.NotNil(t, got.FinishedAt)
+
+	callback, err = repo.Get(ctx, *batch.OnCompleteJobID)
+	require.NoError(t, err)
+	assert.Equal(t, config.JobStatusQueued, callback.Status)
+	var payload struct {
+		Event string         `json:"event"`
+		Batch map[string]int `json:"batch"`
+	}
+	require.NoError(t, json.Unmarshal(callback.Payload, &payload))
+	assert.Equal(t, "complete", payload.Event)
+	assert.Equal(t, map[string]int{"id": int(batch.ID), "total": 2, "pending": 0, "succeeded": 2, "failed": 0}, payload.Batch)
+
+	failure, err := repo.Get(ctx, *batch.OnFailureJobID)
+	require.NoError(t, err)
+	assert.Equal(t, config.JobStatusSkipped, failure.Status)
+}
+
+func TestJobRepository_Batch_FailureCallbackOnFirstFailure(t *testing.T) {
+	db, ctx := setupTestDB(t)
+	defer closeTestDB(db)
+
+	repo := postgres.NewJobRepository(db)
+	batch, jobs := createBatch(t, repo, 3)
+	acquireAll(t, repo, "batch", 3)
+
+	require.NoError(t, repo.MarkFailed(ctx, jobs[0].ID, 1, "boom"))
+	failure, err := repo.Get(ctx, *batch.OnFailureJobID)
+	require.NoError(t, err)
+	assert.Equal(t, config.JobStatusQueued, failure.Status)
+
+	// The callback is only queued once.
+	acquireAll(t, repo, "callbacks", 1)
+	require.NoError(t, repo.MarkFailed(ctx, jobs[1].ID, 1, "boom"))
+	failure, err = repo.Get(ctx, *batch.OnFailureJobID)
+	require.NoError(t, err)
+	assert.Equal(t, config.JobStatusRunning, failure.Status)
+
+	require.NoError(t, repo.MarkCompleted(ctx, jobs[2].ID, 1, datatypes.JSON(`{}`)))
+	got, err := repo.GetBatch(ctx, batch.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 0, got.Pending)
+	assert.Equal(t, 1, got.Succeeded)
+	assert.Equal(t, 2, got.Failed)
+
+	complete, err := repo.Get(ctx, *batch.OnCompleteJobID)
+	require.NoError(t, err)
+	assert.Equal(t, config.JobStatusQueued, complete.Status)
+}
+
+func TestJobRepository_Batch_CountsSkippedDependents(t *testing.T) {
+	db, ctx := setupTestDB(t)
+	defer closeTestDB(db)
+
+	repo := postgres.NewJobRepository(db)
+	root := createRunning(t, repo)
+
+	batch := &models.Batch{}
+	jobs := []*models.Job{
+		{Queue: "batch", Payload: datatypes.JSON(`{}`)},
+		{
+			Queue:               "batch",
+			Payload:             datatypes.JSON(`{}`),
+			DependsOn:           []uint{root.ID},
+			OnDependencyFailure: config.DependencyFailureSkip,
+		},
+	}
+	require.NoError(t, repo.CreateBatch(ctx, batch, jobs))
+
+	require.NoError(t, repo.MarkFailed(ctx, root.ID, 1, "boom"))
+	got, err := repo.GetBatch(ctx, batch.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 1, got.Pending)
+	assert.Equal(t, 1, got.Failed)
+}
+
+func TestJobRepository_GetBatch_NotFound(t *testing.T) {
+	db, ctx := setupTestDB(t)
+	defer closeTestDB(db)
+
+	_, err := postgres.NewJobRepository(db).GetBatch(ctx, 999999)
+	assert.ErrorIs(t, err, job.ErrBatchNotFound)
+}
@@ -0,0 +1,296 @@
+package integration
+
+import (
+	"testing"
+	"time"
+
+	"github.com/joshu-sajeev/goqueue/internal/config"
+	"github.com/joshu-sajeev/goqueue/internal/job"
+	"github.com/joshu-sajeev/goqueue/internal/models"
+	"github.com/joshu-sajeev/goqueue/internal/storage/postgres"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/datatypes"
+)
+
+// runBulkOperation claims op and processes it in batches of batchSize
+// until it finishes, returning the number of batches.
+func runBulkOperation(t *testing.T, repo *postgres.JobRepository, op *models.BulkOperation, batchSize int) int {
+	t.Helper()
+	claimed, err := repo.ClaimBulkOperation(t.Context(), time.Minute)
+	require.NoError(t, err)
+	require.NotNil(t, claimed)
+	require.Equal(t, op.ID, claimed.ID)
+	assert.Equal(t, models.BulkOperationStatusRunning, claimed.Status)
+
+	batches := 0
+	for !claimed.IsFinished() {
+		require.NoError(t, repo.ProcessBulkBatch(t.Context(), claimed, batchSize))
+		batches++
+	}
+	*op = *claimed
+	return batches
+}
+
+func TestJobRepository_BulkRetry(t *testing.T) {
+	db, ctx := setupTestDB(t)
+	defer closeTestDB(db)
+
+	repo := postgres.NewJobRepository(db)
+	var matching []*models.Job
+	for range 5 {
+		j := finishedJob(t, db, repo, "email", config.JobStatusFailed, time.Hour)
+		require.NoError(t, db.Exec("UPDATE jobs SET error = 'SMTP Timeout', attempts = 3 WHERE id = ?", j.ID).Error)
+		matching = append(matching, j)
+	}
+	otherError := finishedJob(t, db, repo, "email", config.JobStatusFailed, time.Hour)
+	require.NoError(t, db.Exec("UPDATE jobs SET error = 'bad address' WHERE id = ?", otherError.ID).Error)
+	completed := finishedJob(t, db, repo, "email", config.JobStatusCompleted, time.Hour)
+
+	op := &models.BulkOperation{Action: models.BulkActionRetry, FilterQueue: "email", ErrorContains: "timeout"}
+	require.NoError(t, repo.CreateBulkOperation(ctx, op))
+	assert.Equal(t, models.BulkOperationStatusPending, op.Status)
+	assert.Equal(t, 5, op.Total)
+
+	// Jobs created after the operation are not affected.
+	late := finishedJob(t, db, repo, "email", config.JobStatusFailed, time.Hour)
+	require.NoError(t, db.Exec("UPDATE jobs SET error = 'timeout' WHERE id = ?", late.ID).Error)
+
+	assert.Equal(t, 3, runBulkOperation(t, repo, op, 2))
+	assert.Equal(t, models.BulkOperationStatusCompleted, op.Status)
+	assert.Equal(t, 5, op.Processed)
+	assert.NotNil(t, op.FinishedAt)
+
+	for _, j := range matching {
+		got, err := repo.Get(ctx, j.ID)
+		require.NoError(t, err)
+		assert.Equal(t, config.JobStatusQueued, got.Status)
+		assert.Zero(t, got.Attempts)
+		assert.Empty(t, got.Error)
+	}
+	for id, status := range map[uint]config.JobStatus{
+		otherError.ID: config.JobStatusFailed,
+		completed.ID:  config.JobStatusCompleted,
+		late.ID:       config.JobStatusFailed,
+	} {
+		got, err := repo.Get(ctx, id)
+		require.NoError(t, err)
+		assert.Equal(t, status, got.Status)
+	}
+
+	stored, err := repo.GetBulkOperation(ctx, op.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 5, stored.Processed)
+}
+
+func TestJobRepository_BulkRetry_WaitsForDependencies(t *testing.T) {
+	db, ctx := setupTestDB(t)
+	defer closeTestDB(db)
+
+	repo := postgres.NewJobRepository(db)
+	failedParent := finishedJob(t, db, repo, "email", config.JobStatusFailed, time.Hour)
+	skipped := &models.Job{
+		Queue:               "webhooks",
+		Payload:             datatypes.JSON(`{}`),
+		DependsOn:           []uint{failedParent.ID},
+		OnDependencyFailure: config.DependencyFailureSkip,
+	}
+	require.NoError(t, repo.Create(ctx, skipped))
+	require.Equal(t, config.JobStatusSkipped, skipped.Status)
+
+	completedParent := finishedJob(t, db, repo, "email", config.JobStatusCompleted, time.Hour)
+	ready := &models.Job{Queue: "webhooks", Payload: datatypes.JSON(`{}`), DependsOn: []uint{completedParent.ID}}
+	require.NoError(t, repo.Create(ctx, ready))
+	require.NoError(t, db.Exec("UPDATE jobs SET status = ? WHERE id = ?", config.JobStatusFailed, ready.ID).Error)
+
+	op := &models.BulkOperation{Action: models.BulkActionRetry, FilterQueue: "webhooks"}
+	require.NoError(t, repo.CreateBulkOperation(ctx, op))
+	assert.Equal(t, 2, op.Total)
+	runBulkOperation(t, repo, op, 10)
+
+	got, err := repo.Get(ctx, skipped.ID)
+	require.NoError(t, err)
+	assert.Equal(t, config.JobStatusSkipped, got.Status)
+	got, err = repo.Get(ctx, ready.ID)
+	require.NoError(t, err)
+	assert.Equal(t, config.JobStatusQueued, got.Status)
+}
+
+func TestJobRepository_BulkRetry_SkipsBatchAndSagaJobs(t *testing.T) {
+	db, ctx := setupTestDB(t)
+	defer closeTestDB(db)
+
+	repo := postgres.NewJobRepository(db)
+	_, jobs := createBatch(t, repo, 1)
+	acquireAll(t, repo, "batch", 1)
+	require.NoError(t, repo.MarkFailed(ctx, jobs[0].ID, 1, "boom"))
+
+	_, steps := createSaga(t, repo, 1)
+	runNext(t, repo, "saga", steps[0].JobID)
+	require.NoError(t, repo.MarkFailed(ctx, steps[0].JobID, 1, "boom"))
+
+	for _, queue := range []string{"batch", "saga"} {
+		op := &models.BulkOperation{Action: models.BulkActionRetry, FilterQueue: queue}
+		require.NoError(t, repo.CreateBulkOperation(ctx, op))
+		assert.Equal(t, 0, op.Total, queue)
+		assert.Equal(t, models.BulkOperationStatusCompleted, op.Status, queue)
+	}
+
+	for _, id := range []uint{jobs[0].ID, steps[0].JobID} {
+		got, err := repo.Get(ctx, id)
+		require.NoError(t, err)
+		assert.Equal(t, config.JobStatusFailed, got.Status)
+	}
+}
+
+func TestJobRepository_BulkCancel_FailsDependents(t *testing.T) {
+	db, ctx := setupTestDB(t)
+	defer closeTestDB(db)
+
+	repo := postgres.NewJobRepository(db)
+	parent := createRunning(t, repo)
+	waiting := &models.Job{Queue: "webhooks", Payload: datatypes.JSON(`{}`), DependsOn: []uint{parent.ID}}
+	require.NoError(t, repo.Create(ctx, waiting))
+	child := &models.Job{Queue: "default", Payload: datatypes.JSON(`{}`), DependsOn: []uint{waiting.ID}}
+	require.NoError(t, repo.Create(ctx, child))
+	queued := &models.Job{Queue: "webhooks", Payload: datatypes.JSON(`{}`)}
+	require.NoError(t, repo.Create(ctx, queued))
+
+	op := &models.BulkOperation{Action: models.BulkActionCancel, FilterQueue: "webhooks"}
+	require.NoError(t, repo.CreateBulkOperation(ctx, op))
+	assert.Equal(t, 2, op.Total)
+	runBulkOperation(t, repo, op, 10)
+	assert.Equal(t, 2, op.Processed)
+
+	for id, status := range map[uint]config.JobStatus{
+		waiting.ID: config.JobStatusCancelled,
+		queued.ID:  config.JobStatusCancelled,
+		child.ID:   config.JobStatusFailed,
+		parent.ID:  config.JobStatusRunning,
+	} {
+		got, err := repo.Get(ctx, id)
+		require.NoError(t, err)
+		assert.Equal(t, status, got.Status)
+	}
+}
+
+func TestJobRepository_BulkDelete_ReleasesDependents(t *testing.T) {
+	db, ctx := setupTestDB(t)
+	defer closeTestDB(db)
+
+	repo := postgres.NewJobRepository(db)
+	stale := finishedJob(t, db, repo, "email", config.JobStatusFailed, time.Hour)
+	waiting := &models.Job{Queue: "default", Payload: datatypes.JSON(`{}`), DependsOn: []uint{stale.ID}}
+	require.NoError(t, repo.Create(ctx, waiting))
+	require.NoError(t, db.Exec("UPDATE jobs SET status = ? WHERE id = ?", config.JobStatusWaiting, waiting.ID).Error)
+	running := createRunning(t, repo)
+
+	op := &models.BulkOperation{Action: models.BulkActionDelete, FilterStatus: config.JobStatusFailed}
+	require.NoError(t, repo.CreateBulkOperation(ctx, op))
+	runBulkOperation(t, repo, op, 10)
+	assert.Equal(t, 1, op.Processed)
+
+	_, err := repo.Get(ctx, stale.ID)
+	assert.Error(t, err)
+	got, err := repo.Get(ctx, waiting.ID)
+	require.NoError(t, err)
+	assert.Equal(t, config.JobStatusQueued, got.Status)
+	got, err = repo.Get(ctx, running.ID)
+	require.NoError(t, err)
+	assert.Equal(t, config.JobStatusRunning, got.Status)
+}
+
+func TestJobRepository_BulkDelete_CancelsUnfinishedJobs(t *testing.T) {
+	db, ctx := setupTestDB(t)
+	defer closeTestDB(db)
+
+	repo := postgres.NewJobRepository(db)
+	queued := &models.Job{Queue: "webhooks", Payload: datatypes.JSON(`{}`)}
+	require.NoError(t, repo.Create(ctx, queued))
+	child := &models.Job{Queue: "default", Payload: datatypes.JSON(`{}`), DependsOn: []uint{queued.ID}}
+	require.NoError(t, repo.Create(ctx, child))
+
+	op := &models.BulkOperation{Action: models.BulkActionDelete, FilterQueue: "webhooks"}
+	require.NoError(t, repo.CreateBulkOperation(ctx, op))
+	runBulkOperation(t, repo, op, 10)
+	assert.Equal(t, 1, op.Processed)
+
+	_, err := repo.Get(ctx, queued.ID)
+	assert.Error(t, err)
+	got, err := repo.Get(ctx, child.ID)
+	require.NoError(t, err)
+	assert.Equal(t, config.JobStatusFailed, got.Status)
+}
+
+func TestJobRepository_BulkMove(t *testing.T) {
+	db, ctx := setupTestDB(t)
+	defer closeTestDB(db)
+
+	repo := postgres.NewJobRepository(db)
+	after := time.Now()
+	queued := &models.Job{Queue: "email", Payload: datatypes.JSON(`{}`)}
+	require.NoError(t, repo.Create(ctx, queued))
+	failed := finishedJob(t, db, repo, "email", config.JobStatusFailed, time.Hour)
+
+	op := &models.BulkOperation{Action: models.BulkActionMove, FilterQueue: "email", CreatedAfter: &after, TargetQueue: "default"}
+	require.NoError(t, repo.CreateBulkOperation(ctx, op))
+	runBulkOperation(t, repo, op, 10)
+	assert.Equal(t, 1, op.Processed)
+
+	got, err := repo.Get(ctx, queued.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "default", got.Queue)
+	got, err = repo.Get(ctx, failed.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "email", got.Queue)
+}
+
+func TestJobRepository_BulkOperation_NoMatches(t *testing.T) {
+	db, ctx := setupTestDB(t)
+	defer closeTestDB(db)
+
+	repo := postgres.NewJobRepository(db)
+	op := &models.BulkOperation{Action: models.BulkActionRetry, FilterQueue: "payment"}
+	require.NoError(t, repo.CreateBulkOperation(ctx, op))
+	assert.Equal(t, models.BulkOperationStatusCompleted, op.Status)
+	assert.Zero(t, op.Total)
+
+	claimed, err := repo.ClaimBulkOperation(ctx, time.Minute)
+	require.NoError(t, err)
+	assert.Nil(t, claimed)
+}
+
+func TestJobRepository_ClaimBulkOperation_TakesOverStale(t *testing.T) {
+	db, ctx := setupTestDB(t)
+	defer closeTestDB(db)
+
+	repo := postgres.NewJobRepository(db)
+	finishedJob(t, db, repo, "email", config.JobStatusFailed, time.Hour)
+	op := &models.BulkOperation{Action: models.BulkActionRetry, FilterQueue: "email"}
+	require.NoError(t, repo.CreateBulkOperation(ctx, op))
+
+	claimed, err := repo.ClaimBulkOperation(ctx, time.Minute)
+	require.NoError(t, err)
+	require.NotNil(t, claimed)
+
+	// A running operation is left to its worker until it goes stale.
+	again, err := repo.ClaimBulkOperation(ctx, time.Minute)
+	require.NoError(t, err)
+	assert.Nil(t, again)
+
+	require.NoError(t, db.Exec("UPDATE bulk_operations SET updated_at = ? WHERE id = ?",
+		time.Now().Add(-2*time.Minute), op.ID).Error)
+	again, err = repo.ClaimBulkOperation(ctx, time.Minute)
+	require.NoError(t, err)
+	require.NotNil(t, again)
+	assert.Equal(t, op.ID, again.ID)
+}
+
+func TestJobRepository_GetBulkOperation_NotFound(t *testing.T) {
+	db, ctx := setupTestDB(t)
+	defer closeTestDB(db)
+
+	repo := postgres.NewJobRepository(db)
+	_, err := repo.GetBulkOperation(ctx, 999999)
+	assert.ErrorIs(t, err, job.ErrBulkOperationNotFound)
+}
@@ -0,0 +1,40 @@
+package integration
+
+import (
+	"testing"
+	"time"
+
+	"github.com/joshu-sajeev/goqueue/internal/config"
+	"github.com/joshu-sajeev/goqueue/internal/models"
+	"github.com/joshu-sajeev/goqueue/internal/storage/postgres"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/datatypes"
+)
+
+func TestJobRepository_CreateJobs(t *testing.T) {
+	db, ctx := setupTestDB(t)
+	defer closeTestDB(db)
+
+	repo := postgres.NewJobRepository(db)
+	jobs := make([]*models.Job, 1200)
+	for i := range jobs {
+		jobs[i] = &models.Job{Queue: "default", Payload: datatypes.JSON(`{}`), MaxRetries: 3}
+	}
+	require.NoError(t, repo.CreateJobs(ctx, jobs))
+
+	for i, j := range jobs {
+		require.NotZero(t, j.ID)
+		if i > 0 {
+			assert.Greater(t, j.ID, jobs[i-1].ID)
+		}
+	}
+
+	var count int64
+	require.NoError(t, db.Model(&models.Job{}).Where("status = ?", config.JobStatusQueued).Count(&count).Error)
+	assert.Equal(t, int64(len(jobs)), count)
+
+	acquired, err := repo.AcquireNext(ctx, "default", 1, time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, jobs[0].ID, acquired.ID)
+}
@@ -0,0 +1,120 @@
+package integration
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/joshu-sajeev/goqueue/internal/models"
+	"github.com/joshu-sajeev/goqueue/internal/storage/postgres"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/datatypes"
+)
+
+func createWithConcurrencyKey(t *testing.T, repo *postgres.JobRepository, queue, key string, limit int) *models.Job {
+	t.Helper()
+	j := &models.Job{
+		Queue:            queue,
+		Payload:          datatypes.JSON(`{}`),
+		MaxRetries:       3,
+		ConcurrencyKey:   &key,
+		ConcurrencyLimit: limit,
+	}
+	require.NoError(t, repo.Create(t.Context(), j))
+	return j
+}
+
+func TestJobRepository_AcquireNext_ConcurrencyKey(t *testing.T) {
+	db, ctx := setupTestDB(t)
+	defer closeTestDB(db)
+
+	repo := postgres.NewJobRepository(db)
+	first := createWithConcurrencyKey(t, repo, "default", "account-1", 1)
+	second := createWithConcurrencyKey(t, repo, "default", "account-1", 1)
+	// The key is shared across queues.
+	third := createWithConcurrencyKey(t, repo, "webhooks", "account-1", 1)
+	other := createWithConcurrencyKey(t, repo, "default", "account-2", 1)
+
+	got, err := repo.AcquireNext(ctx, "default", 1, time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, first.ID, got.ID)
+
+	got, err = repo.AcquireNext(ctx, "default", 1, time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, other.ID, got.ID)
+
+	got, err = repo.AcquireNext(ctx, "webhooks", 1, time.Minute)
+	require.NoError(t, err)
+	assert.Nil(t, got)
+
+	require.NoError(t, repo.MarkCompleted(ctx, first.ID, 1, datatypes.JSON(`{}`)))
+	got, err = repo.AcquireNext(ctx, "default", 1, time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, second.ID, got.ID)
+
+	require.NoError(t, repo.MarkCompleted(ctx, second.ID, 1, datatypes.JSON(`{}`)))
+	got, err = repo.AcquireNext(ctx, "webhooks", 1, time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, third.ID, got.ID)
+}
+
+func TestJobRepository_AcquireNext_ConcurrencyLimitUnderContention(t *testing.T) {
+	db, ctx := setupTestDB(t)
+	defer closeTestDB(db)
+
+	repo := postgres.NewJobRepository(db)
+	for range 10 {
+		createWithConcurrencyKey(t, repo, "default", "account-1", 2)
+	}
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		acquired int
+	)
+	for w := range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			got, err := repo.AcquireNext(ctx, "default", uint(w+1), time.Minute)
+			assert.NoError(t, err)
+			if got != nil {
+				mu.Lock()
+				acquired++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, 2, acquired)
+	var running int64
+	require.NoError(t, db.Model(&models.Job{}).Where("status = ?", "running").Count(&running).Error)
+	assert.Equal(t, int64(2), running)
+}
+
+func TestJobRepository_AcquireNext_SkipsKeyLockedByAnotherWorker(t *testing.T) {
+	db, ctx := setupTestDB(t)
+	defer closeTestDB(db)
+
+	repo := postgres.NewJobRepository(db)
+	busy := createWithConcurrencyKey(t, repo, "default", "account-1", 2)
+	other := createWithConcurrencyKey(t, repo, "default", "account-2", 2)
+
+	// Another worker is claiming a slot of account-1.
+	tx := db.Begin()
+	defer tx.Rollback()
+	require.NoError(t, tx.Exec("SELECT pg_advisory_xact_lock(hashtextextended(?, 0))", "account-1").Error)
+
+	got, err := repo.AcquireNext(ctx, "default", 1, time.Minute)
+	require.NoError(t, err)
+	require.NotNil(t, got)
+	assert.Equal(t, other.ID, got.ID)
+
+	require.NoError(t, tx.Rollback().Error)
+	got, err = repo.AcquireNext(ctx, "default", 1, time.Minute)
+	require.NoError(t, err)
+	require.NotNil(t, got)
+	assert.Equal(t, busy.ID, got.ID)
+}
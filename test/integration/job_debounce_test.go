@@ -0,0 +1,101 @@
+package integration
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/joshu-sajeev/goqueue/internal/config"
+	"github.com/joshu-sajeev/goqueue/internal/job"
+	"github.com/joshu-sajeev/goqueue/internal/models"
+	"github.com/joshu-sajeev/goqueue/internal/storage/postgres"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/datatypes"
+)
+
+func debounced(key, mode string, window time.Duration, payload string) *models.Job {
+	return &models.Job{
+		Queue:          "default",
+		Payload:        datatypes.JSON(payload),
+		MaxRetries:     3,
+		DebounceKey:    &key,
+		DebounceMode:   mode,
+		DebounceWindow: window,
+	}
+}
+
+func TestJobRepository_Create_Debounce(t *testing.T) {
+	db, ctx := setupTestDB(t)
+	defer closeTestDB(db)
+
+	repo := postgres.NewJobRepository(db)
+	first := debounced("doc-1", config.DebounceModeDebounce, time.Minute, `{"rev":1}`)
+	require.NoError(t, repo.Create(ctx, first))
+	assert.WithinDuration(t, time.Now().Add(time.Minute), first.AvailableAt, 5*time.Second)
+
+	second := debounced("doc-1", config.DebounceModeDebounce, 2*time.Minute, `{"rev":2}`)
+	require.NoError(t, repo.Create(ctx, second))
+	assert.Equal(t, first.ID, second.ID)
+
+	got, err := repo.Get(ctx, first.ID)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"rev":2}`, string(got.Payload))
+	assert.WithinDuration(t, time.Now().Add(2*time.Minute), got.AvailableAt, 5*time.Second)
+
+	// Other keys are independent.
+	other := debounced("doc-2", config.DebounceModeDebounce, time.Minute, `{"rev":1}`)
+	require.NoError(t, repo.Create(ctx, other))
+	assert.NotEqual(t, first.ID, other.ID)
+
+	var count int64
+	require.NoError(t, db.Model(&models.Job{}).Count(&count).Error)
+	assert.Equal(t, int64(2), count)
+}
+
+func TestJobRepository_Create_DebounceAfterStart(t *testing.T) {
+	db, ctx := setupTestDB(t)
+	defer closeTestDB(db)
+
+	repo := postgres.NewJobRepository(db)
+	first := debounced("doc-1", config.DebounceModeDebounce, time.Second, `{"rev":1}`)
+	require.NoError(t, repo.Create(ctx, first))
+	require.NoError(t, db.Model(first).Update("available_at", time.Now().Add(-time.Minute)).Error)
+
+	acquired, err := repo.AcquireNext(ctx, "default", 1, time.Minute)
+	require.NoError(t, err)
+	require.Equal(t, first.ID, acquired.ID)
+
+	// A running job is not modified; the enqueue creates a new job.
+	second := debounced("doc-1", config.DebounceModeDebounce, time.Second, `{"rev":2}`)
+	require.NoError(t, repo.Create(ctx, second))
+	assert.NotEqual(t, first.ID, second.ID)
+
+	got, err := repo.Get(ctx, first.ID)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"rev":1}`, string(got.Payload))
+}
+
+func TestJobRepository_Create_Throttle(t *testing.T) {
+	db, ctx := setupTestDB(t)
+	defer closeTestDB(db)
+
+	repo := postgres.NewJobRepository(db)
+	first := debounced("report-1", config.DebounceModeThrottle, time.Minute, `{}`)
+	require.NoError(t, repo.Create(ctx, first))
+	assert.WithinDuration(t, time.Now(), first.AvailableAt, 5*time.Second)
+
+	err := repo.Create(ctx, debounced("report-1", config.DebounceModeThrottle, time.Minute, `{}`))
+	var throttled *job.ThrottledError
+	require.True(t, errors.As(err, &throttled))
+	assert.Equal(t, first.ID, throttled.JobID)
+	assert.WithinDuration(t, first.CreatedAt.Add(time.Minute), throttled.Until, time.Second)
+
+	// Once the window has passed the next enqueue is accepted.
+	require.NoError(t, db.Model(first).Update("created_at", time.Now().Add(-2*time.Minute)).Error)
+	require.NoError(t, repo.Create(ctx, debounced("report-1", config.DebounceModeThrottle, time.Minute, `{}`)))
+
+	var count int64
+	require.NoError(t, db.Model(&models.Job{}).Count(&count).Error)
+	assert.Equal(t, int64(2), count)
+}
@@ -0,0 +1,154 @@
+package integration
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/joshu-sajeev/goqueue/internal/config"
+	"github.com/joshu-sajeev/goqueue/internal/job"
+	"github.com/joshu-sajeev/goqueue/internal/models"
+	"github.com/joshu-sajeev/goqueue/internal/storage/postgres"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/datatypes"
+)
+
+func createRunning(t *testing.T, repo *postgres.JobRepository) *models.Job {
+	t.Helper()
+	j := &models.Job{Queue: "default", Payload: datatypes.JSON(`{}`), MaxRetries: 3}
+	require.NoError(t, repo.Create(t.Context(), j))
+	acquired, err := repo.AcquireNext(t.Context(), "default", 1, time.Minute)
+	require.NoError(t, err)
+	require.Equal(t, j.ID, acquired.ID)
+	return j
+}
+
+func TestJobRepository_Dependencies_QueuedWhenAllComplete(t *testing.T) {
+	db, ctx := setupTestDB(t)
+	defer closeTestDB(db)
+
+	repo := postgres.NewJobRepository(db)
+	a := createRunning(t, repo)
+	b := createRunning(t, repo)
+
+	child := &models.Job{
+		Queue:       "webhooks",
+		Payload:     datatypes.JSON(`{"url":"https://example.com"}`),
+		DependsOn:   []uint{a.ID, b.ID},
+		PassResults: true,
+	}
+	require.NoError(t, repo.Create(ctx, child))
+	assert.Equal(t, config.JobStatusWaiting, child.Status)
+
+	require.NoError(t, repo.MarkCompleted(ctx, a.ID, 1, datatypes.JSON(`{"rows":1}`)))
+	got, err := repo.Get(ctx, child.ID)
+	require.NoError(t, err)
+	assert.Equal(t, config.JobStatusWaiting, got.Status)
+
+	require.NoError(t, repo.MarkCompleted(ctx, b.ID, 1, datatypes.JSON(`{"rows":2}`)))
+	got, err = repo.Get(ctx, child.ID)
+	require.NoError(t, err)
+	assert.Equal(t, config.JobStatusQueued, got.Status)
+
+	var payload struct {
+		URL               string                     `json:"url"`
+		DependencyResults map[string]json.RawMessage `json:"dependency_results"`
+	}
+	require.NoError(t, json.Unmarshal(got.Payload, &payload))
+	assert.Equal(t, "https://example.com", payload.URL)
+	assert.JSONEq(t, `{"rows":1}`, string(payload.DependencyResults[uintKey(a.ID)]))
+	assert.JSONEq(t, `{"rows":2}`, string(payload.DependencyResults[uintKey(b.ID)]))
+}
+
+func TestJobRepository_Dependencies_AlreadyCompleted(t *testing.T) {
+	db, ctx := setupTestDB(t)
+	defer closeTestDB(db)
+
+	repo := postgres.NewJobRepository(db)
+	a := createRunning(t, repo)
+	require.NoError(t, repo.MarkCompleted(ctx, a.ID, 1, datatypes.JSON(`{}`)))
+
+	child := &models.Job{Queue: "default", Payload: datatypes.JSON(`{}`), DependsOn: []uint{a.ID}}
+	require.NoError(t, repo.Create(ctx, child))
+	assert.Equal(t, config.JobStatusQueued, child.Status)
+}
+
+func TestJobRepository_Dependencies_FailureCascades(t *testing.T) {
+	db, ctx := setupTestDB(t)
+	defer closeTestDB(db)
+
+	repo := postgres.NewJobRepository(db)
+	root := createRunning(t, repo)
+
+	failing := &models.Job{Queue: "default", Payload: datatypes.JSON(`{}`), DependsOn: []uint{root.ID}}
+	require.NoError(t, repo.Create(ctx, failing))
+	skipping := &models.Job{
+		Queue:               "default",
+		Payload:             datatypes.JSON(`{}`),
+		DependsOn:           []uint{failing.ID},
+		OnDependencyFailure: config.DependencyFailureSkip,
+	}
+	require.NoError(t, repo.Create(ctx, skipping))
+
+	require.NoError(t, repo.MarkFailed(ctx, root.ID, 1, "boom"))
+
+	got, err := repo.Get(ctx, failing.ID)
+	require.NoError(t, err)
+	assert.Equal(t, config.JobStatusFailed, got.Status)
+	assert.Equal(t, "dependency "+uintKey(root.ID)+" did not complete", got.Error)
+
+	got, err = repo.Get(ctx, skipping.ID)
+	require.NoError(t, err)
+	assert.Equal(t, config.JobStatusSkipped, got.Status)
+	assert.Equal(t, "dependency "+uintKey(failing.ID)+" did not complete", got.Error)
+}
+
+func TestJobRepository_Dependencies_NotFound(t *testing.T) {
+	db, ctx := setupTestDB(t)
+	defer closeTestDB(db)
+
+	repo := postgres.NewJobRepository(db)
+	err := repo.Create(ctx, &models.Job{Queue: "default", Payload: datatypes.JSON(`{}`), DependsOn: []uint{999999}})
+	assert.ErrorIs(t, err, job.ErrDependencyNotFound)
+	var notFound *job.DependencyNotFoundError
+	require.ErrorAs(t, err, &notFound)
+	assert.Equal(t, []uint{999999}, notFound.IDs)
+
+	var count int64
+	require.NoError(t, db.Model(&models.Job{}).Count(&count).Error)
+	assert.Zero(t, count)
+}
+
+func TestJobRepository_CreateWorkflow(t *testing.T) {
+	db, ctx := setupTestDB(t)
+	defer closeTestDB(db)
+
+	repo := postgres.NewJobRepository(db)
+	jobs := []*models.Job{
+		{Queue: "default", Payload: datatypes.JSON(`{}`)},
+		{Queue: "default", Payload: datatypes.JSON(`{}`)},
+		{Queue: "default", Payload: datatypes.JSON(`{}`)},
+	}
+	require.NoError(t, repo.CreateWorkflow(ctx, jobs, [][]int{nil, {0}, {0, 1}}))
+
+	for _, j := range jobs {
+		assert.NotZero(t, j.ID)
+	}
+	assert.Equal(t, config.JobStatusQueued, jobs[0].Status)
+	assert.Equal(t, config.JobStatusWaiting, jobs[1].Status)
+	assert.Equal(t, config.JobStatusWaiting, jobs[2].Status)
+
+	var edges []models.JobDependency
+	require.NoError(t, db.Order("job_id, depends_on_id").Find(&edges).Error)
+	assert.Equal(t, []models.JobDependency{
+		{JobID: jobs[1].ID, DependsOnID: jobs[0].ID},
+		{JobID: jobs[2].ID, DependsOnID: jobs[0].ID},
+		{JobID: jobs[2].ID, DependsOnID: jobs[1].ID},
+	}, edges)
+}
+
+func uintKey(id uint) string {
+	b, _ := json.Marshal(id)
+	return string(b)
+}
@@ -0,0 +1,136 @@
+package integration
+
+import (
+	"testing"
+	"time"
+
+	"github.com/joshu-sajeev/goqueue/internal/config"
+	"github.com/joshu-sajeev/goqueue/internal/models"
+	"github.com/joshu-sajeev/goqueue/internal/storage/postgres"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/datatypes"
+)
+
+func expiring(expiresAt time.Time) *models.Job {
+	return &models.Job{
+		Queue:      "default",
+		Payload:    datatypes.JSON(`{}`),
+		MaxRetries: 3,
+		ExpiresAt:  &expiresAt,
+	}
+}
+
+func TestJobRepository_AcquireNext_ExpiresStaleJobs(t *testing.T) {
+	db, ctx := setupTestDB(t)
+	defer closeTestDB(db)
+
+	repo := postgres.NewJobRepository(db)
+	stale := expiring(time.Now().Add(-time.Minute))
+	require.NoError(t, repo.Create(ctx, stale))
+	fresh := expiring(time.Now().Add(time.Hour))
+	require.NoError(t, repo.Create(ctx, fresh))
+
+	acquired, err := repo.AcquireNext(ctx, "default", 1, time.Minute)
+	require.NoError(t, err)
+	require.NotNil(t, acquired)
+	assert.Equal(t, fresh.ID, acquired.ID)
+
+	got, err := repo.Get(ctx, stale.ID)
+	require.NoError(t, err)
+	assert.Equal(t, config.JobStatusExpired, got.Status)
+	assert.Equal(t, 0, got.Attempts)
+	assert.NotEmpty(t, got.Error)
+
+	acquired, err = repo.AcquireNext(ctx, "default", 1, time.Minute)
+	require.NoError(t, err)
+	assert.Nil(t, acquired)
+}
+
+func TestJobRepository_AcquireNext_ExpiryFailsDependents(t *testing.T) {
+	db, ctx := setupTestDB(t)
+	defer closeTestDB(db)
+
+	repo := postgres.NewJobRepository(db)
+	stale := expiring(time.Now().Add(-time.Minute))
+	require.NoError(t, repo.Create(ctx, stale))
+
+	failing := &models.Job{Queue: "webhooks", Payload: datatypes.JSON(`{}`), DependsOn: []uint{stale.ID}}
+	require.NoError(t, repo.Create(ctx, failing))
+	skipping := &models.Job{
+		Queue:               "webhooks",
+		Payload:             datatypes.JSON(`{}`),
+		DependsOn:           []uint{stale.ID},
+		OnDependencyFailure: config.DependencyFailureSkip,
+	}
+	require.NoError(t, repo.Create(ctx, skipping))
+
+	acquired, err := repo.AcquireNext(ctx, "default", 1, time.Minute)
+	require.NoError(t, err)
+	assert.Nil(t, acquired)
+
+	got, err := repo.Get(ctx, failing.ID)
+	require.NoError(t, err)
+	assert.Equal(t, config.JobStatusFailed, got.Status)
+	got, err = repo.Get(ctx, skipping.ID)
+	require.NoError(t, err)
+	assert.Equal(t, config.JobStatusSkipped, got.Status)
+}
+
+func TestJobRepository_QueueTTL_StartsWhenQueued(t *testing.T) {
+	db, ctx := setupTestDB(t)
+	defer closeTestDB(db)
+
+	repo := postgres.NewJobRepository(db)
+	parent := createRunning(t, repo)
+	// The service sets both from the queue TTL.
+	expiresAt := time.Now().Add(time.Minute)
+	child := &models.Job{
+		Queue:      "webhooks",
+		Payload:    datatypes.JSON(`{}`),
+		DependsOn:  []uint{parent.ID},
+		ExpiresAt:  &expiresAt,
+		TTLSeconds: 60,
+	}
+	require.NoError(t, repo.Create(ctx, child))
+
+	got, err := repo.Get(ctx, child.ID)
+	require.NoError(t, err)
+	assert.Equal(t, config.JobStatusWaiting, got.Status)
+	assert.Nil(t, got.ExpiresAt)
+
+	// Waiting longer than the TTL does not expire the job.
+	require.NoError(t, db.Exec("UPDATE jobs SET available_at = ? WHERE id = ?", time.Now().Add(-time.Hour), child.ID).Error)
+	require.NoError(t, repo.MarkCompleted(ctx, parent.ID, 1, datatypes.JSON(`{}`)))
+
+	got, err = repo.Get(ctx, child.ID)
+	require.NoError(t, err)
+	assert.Equal(t, config.JobStatusQueued, got.Status)
+	require.NotNil(t, got.ExpiresAt)
+	assert.WithinDuration(t, time.Now().Add(time.Minute), *got.ExpiresAt, 10*time.Second)
+
+	acquired, err := repo.AcquireNext(ctx, "webhooks", 1, time.Minute)
+	require.NoError(t, err)
+	require.NotNil(t, acquired)
+	assert.Equal(t, child.ID, acquired.ID)
+}
+
+func TestJobRepository_QueueStats(t *testing.T) {
+	db, ctx := setupTestDB(t)
+	defer closeTestDB(db)
+
+	repo := postgres.NewJobRepository(db)
+	require.NoError(t, repo.Create(ctx, expiring(time.Now().Add(-time.Minute))))
+	require.NoError(t, repo.Create(ctx, expiring(time.Now().Add(-time.Minute))))
+	require.NoError(t, repo.Create(ctx, &models.Job{Queue: "email", Payload: datatypes.JSON(`{}`)}))
+
+	_, err := repo.AcquireNext(ctx, "default", 1, time.Minute)
+	require.NoError(t, err)
+
+	stats, err := repo.QueueStats(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]map[config.JobStatus]int64{
+		"default": {config.JobStatusExpired: 2},
+		"email":   {config.JobStatusQueued: 1},
+	}, stats)
+}
@@ -0,0 +1,73 @@
+package integration
+
+import (
+	"testing"
+	"time"
+
+	"github.com/joshu-sajeev/goqueue/internal/models"
+	"github.com/joshu-sajeev/goqueue/internal/storage/postgres"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/datatypes"
+)
+
+func createGrouped(t *testing.T, repo *postgres.JobRepository, group string) *models.Job {
+	t.Helper()
+	j := &models.Job{Queue: "webhooks", Payload: datatypes.JSON(`{}`), MaxRetries: 3}
+	if group != "" {
+		j.GroupKey = &group
+	}
+	require.NoError(t, repo.Create(t.Context(), j))
+	return j
+}
+
+func TestJobRepository_AcquireNext_GroupKey(t *testing.T) {
+	db, ctx := setupTestDB(t)
+	defer closeTestDB(db)
+
+	repo := postgres.NewJobRepository(db)
+	a1 := createGrouped(t, repo, "customer-1")
+	a2 := createGrouped(t, repo, "customer-1")
+	b1 := createGrouped(t, repo, "customer-2")
+	free := createGrouped(t, repo, "")
+
+	acquire := func() uint {
+		t.Helper()
+		j, err := repo.AcquireNext(ctx, "webhooks", 1, time.Minute)
+		require.NoError(t, err)
+		if j == nil {
+			return 0
+		}
+		return j.ID
+	}
+
+	// a2 waits behind a1; the other group and ungrouped jobs run in
+	// parallel.
+	assert.Equal(t, a1.ID, acquire())
+	assert.Equal(t, b1.ID, acquire())
+	assert.Equal(t, free.ID, acquire())
+	assert.Zero(t, acquire())
+
+	// A retried job keeps its place at the head of its group.
+	require.NoError(t, repo.RetryLater(ctx, a1.ID, 1, time.Now().Add(-time.Second), "boom"))
+	assert.Equal(t, a1.ID, acquire())
+	assert.Zero(t, acquire())
+
+	require.NoError(t, repo.MarkFailed(ctx, a1.ID, 1, "boom"))
+	assert.Equal(t, a2.ID, acquire())
+}
+
+func TestJobRepository_AcquireNext_GroupKeyPerQueue(t *testing.T) {
+	db, ctx := setupTestDB(t)
+	defer closeTestDB(db)
+
+	repo := postgres.NewJobRepository(db)
+	group := "customer-1"
+	require.NoError(t, repo.Create(ctx, &models.Job{Queue: "email", Payload: datatypes.JSON(`{}`), GroupKey: &group}))
+	j := createGrouped(t, repo, group)
+
+	got, err := repo.AcquireNext(ctx, "webhooks", 1, time.Minute)
+	require.NoError(t, err)
+	require.NotNil(t, got)
+	assert.Equal(t, j.ID, got.ID)
+}
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/joshu-sajeev/goqueue/internal/config"
+	"github.com/joshu-sajeev/goqueue/internal/job"
 	"github.com/joshu-sajeev/goqueue/internal/models"
 	"github.com/joshu-sajeev/goqueue/internal/storage/postgres"
 	"github.com/stretchr/testify/assert"
@@ -766,8 +767,9 @@ func TestJobRepository_RetryLater(t *testing.T) {
 	availableAt := now.Add(time.Minute)
 
 	tests := []struct {
-		name  string
-		setup func(db *gorm.DB) uint
+		name    string
+		setup   func(db *gorm.DB) uint
+		wantErr error
 	}{
 		{
 			name: "retry existing job",
@@ -783,10 +785,25 @@ func TestJobRepository_RetryLater(t *testing.T) {
 			},
 		},
 		{
-			name: "retry non-existent job (idempotent)",
+			name: "job held by another worker",
+			setup: func(db *gorm.DB) uint {
+				job := models.Job{
+					Queue:    "default",
+					Status:   config.JobStatusRunning,
+					LockedAt: &now,
+					LockedBy: ptrUint(2),
+				}
+				require.NoError(t, db.Create(&job).Error)
+				return job.ID
+			},
+			wantErr: job.ErrLeaseLost,
+		},
+		{
+			name: "retry non-existent job",
 			setup: func(db *gorm.DB) uint {
 				return 9999
 			},
+			wantErr: job.ErrLeaseLost,
 		},
 	}
 
@@ -798,7 +815,11 @@ func TestJobRepository_RetryLater(t *testing.T) {
 			repo := postgres.NewJobRepository(db)
 			id := tt.setup(db)
 
-			err := repo.RetryLater(ctx, id, availableAt)
+			err := repo.RetryLater(ctx, id, 1, availableAt, "handler failed")
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
 			require.NoError(t, err)
 
 			var job models.Job
@@ -807,6 +828,105 @@ func TestJobRepository_RetryLater(t *testing.T) {
 				assert.Nil(t, job.LockedBy)
 				assert.Nil(t, job.LockedAt)
 				assert.WithinDuration(t, availableAt, job.AvailableAt, time.Millisecond)
+				assert.Equal(t, "handler failed", job.Error)
+				assert.Equal(t, 1, job.Attempts)
+			}
+		})
+	}
+}
+
+func TestJobRepository_Snooze(t *testing.T) {
+	now := time.Now()
+	availableAt := now.Add(time.Minute)
+
+	db, ctx := setupTestDB(t)
+	defer closeTestDB(db)
+
+	repo := postgres.NewJobRepository(db)
+
+	job := models.Job{
+		Queue:    "default",
+		Status:   config.JobStatusRunning,
+		Attempts: 2,
+		Error:    "previous failure",
+		LockedAt: &now,
+		LockedBy: ptrUint(1),
+	}
+	require.NoError(t, db.Create(&job).Error)
+
+	require.NoError(t, repo.Snooze(ctx, job.ID, 1, availableAt))
+
+	var got models.Job
+	require.NoError(t, db.First(&got, job.ID).Error)
+	assert.Equal(t, config.JobStatusQueued, got.Status)
+	assert.Equal(t, 2, got.Attempts)
+	assert.Equal(t, "previous failure", got.Error)
+	assert.Nil(t, got.LockedBy)
+	assert.Nil(t, got.LockedAt)
+	assert.WithinDuration(t, availableAt, got.AvailableAt, time.Millisecond)
+}
+
+func TestJobRepository_MarkFailed(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name    string
+		setup   func(db *gorm.DB) uint
+		wantErr error
+	}{
+		{
+			name: "fail running job",
+			setup: func(db *gorm.DB) uint {
+				job := models.Job{
+					Queue:    "default",
+					Status:   config.JobStatusRunning,
+					LockedAt: &now,
+					LockedBy: ptrUint(1),
+				}
+				require.NoError(t, db.Create(&job).Error)
+				return job.ID
+			},
+		},
+		{
+			name: "fail finished job",
+			setup: func(db *gorm.DB) uint {
+				job := models.Job{Queue: "default", Status: config.JobStatusCompleted}
+				require.NoError(t, db.Create(&job).Error)
+				return job.ID
+			},
+			wantErr: job.ErrLeaseLost,
+		},
+		{
+			name: "fail non-existent job",
+			setup: func(db *gorm.DB) uint {
+				return 9999
+			},
+			wantErr: job.ErrLeaseLost,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, ctx := setupTestDB(t)
+			defer closeTestDB(db)
+
+			repo := postgres.NewJobRepository(db)
+			id := tt.setup(db)
+
+			err := repo.MarkFailed(ctx, id, 1, "unmarshal email payload")
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+
+			var job models.Job
+			if db.First(&job, id).Error == nil {
+				assert.Equal(t, config.JobStatusFailed, job.Status)
+				assert.Equal(t, "unmarshal email payload", job.Error)
+				assert.Equal(t, 1, job.Attempts)
+				assert.Nil(t, job.LockedBy)
+				assert.Nil(t, job.LockedAt)
 			}
 		})
 	}
@@ -874,6 +994,66 @@ func TestJobRepository_ListStuckJobs(t *testing.T) {
 	}
 }
 
+func TestJobRepository_ListOrphanedJobs(t *testing.T) {
+	db, ctx := setupTestDB(t)
+	defer closeTestDB(db)
+
+	now := time.Now()
+	alive := models.Worker{Hostname: "alive", PID: 1, LastHeartbeatAt: now, StartedAt: now}
+	dead := models.Worker{Hostname: "dead", PID: 2, LastHeartbeatAt: now.Add(-time.Hour), StartedAt: now.Add(-time.Hour)}
+	require.NoError(t, db.Create(&alive).Error)
+	require.NoError(t, db.Create(&dead).Error)
+
+	expired := now.Add(-time.Second)
+	leased := now.Add(time.Hour)
+	jobs := []models.Job{
+		{Queue: "default", Status: config.JobStatusRunning, LockedAt: &expired, LockedBy: ptrUint(alive.ID)},
+		{Queue: "default", Status: config.JobStatusRunning, LockedAt: &expired, LockedBy: ptrUint(dead.ID)},
+		{Queue: "default", Status: config.JobStatusRunning, LockedAt: &expired, LockedBy: ptrUint(dead.ID + 1000)},
+		{Queue: "default", Status: config.JobStatusQueued},
+		// The lease has not expired yet, so the job is kept.
+		{Queue: "default", Status: config.JobStatusRunning, LockedAt: &leased, LockedBy: ptrUint(dead.ID)},
+	}
+	for i := range jobs {
+		require.NoError(t, db.Create(&jobs[i]).Error)
+	}
+
+	repo := postgres.NewJobRepository(db)
+	orphaned, err := repo.ListOrphanedJobs(ctx, time.Minute)
+	require.NoError(t, err)
+
+	var ids []uint
+	for _, j := range orphaned {
+		ids = append(ids, j.ID)
+	}
+	assert.ElementsMatch(t, []uint{jobs[1].ID, jobs[2].ID}, ids)
+}
+
+func TestJobRepository_ExtendLock(t *testing.T) {
+	db, ctx := setupTestDB(t)
+	defer closeTestDB(db)
+
+	now := time.Now()
+	job := models.Job{Queue: "default", Status: config.JobStatusRunning, LockedAt: &now, LockedBy: ptrUint(7)}
+	require.NoError(t, db.Create(&job).Error)
+
+	repo := postgres.NewJobRepository(db)
+	until := now.Add(10 * time.Minute)
+
+	ok, err := repo.ExtendLock(ctx, job.ID, 8, until)
+	require.NoError(t, err)
+	assert.False(t, ok, "other workers cannot extend the lock")
+
+	ok, err = repo.ExtendLock(ctx, job.ID, 7, until)
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	var got models.Job
+	require.NoError(t, db.First(&got, job.ID).Error)
+	require.NotNil(t, got.LockedAt)
+	assert.WithinDuration(t, until, *got.LockedAt, time.Millisecond)
+}
+
 func TestJobRepository_MarkCompleted(t *testing.T) {
 	now := time.Now()
 
@@ -903,11 +1083,30 @@ func TestJobRepository_MarkCompleted(t *testing.T) {
 			wantErr: false,
 		},
 		{
-			name:    "job does not exist",
-			jobID:   99999,
-			result:  datatypes.JSON([]byte(`{"status":"ok"}`)),
-			setup:   func(db *gorm.DB) uint { return 99999 },
-			wantErr: false,
+			name:   "job completed by a stale worker",
+			result: datatypes.JSON([]byte(`{"status":"ok"}`)),
+			setup: func(db *gorm.DB) uint {
+				lockedAt := now
+				job := models.Job{
+					Queue:       "default",
+					Status:      config.JobStatusRunning,
+					AvailableAt: now.Add(-time.Minute),
+					LockedAt:    &lockedAt,
+					LockedBy:    ptrUint(43),
+				}
+				require.NoError(t, db.Create(&job).Error)
+				return job.ID
+			},
+			wantErr:     true,
+			errContains: job.ErrLeaseLost.Error(),
+		},
+		{
+			name:        "job does not exist",
+			jobID:       99999,
+			result:      datatypes.JSON([]byte(`{"status":"ok"}`)),
+			setup:       func(db *gorm.DB) uint { return 99999 },
+			wantErr:     true,
+			errContains: job.ErrLeaseLost.Error(),
 		},
 	}
 
@@ -925,7 +1124,7 @@ func TestJobRepository_MarkCompleted(t *testing.T) {
 				jobID = tt.jobID
 			}
 
-			err := repo.MarkCompleted(ctx, jobID, tt.result)
+			err := repo.MarkCompleted(ctx, jobID, 42, tt.result)
 
 			if tt.wantErr {
 				require.Error(t, err)
@@ -951,3 +1150,67 @@ func TestJobRepository_MarkCompleted(t *testing.T) {
 		})
 	}
 }
+
+func TestJobRepository_CompleteWithChildren(t *testing.T) {
+	db, ctx := setupTestDB(t)
+	defer closeTestDB(db)
+
+	repo := postgres.NewJobRepository(db)
+	now := time.Now()
+	parent := models.Job{
+		Queue:       "default",
+		Status:      config.JobStatusRunning,
+		AvailableAt: now,
+		LockedAt:    &now,
+		LockedBy:    ptrUint(1),
+	}
+	require.NoError(t, db.Create(&parent).Error)
+
+	children := []*models.Job{
+		{Queue: "email", Payload: datatypes.JSON(`{"to":"a@example.com"}`), MaxRetries: 3},
+		{Queue: "webhooks", Payload: datatypes.JSON(`{}`), MaxRetries: 3},
+	}
+	require.NoError(t, repo.CompleteWithChildren(ctx, parent.ID, 1, datatypes.JSON(`{"ok":true}`), children))
+
+	got, err := repo.Get(ctx, parent.ID)
+	require.NoError(t, err)
+	assert.Equal(t, config.JobStatusCompleted, got.Status)
+	assert.Nil(t, got.LockedBy)
+
+	var stored []models.Job
+	require.NoError(t, db.Where("parent_id = ?", parent.ID).Order("id").Find(&stored).Error)
+	require.Len(t, stored, 2)
+	for i, child := range stored {
+		assert.Equal(t, children[i].Queue, child.Queue)
+		assert.Equal(t, config.JobStatusQueued, child.Status)
+		assert.Equal(t, parent.ID, *child.ParentID)
+	}
+}
+
+func TestJobRepository_CompleteWithChildren_RollsBack(t *testing.T) {
+	db, ctx := setupTestDB(t)
+	defer closeTestDB(db)
+
+	repo := postgres.NewJobRepository(db)
+	now := time.Now()
+	parent := models.Job{
+		Queue:       "default",
+		Status:      config.JobStatusRunning,
+		AvailableAt: now,
+		LockedAt:    &now,
+		LockedBy:    ptrUint(1),
+	}
+	require.NoError(t, db.Create(&parent).Error)
+
+	// The invalid JSON payload makes the child insert fail.
+	children := []*models.Job{{Queue: "email", Payload: datatypes.JSON(`{`)}}
+	require.Error(t, repo.CompleteWithChildren(ctx, parent.ID, 1, datatypes.JSON(`{}`), children))
+
+	got, err := repo.Get(ctx, parent.ID)
+	require.NoError(t, err)
+	assert.Equal(t, config.JobStatusRunning, got.Status)
+
+	var count int64
+	require.NoError(t, db.Model(&models.Job{}).Where("parent_id = ?", parent.ID).Count(&count).Error)
+	assert.Zero(t, count)
+}
@@ -0,0 +1,74 @@
+package integration
+
+import (
+	"testing"
+	"time"
+
+	"github.com/joshu-sajeev/goqueue/internal/config"
+	"github.com/joshu-sajeev/goqueue/internal/models"
+	"github.com/joshu-sajeev/goqueue/internal/storage/postgres"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// finishedJob creates a job in queue with status, last updated age ago.
+func finishedJob(t *testing.T, db *gorm.DB, repo *postgres.JobRepository, queue string, status config.JobStatus, age time.Duration) *models.Job {
+	t.Helper()
+	j := &models.Job{Queue: queue, Payload: datatypes.JSON(`{}`)}
+	require.NoError(t, repo.Create(t.Context(), j))
+	require.NoError(t, db.Exec("UPDATE jobs SET status = ?, updated_at = ? WHERE id = ?",
+		status, time.Now().Add(-age), j.ID).Error)
+	return j
+}
+
+func TestJobRepository_PruneJobs(t *testing.T) {
+	db, ctx := setupTestDB(t)
+	defer closeTestDB(db)
+
+	repo := postgres.NewJobRepository(db)
+	for range 5 {
+		finishedJob(t, db, repo, "email", config.JobStatusCompleted, 48*time.Hour)
+	}
+	recent := finishedJob(t, db, repo, "email", config.JobStatusCompleted, time.Hour)
+	failed := finishedJob(t, db, repo, "email", config.JobStatusFailed, 48*time.Hour)
+	other := finishedJob(t, db, repo, "webhooks", config.JobStatusCompleted, 48*time.Hour)
+
+	cutoff := time.Now().Add(-24 * time.Hour)
+	n, err := repo.CountPrunable(ctx, "email", config.JobStatusCompleted, cutoff)
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), n)
+
+	n, err = repo.PruneJobs(ctx, "email", config.JobStatusCompleted, cutoff, 3)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), n)
+	n, err = repo.PruneJobs(ctx, "email", config.JobStatusCompleted, cutoff, 3)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), n)
+	n, err = repo.PruneJobs(ctx, "email", config.JobStatusCompleted, cutoff, 3)
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), n)
+
+	var left []uint
+	require.NoError(t, db.Model(&models.Job{}).Order("id").Pluck("id", &left).Error)
+	assert.Equal(t, []uint{recent.ID, failed.ID, other.ID}, left)
+}
+
+func TestJobRepository_PruneJobs_KeepsDependenciesOfWaitingJobs(t *testing.T) {
+	db, ctx := setupTestDB(t)
+	defer closeTestDB(db)
+
+	repo := postgres.NewJobRepository(db)
+	a := createRunning(t, repo)
+	b := createRunning(t, repo)
+	child := &models.Job{Queue: "webhooks", Payload: datatypes.JSON(`{}`), DependsOn: []uint{a.ID, b.ID}, PassResults: true}
+	require.NoError(t, repo.Create(ctx, child))
+
+	require.NoError(t, repo.MarkCompleted(ctx, a.ID, 1, datatypes.JSON(`{"rows":1}`)))
+	require.NoError(t, db.Exec("UPDATE jobs SET updated_at = ? WHERE id = ?", time.Now().Add(-48*time.Hour), a.ID).Error)
+
+	n, err := repo.PruneJobs(ctx, "default", config.JobStatusCompleted, time.Now().Add(-24*time.Hour), 10)
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), n)
+}
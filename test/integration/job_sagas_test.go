@@ -0,0 +1,227 @@
+package integration
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/joshu-sajeev/goqueue/internal/config"
+	"github.com/joshu-sajeev/goqueue/internal/job"
+	"github.com/joshu-sajeev/goqueue/internal/models"
+	"github.com/joshu-sajeev/goqueue/internal/storage/postgres"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// createSaga creates a saga of n steps on the "saga" queue. Every step but
+// the last has a compensation on the "undo" queue.
+func createSaga(t *testing.T, repo *postgres.JobRepository, n int) (*models.Saga, []*models.SagaStep) {
+	t.Helper()
+	steps := make([]*models.SagaStep, n)
+	for i := range steps {
+		steps[i] = &models.SagaStep{Job: &models.Job{Queue: "saga", Payload: datatypes.JSON(`{}`), MaxRetries: 3}}
+		if i < n-1 {
+			steps[i].CompensationQueue = "undo"
+			steps[i].CompensationPayload = datatypes.JSON(`{"undo":true}`)
+			steps[i].CompensationMaxRetries = 3
+		}
+	}
+	saga := &models.Saga{}
+	require.NoError(t, repo.CreateSaga(t.Context(), saga, steps))
+	return saga, steps
+}
+
+// runNext acquires the next job on queue and checks that it is want.
+func runNext(t *testing.T, repo *postgres.JobRepository, queue string, want uint) {
+	t.Helper()
+	j, err := repo.AcquireNext(t.Context(), queue, 1, time.Minute)
+	require.NoError(t, err)
+	require.NotNil(t, j)
+	require.Equal(t, want, j.ID)
+}
+
+func TestJobRepository_Saga_Completes(t *testing.T) {
+	db, ctx := setupTestDB(t)
+	defer closeTestDB(db)
+
+	repo := postgres.NewJobRepository(db)
+	saga, steps := createSaga(t, repo, 2)
+	assert.Equal(t, models.SagaStatusRunning, saga.Status)
+	assert.Equal(t, config.JobStatusQueued, steps[0].Job.Status)
+	assert.Equal(t, config.JobStatusWaiting, steps[1].Job.Status)
+
+	runNext(t, repo, "saga", steps[0].JobID)
+	require.NoError(t, repo.MarkCompleted(ctx, steps[0].JobID, 1, datatypes.JSON(`{}`)))
+	got, _, err := repo.GetSaga(ctx, saga.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 2, got.CurrentStep)
+
+	runNext(t, repo, "saga", steps[1].JobID)
+	require.NoError(t, repo.MarkCompleted(ctx, steps[1].JobID, 1, datatypes.JSON(`{}`)))
+	got, gotSteps, err := repo.GetSaga(ctx, saga.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.SagaStatusCompleted, got.Status)
+	assert.NotNil(t, got.FinishedAt)
+	for _, s := range gotSteps {
+		assert.Equal(t, config.JobStatusCompleted, s.JobStatus)
+		assert.Nil(t, s.CompensationJobID)
+	}
+}
+
+func TestJobRepository_Saga_CompensatesInReverse(t *testing.T) {
+	db, ctx := setupTestDB(t)
+	defer closeTestDB(db)
+
+	repo := postgres.NewJobRepository(db)
+	saga, steps := createSaga(t, repo, 4)
+
+	for i, result := range []string{`{"charge":"ch_1"}`, `{"charge":"ch_2"}`} {
+		runNext(t, repo, "saga", steps[i].JobID)
+		require.NoError(t, repo.MarkCompleted(ctx, steps[i].JobID, 1, datatypes.JSON(result)))
+	}
+	runNext(t, repo, "saga", steps[2].JobID)
+	require.NoError(t, repo.MarkFailed(ctx, steps[2].JobID, 1, "declined"))
+
+	got, gotSteps, err := repo.GetSaga(ctx, saga.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.SagaStatusCompensating, got.Status)
+	require.NotNil(t, got.FailedStep)
+	assert.Equal(t, 3, *got.FailedStep)
+	assert.Equal(t, "step 3 failed: declined", got.Error)
+	assert.Equal(t, config.JobStatusSkipped, gotSteps[3].JobStatus)
+	assert.Nil(t, gotSteps[2].CompensationJobID, "the failed step is not compensated")
+	require.NotNil(t, gotSteps[0].CompensationJobID)
+	require.NotNil(t, gotSteps[1].CompensationJobID)
+	assert.Equal(t, config.JobStatusWaiting, gotSteps[0].CompensationStatus)
+	assert.Equal(t, config.JobStatusQueued, gotSteps[1].CompensationStatus)
+
+	// Step 2 is compensated first, with its result in the payload.
+	undo2 := *gotSteps[1].CompensationJobID
+	compensation, err := repo.Get(ctx, undo2)
+	require.NoError(t, err)
+	var payload struct {
+		Undo       bool            `json:"undo"`
+		StepResult json.RawMessage `json:"step_result"`
+	}
+	require.NoError(t, json.Unmarshal(compensation.Payload, &payload))
+	assert.True(t, payload.Undo)
+	assert.JSONEq(t, `{"charge":"ch_2"}`, string(payload.StepResult))
+
+	runNext(t, repo, "undo", undo2)
+	require.NoError(t, repo.MarkCompleted(ctx, undo2, 1, datatypes.JSON(`{}`)))
+	got, _, err = repo.GetSaga(ctx, saga.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.SagaStatusCompensating, got.Status)
+
+	undo1 := *gotSteps[0].CompensationJobID
+	runNext(t, repo, "undo", undo1)
+	require.NoError(t, repo.MarkCompleted(ctx, undo1, 1, datatypes.JSON(`{}`)))
+	got, _, err = repo.GetSaga(ctx, saga.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.SagaStatusCompensated, got.Status)
+	assert.NotNil(t, got.FinishedAt)
+}
+
+func TestJobRepository_Saga_CompensationFails(t *testing.T) {
+	db, ctx := setupTestDB(t)
+	defer closeTestDB(db)
+
+	repo := postgres.NewJobRepository(db)
+	saga, steps := createSaga(t, repo, 3)
+
+	runNext(t, repo, "saga", steps[0].JobID)
+	require.NoError(t, repo.MarkCompleted(ctx, steps[0].JobID, 1, datatypes.JSON(`{}`)))
+	runNext(t, repo, "saga", steps[1].JobID)
+	require.NoError(t, repo.MarkCompleted(ctx, steps[1].JobID, 1, datatypes.JSON(`{}`)))
+	runNext(t, repo, "saga", steps[2].JobID)
+	require.NoError(t, repo.MarkFailed(ctx, steps[2].JobID, 1, "declined"))
+
+	_, gotSteps, err := repo.GetSaga(ctx, saga.ID)
+	require.NoError(t, err)
+	undo2 := *gotSteps[1].CompensationJobID
+	runNext(t, repo, "undo", undo2)
+	require.NoError(t, repo.MarkFailed(ctx, undo2, 1, "refund failed"))
+
+	got, gotSteps, err := repo.GetSaga(ctx, saga.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.SagaStatusCompensationFailed, got.Status)
+	assert.Equal(t, "step 3 failed: declined; compensation for step 2 failed: refund failed", got.Error)
+	assert.Equal(t, config.JobStatusSkipped, gotSteps[0].CompensationStatus)
+}
+
+func TestJobRepository_Saga_FirstStepFails(t *testing.T) {
+	db, ctx := setupTestDB(t)
+	defer closeTestDB(db)
+
+	repo := postgres.NewJobRepository(db)
+	saga, steps := createSaga(t, repo, 2)
+
+	runNext(t, repo, "saga", steps[0].JobID)
+	require.NoError(t, repo.MarkFailed(ctx, steps[0].JobID, 1, "declined"))
+
+	got, _, err := repo.GetSaga(ctx, saga.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.SagaStatusCompensated, got.Status)
+	assert.NotNil(t, got.FinishedAt)
+}
+
+// rerun puts a finished job back in the running state under worker 1, as
+// if it had been reclaimed and picked up again.
+func rerun(t *testing.T, db *gorm.DB, id uint) {
+	t.Helper()
+	require.NoError(t, db.Exec("UPDATE jobs SET status = ?, locked_by = 1, locked_at = now() WHERE id = ?",
+		config.JobStatusRunning, id).Error)
+}
+
+func TestJobRepository_Saga_IgnoresRepeatedEvents(t *testing.T) {
+	db, ctx := setupTestDB(t)
+	defer closeTestDB(db)
+
+	repo := postgres.NewJobRepository(db)
+	saga, steps := createSaga(t, repo, 3)
+
+	runNext(t, repo, "saga", steps[0].JobID)
+	require.NoError(t, repo.MarkCompleted(ctx, steps[0].JobID, 1, datatypes.JSON(`{}`)))
+	runNext(t, repo, "saga", steps[1].JobID)
+	require.NoError(t, repo.MarkFailed(ctx, steps[1].JobID, 1, "declined"))
+
+	_, gotSteps, err := repo.GetSaga(ctx, saga.ID)
+	require.NoError(t, err)
+	require.NotNil(t, gotSteps[0].CompensationJobID)
+	undo := *gotSteps[0].CompensationJobID
+
+	// The failed step fails again: no second compensation is created.
+	rerun(t, db, steps[1].JobID)
+	require.NoError(t, repo.MarkFailed(ctx, steps[1].JobID, 1, "declined"))
+	var compensations int64
+	require.NoError(t, db.Model(&models.Job{}).Where("queue = ?", "undo").Count(&compensations).Error)
+	assert.Equal(t, int64(1), compensations)
+
+	// A completion that arrives late does not complete the saga.
+	rerun(t, db, steps[1].JobID)
+	require.NoError(t, repo.MarkCompleted(ctx, steps[1].JobID, 1, datatypes.JSON(`{}`)))
+	got, gotSteps, err := repo.GetSaga(ctx, saga.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.SagaStatusCompensating, got.Status)
+	assert.Equal(t, 2, got.CurrentStep)
+	assert.Equal(t, undo, *gotSteps[0].CompensationJobID)
+
+	// Once compensated, a repeated compensation event changes nothing.
+	runNext(t, repo, "undo", undo)
+	require.NoError(t, repo.MarkCompleted(ctx, undo, 1, datatypes.JSON(`{}`)))
+	rerun(t, db, undo)
+	require.NoError(t, repo.MarkFailed(ctx, undo, 1, "refund failed"))
+	got, _, err = repo.GetSaga(ctx, saga.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.SagaStatusCompensated, got.Status)
+}
+
+func TestJobRepository_GetSaga_NotFound(t *testing.T) {
+	db, ctx := setupTestDB(t)
+	defer closeTestDB(db)
+
+	_, _, err := postgres.NewJobRepository(db).GetSaga(ctx, 999999)
+	assert.ErrorIs(t, err, job.ErrSagaNotFound)
+}
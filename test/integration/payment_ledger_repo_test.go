@@ -0,0 +1,70 @@
+package integration
+
+import (
+	"testing"
+
+	"github.com/joshu-sajeev/goqueue/internal/models"
+	"github.com/joshu-sajeev/goqueue/internal/storage/postgres"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPaymentLedgerRepository_BeginIsIdempotent(t *testing.T) {
+	db, ctx := setupTestDB(t)
+	defer closeTestDB(db)
+
+	repo := postgres.NewPaymentLedgerRepository(db)
+	newTxn := func() *models.PaymentTransaction {
+		return &models.PaymentTransaction{
+			PaymentID: "pay_123",
+			UserID:    "user_1",
+			Amount:    99.99,
+			Currency:  "USD",
+			Method:    "card",
+			Provider:  "fake",
+		}
+	}
+
+	txn, created, err := repo.Begin(ctx, newTxn())
+	require.NoError(t, err)
+	assert.True(t, created)
+	assert.Equal(t, models.PaymentStatusPending, txn.Status)
+
+	done, err := repo.Complete(ctx, "pay_123", "txn_abc")
+	require.NoError(t, err)
+	assert.Equal(t, models.PaymentStatusCompleted, done.Status)
+	assert.Equal(t, "txn_abc", done.TransactionID)
+
+	again, created, err := repo.Begin(ctx, newTxn())
+	require.NoError(t, err)
+	assert.False(t, created)
+	assert.Equal(t, txn.ID, again.ID)
+	assert.Equal(t, models.PaymentStatusCompleted, again.Status)
+	assert.Equal(t, "txn_abc", again.TransactionID)
+	assert.True(t, again.Matches("user_1", 99.99, "USD"))
+
+	var count int64
+	require.NoError(t, db.Model(&models.PaymentTransaction{}).Count(&count).Error)
+	assert.Equal(t, int64(1), count)
+}
+
+func TestPaymentLedgerRepository_Fail(t *testing.T) {
+	db, ctx := setupTestDB(t)
+	defer closeTestDB(db)
+
+	repo := postgres.NewPaymentLedgerRepository(db)
+	_, _, err := repo.Begin(ctx, &models.PaymentTransaction{
+		PaymentID: "pay_456",
+		UserID:    "user_1",
+		Amount:    10,
+		Currency:  "EUR",
+		Method:    "upi",
+		Provider:  "fake",
+	})
+	require.NoError(t, err)
+
+	txn, err := repo.Fail(ctx, "pay_456", "card declined")
+	require.NoError(t, err)
+	assert.Equal(t, models.PaymentStatusFailed, txn.Status)
+	assert.Equal(t, "card declined", txn.Error)
+}
@@ -0,0 +1,94 @@
+package integration
+
+import (
+	"testing"
+	"time"
+
+	"github.com/joshu-sajeev/goqueue/internal/models"
+	"github.com/joshu-sajeev/goqueue/internal/storage/postgres"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+func TestWorkerRepository_Lifecycle(t *testing.T) {
+	db, ctx := setupTestDB(t)
+	defer closeTestDB(db)
+
+	repo := postgres.NewWorkerRepository(db)
+
+	w := &models.Worker{
+		Hostname: "host-a",
+		PID:      1234,
+		Queues:   datatypes.NewJSONSlice([]string{"email", "payment"}),
+		Version:  "v1.0.0",
+	}
+	require.NoError(t, repo.Register(ctx, w))
+	assert.NotZero(t, w.ID)
+
+	stale := time.Now().Add(-time.Hour)
+	require.NoError(t, db.Model(&models.Worker{}).Where("id = ?", w.ID).Update("last_heartbeat_at", stale).Error)
+	require.NoError(t, repo.Heartbeat(ctx, w.ID))
+
+	workers, err := repo.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, workers, 1)
+	assert.Equal(t, "host-a", workers[0].Hostname)
+	assert.Equal(t, 1234, workers[0].PID)
+	assert.Equal(t, []string{"email", "payment"}, []string(workers[0].Queues))
+	assert.WithinDuration(t, time.Now(), workers[0].LastHeartbeatAt, 5*time.Second)
+
+	require.NoError(t, repo.Deregister(ctx, w.ID))
+
+	workers, err = repo.List(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, workers)
+}
+
+func TestWorkerRepository_HeartbeatUnknownWorker(t *testing.T) {
+	db, ctx := setupTestDB(t)
+	defer closeTestDB(db)
+
+	repo := postgres.NewWorkerRepository(db)
+
+	err := repo.Heartbeat(ctx, 9999)
+	require.Error(t, err)
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+}
+
+func TestWorkerRepository_RemoveDead(t *testing.T) {
+	db, ctx := setupTestDB(t)
+	defer closeTestDB(db)
+
+	repo := postgres.NewWorkerRepository(db)
+	jobRepo := postgres.NewJobRepository(db)
+	register := func(age time.Duration) *models.Worker {
+		w := &models.Worker{Hostname: "host", Queues: datatypes.NewJSONSlice([]string{"default"})}
+		require.NoError(t, repo.Register(ctx, w))
+		require.NoError(t, db.Model(&models.Worker{}).Where("id = ?", w.ID).Update("last_heartbeat_at", time.Now().Add(-age)).Error)
+		return w
+	}
+	alive := register(0)
+	dead := register(time.Hour)
+	busy := register(time.Hour)
+
+	j := &models.Job{Queue: "default", Payload: datatypes.JSON(`{}`)}
+	require.NoError(t, jobRepo.Create(ctx, j))
+	acquired, err := jobRepo.AcquireNext(ctx, "default", busy.ID, time.Minute)
+	require.NoError(t, err)
+	require.NotNil(t, acquired)
+
+	n, err := repo.RemoveDead(ctx, 10*time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), n)
+
+	workers, err := repo.List(ctx)
+	require.NoError(t, err)
+	var ids []uint
+	for _, w := range workers {
+		ids = append(ids, w.ID)
+	}
+	assert.ElementsMatch(t, []uint{alive.ID, busy.ID}, ids)
+	assert.NotContains(t, ids, dead.ID)
+}